@@ -0,0 +1,94 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package tcvdbotel traces the requests of tcvectordb.Client by OpenTelemetry, it is a separate module
+// so that the users who don't trace are not depending on OpenTelemetry.
+//
+//	cli, err := tcvectordb.NewClient(url, username, key, &tcvectordb.ClientOption{
+//		Tracer: tcvdbotel.NewTracer(nil, nil),
+//	})
+package tcvdbotel
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/tencent/vectordatabase-sdk-go/tcvdbotel"
+
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracer returns the tcvectordb.Tracer starting a client span for each request, named by the api path,
+// and injecting the trace headers into the request. The global TracerProvider and TextMapPropagator
+// of otel are used if provider or propagator is nil.
+func NewTracer(provider trace.TracerProvider, propagator propagation.TextMapPropagator) tcvectordb.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	return &tracer{
+		tracer:     provider.Tracer(instrumentationName, trace.WithInstrumentationVersion(tcvectordb.SDKVersion)),
+		propagator: propagator,
+	}
+}
+
+func (t *tracer) Start(ctx context.Context, s *tcvectordb.RequestSpan) (context.Context, func(*tcvectordb.RequestSpan)) {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "tencent_vectordb"),
+		attribute.String("db.operation", s.Operation),
+		attribute.String("http.method", s.Method),
+		attribute.Int("vectordb.request_size", s.RequestSize),
+	}
+	if s.Database != "" {
+		attrs = append(attrs, attribute.String("db.name", s.Database))
+	}
+	if s.Collection != "" {
+		attrs = append(attrs, attribute.String("db.vectordb.collection", s.Collection))
+	}
+	ctx, span := t.tracer.Start(ctx, s.Operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	header := make(http.Header)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	for k := range header {
+		ctx = tcvectordb.WithHeader(ctx, k, header.Get(k))
+	}
+	return ctx, func(s *tcvectordb.RequestSpan) {
+		span.SetAttributes(
+			attribute.Int("http.status_code", s.Status),
+			attribute.Int("vectordb.code", int(s.Code)),
+			attribute.Int("vectordb.response_size", s.ResponseSize),
+		)
+		if s.Err != nil {
+			span.RecordError(s.Err)
+			span.SetStatus(codes.Error, s.Err.Error())
+		}
+		span.End()
+	}
+}
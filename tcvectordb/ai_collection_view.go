@@ -21,7 +21,6 @@ package tcvectordb
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
@@ -192,7 +191,7 @@ func (i *implementerCollectionView) DropCollectionView(ctx context.Context, name
 	err = i.Request(ctx, req, res)
 	result = new(DropAICollectionViewResult)
 	if err != nil {
-		if strings.Contains(err.Error(), "not exist") {
+		if isNotExist(err) {
 			return result, nil
 		}
 		return
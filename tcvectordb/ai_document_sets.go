@@ -503,6 +503,9 @@ func (i *implementerAIDocumentSets) loadAndSplitTextCheckParams(param *LoadAndSp
 		}
 		size = fstat.Size()
 	} else {
+		if param.Reader == nil {
+			return 0, nil, errors.New("need param: LocalFilePath or Reader")
+		}
 		bytesBuf := bytes.NewBuffer(nil)
 		written, err := io.Copy(bytesBuf, param.Reader)
 		if err != nil {
@@ -114,7 +114,7 @@ type DeleteRes struct {
 }
 
 type UpdateReq struct {
-	api.Meta       `path:"/ai/documentSet/update" tags:"Document" method:"Post""`
+	api.Meta       `path:"/ai/documentSet/update" tags:"Document" method:"Post"`
 	Database       string                 `json:"database"`
 	CollectionView string                 `json:"collectionView"`
 	Query          UpdateQueryCond        `json:"query"`
@@ -161,7 +161,7 @@ type Credentials struct {
 }
 
 type GetReq struct {
-	api.Meta        `path:"/ai/documentSet/get" tags:"Document" method:"Post""`
+	api.Meta        `path:"/ai/documentSet/get" tags:"Document" method:"Post"`
 	Database        string `json:"database"`
 	CollectionView  string `json:"collectionView"`
 	DocumentSetName string `json:"documentSetName"`
@@ -175,7 +175,7 @@ type GetRes struct {
 }
 
 type GetChunksReq struct {
-	api.Meta        `path:"/ai/documentSet/getChunks" tags:"Document" method:"Post""`
+	api.Meta        `path:"/ai/documentSet/getChunks" tags:"Document" method:"Post"`
 	Database        string `json:"database"`
 	CollectionView  string `json:"collectionView"`
 	DocumentSetName string `json:"documentSetName"`
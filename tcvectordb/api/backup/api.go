@@ -0,0 +1,87 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package backup
+
+import "github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
+
+// Backup the snapshot of collection, the status is creating, ready, restoring or failed
+type Backup struct {
+	BackupId    string `json:"backupId,omitempty"`
+	Database    string `json:"database,omitempty"`
+	Collection  string `json:"collection,omitempty"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Progress    string `json:"progress,omitempty"`
+	Size        uint64 `json:"size,omitempty"`
+	CreateTime  string `json:"createTime,omitempty"`
+}
+
+type CreateReq struct {
+	api.Meta    `path:"/backup/create" tags:"Backup" method:"Post" summary:"创建collection备份"`
+	Database    string `json:"database,omitempty"`
+	Collection  string `json:"collection,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type CreateRes struct {
+	api.CommonRes
+	Backup *Backup `json:"backup,omitempty"`
+}
+
+type ListReq struct {
+	api.Meta   `path:"/backup/list" tags:"Backup" method:"Post" summary:"列出collection的备份"`
+	Database   string `json:"database,omitempty"`
+	Collection string `json:"collection,omitempty"`
+}
+
+type ListRes struct {
+	api.CommonRes
+	Backups []*Backup `json:"backups,omitempty"`
+}
+
+type DescribeReq struct {
+	api.Meta `path:"/backup/describe" tags:"Backup" method:"Post" summary:"查询备份详情"`
+	BackupId string `json:"backupId,omitempty"`
+}
+
+type DescribeRes struct {
+	api.CommonRes
+	Backup *Backup `json:"backup,omitempty"`
+}
+
+type RestoreReq struct {
+	api.Meta   `path:"/backup/restore" tags:"Backup" method:"Post" summary:"从备份恢复collection"`
+	BackupId   string `json:"backupId,omitempty"`
+	Database   string `json:"database,omitempty"`
+	Collection string `json:"collection,omitempty"`
+}
+
+type RestoreRes struct {
+	api.CommonRes
+	Backup *Backup `json:"backup,omitempty"`
+}
+
+type DeleteReq struct {
+	api.Meta `path:"/backup/delete" tags:"Backup" method:"Post" summary:"删除备份"`
+	BackupId string `json:"backupId,omitempty"`
+}
+
+type DeleteRes struct {
+	api.CommonRes
+}
@@ -18,7 +18,11 @@
 
 package collection
 
-import "github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
+import (
+	"encoding/json"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
+)
 
 // CreateReq create collection request
 type CreateReq struct {
@@ -135,3 +139,16 @@ type EmbeddingRes struct {
 	Embedding
 	Status string
 }
+
+// StatsReq get collection statistics request
+type StatsReq struct {
+	api.Meta   `path:"/collection/stats" tags:"Collection" method:"Post" summary:"查询collection统计信息"`
+	Database   string `json:"database,omitempty"`
+	Collection string `json:"collection,omitempty"`
+}
+
+// StatsRes get collection statistics response, the stats are kept raw to preserve the unknown fields
+type StatsRes struct {
+	api.CommonRes
+	Stats json.RawMessage `json:"stats,omitempty"`
+}
@@ -66,6 +66,20 @@ type ListRes struct {
 }
 
 type DatabaseInfo struct {
+	Database   string `json:"database,omitempty"`
 	CreateTime string `json:"createTime,omitempty"`
 	DbType     string `json:"dbType,omitempty"`
+	Count      int64  `json:"count,omitempty"`
+}
+
+// DescribeReq describe database request
+type DescribeReq struct {
+	api.Meta `path:"/database/describe" tags:"Database" method:"Post" summary:"查询database的创建时间、类型及collection数量"`
+	Database string `json:"database,omitempty"`
+}
+
+// DescribeRes describe database response
+type DescribeRes struct {
+	api.CommonRes
+	Database *DatabaseInfo `json:"database,omitempty"`
 }
@@ -21,6 +21,7 @@ package document
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"reflect"
 	"strings"
 
@@ -36,11 +37,57 @@ type UpsertReq struct {
 	Documents  []*Document `json:"documents,omitempty"`
 }
 
+// MarshalStream writes the json of request to w document by document, the same as json.Encoder
+// without escaping html, so the documents are not buffered as a whole.
+func (r *UpsertReq) MarshalStream(w io.Writer) error {
+	head := *r
+	head.Documents = nil
+	buf := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(&head); err != nil {
+		return err
+	}
+	fields := bytes.TrimSuffix(bytes.TrimSpace(buf.Bytes()), []byte("}"))
+	if len(r.Documents) == 0 {
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+	if len(fields) > 1 {
+		fields = append(fields, ',')
+	}
+	if _, err := w.Write(append(fields, `"documents":[`...)); err != nil {
+		return err
+	}
+	for i, doc := range r.Documents {
+		buf.Reset()
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+		// trim the newline written by Encode
+		if _, err := w.Write(buf.Bytes()[:buf.Len()-1]); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write([]byte("]}\n"))
+	return err
+}
+
 // UpsertRes upsert document response
 type UpsertRes struct {
 	api.CommonRes
-	AffectedCount int    `json:"affectedCount,omitempty"`
-	Warning       string `json:"warning,omitempty"`
+	AffectedCount      int                 `json:"affectedCount,omitempty"`
+	Warning            string              `json:"warning,omitempty"`
+	EmbeddingExtraInfo *EmbeddingExtraInfo `json:"embeddingExtraInfo,omitempty"`
+	Ids                []string            `json:"ids,omitempty"`
+}
+
+// EmbeddingExtraInfo the usage of embedding model
+type EmbeddingExtraInfo struct {
+	TokenUsed uint64 `json:"tokenUsed,omitempty"`
 }
 
 // Document document struct for document api
@@ -83,15 +130,23 @@ func (d Document) MarshalJSON() ([]byte, error) {
 }
 
 func (d *Document) UnmarshalJSON(data []byte) error {
+	if d.unmarshalFast(data) {
+		return nil
+	}
+	return d.unmarshalGeneric(data)
+}
+
+// unmarshalGeneric decodes the document with encoding/json, it is the fallback of unmarshalFast.
+func (d *Document) unmarshalGeneric(data []byte) error {
 	type Alias Document
 	var temp Alias
 	err := json.Unmarshal(data, &temp)
 	if err != nil {
 		return err
 	}
-	ds := json.NewDecoder(bytes.NewReader(data))
-	ds.UseNumber()
-	err = ds.Decode(&temp.Fields)
+	// decode the raw values first, so the known fields such as the vector are not decoded twice
+	var raw map[string]json.RawMessage
+	err = json.Unmarshal(data, &raw)
 	if err != nil {
 		return err
 	}
@@ -102,7 +157,20 @@ func (d *Document) UnmarshalJSON(data []byte) error {
 		if tags[0] == "-" {
 			continue
 		}
-		delete(temp.Fields, tags[0])
+		delete(raw, tags[0])
+	}
+	if len(raw) != 0 {
+		temp.Fields = make(map[string]interface{}, len(raw))
+	}
+	for name, value := range raw {
+		var v interface{}
+		ds := json.NewDecoder(bytes.NewReader(value))
+		ds.UseNumber()
+		err = ds.Decode(&v)
+		if err != nil {
+			return err
+		}
+		temp.Fields[name] = v
 	}
 
 	*d = Document(temp)
@@ -121,8 +189,9 @@ type SearchReq struct {
 // SearchRes search documents response
 type SearchRes struct {
 	api.CommonRes
-	Warning   string        `json:"warning,omitempty"`
-	Documents [][]*Document `json:"documents,omitempty"`
+	Warning            string              `json:"warning,omitempty"`
+	Documents          [][]*Document       `json:"documents,omitempty"`
+	EmbeddingExtraInfo *EmbeddingExtraInfo `json:"embeddingExtraInfo,omitempty"`
 }
 
 type HybridSearchReq struct {
@@ -194,13 +263,19 @@ type QueryReq struct {
 }
 
 type QueryCond struct {
-	DocumentIds    []string `json:"documentIds,omitempty"`
-	IndexIds       []uint64 `json:"indexIds,omitempty"`
-	RetrieveVector bool     `json:"retrieveVector,omitempty"`
-	Filter         string   `json:"filter,omitempty"`
-	Limit          int64    `json:"limit,omitempty"`
-	Offset         int64    `json:"offset,omitempty"`
-	OutputFields   []string `json:"outputFields,omitempty"`
+	DocumentIds    []string    `json:"documentIds,omitempty"`
+	IndexIds       []uint64    `json:"indexIds,omitempty"`
+	RetrieveVector bool        `json:"retrieveVector,omitempty"`
+	Filter         string      `json:"filter,omitempty"`
+	Limit          int64       `json:"limit,omitempty"`
+	Offset         int64       `json:"offset,omitempty"`
+	OutputFields   []string    `json:"outputFields,omitempty"`
+	Sort           []*SortRule `json:"sort,omitempty"`
+}
+
+type SortRule struct {
+	FieldName string `json:"fieldName,omitempty"`
+	Direction string `json:"direction,omitempty"`
 }
 
 // QueryRes query document response
@@ -210,6 +285,25 @@ type QueryRes struct {
 	Documents []*Document `json:"documents,omitempty"`
 }
 
+// CountReq count document request
+type CountReq struct {
+	api.Meta        `path:"/document/count" tags:"Document" method:"Post" summary:"统计满足过滤条件的文档数量"`
+	Database        string          `json:"database,omitempty"`
+	Collection      string          `json:"collection,omitempty"`
+	Query           *CountQueryCond `json:"query,omitempty"`
+	ReadConsistency string          `json:"readConsistency,omitempty"`
+}
+
+type CountQueryCond struct {
+	Filter string `json:"filter,omitempty"`
+}
+
+// CountRes count document response
+type CountRes struct {
+	api.CommonRes
+	Count uint64 `json:"count,omitempty"`
+}
+
 // DeleteReq delete document request
 type DeleteReq struct {
 	api.Meta   `path:"/document/delete" tags:"Document" method:"Post" summary:"删除指定id的文档,flat 索引不支持删除"`
@@ -221,7 +315,8 @@ type DeleteReq struct {
 // DeleteRes delete document request
 type DeleteRes struct {
 	api.CommonRes
-	AffectedCount int `json:"affectedCount,omitempty"`
+	AffectedCount int    `json:"affectedCount,omitempty"`
+	Warning       string `json:"warning,omitempty"`
 }
 
 type UpdateReq struct {
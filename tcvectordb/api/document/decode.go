@@ -0,0 +1,315 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package document
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// unmarshalFast decodes the document in a single pass over the data without reflection. It returns
+// false without modifying d if the data is not understood, such as invalid json, escaped keys or
+// values of unexpected types, so that unmarshalGeneric decodes it and reports the same errors.
+func (d *Document) unmarshalFast(data []byte) bool {
+	if !json.Valid(data) {
+		return false
+	}
+	var temp Document
+	s := docScanner{data: data}
+	s.skipSpace()
+	if !s.consume('{') {
+		return false
+	}
+	s.skipSpace()
+	if s.consume('}') {
+		*d = temp
+		return true
+	}
+	for {
+		s.skipSpace()
+		key, ok := s.key()
+		if !ok {
+			return false
+		}
+		s.skipSpace()
+		s.consume(':')
+		s.skipSpace()
+		value := s.value()
+		if !temp.setFast(key, value) {
+			return false
+		}
+		s.skipSpace()
+		if s.consume('}') {
+			break
+		}
+		s.consume(',')
+	}
+	*d = temp
+	return true
+}
+
+// setFast sets the field named key with the raw json value, the known fields are matched only
+// if they are in the exact case, as encoding/json matches them case-insensitively.
+func (d *Document) setFast(key []byte, value []byte) bool {
+	switch string(key) {
+	case "id":
+		if isNull(value) {
+			return true
+		}
+		if value[0] != '"' {
+			return false
+		}
+		d.Id = unquote(value)
+		return true
+	case "vector":
+		if isNull(value) {
+			return true
+		}
+		vector, ok := parseVector(value)
+		if !ok {
+			return false
+		}
+		d.Vector = vector
+		return true
+	case "score":
+		if isNull(value) {
+			return true
+		}
+		score, ok := parseFloat32(value)
+		if !ok {
+			return false
+		}
+		d.Score = score
+		return true
+	case "sparse_vector":
+		return json.Unmarshal(value, &d.SparseVector) == nil
+	case "doc_info":
+		return json.Unmarshal(value, &d.DocInfo) == nil
+	}
+	for _, name := range []string{"id", "vector", "score", "sparse_vector", "doc_info"} {
+		if strings.EqualFold(string(key), name) {
+			return false
+		}
+	}
+
+	v, ok := parseValue(value)
+	if !ok {
+		return false
+	}
+	if d.Fields == nil {
+		d.Fields = make(map[string]interface{})
+	}
+	d.Fields[string(key)] = v
+	return true
+}
+
+// parseValue parses the raw json value as encoding/json does into interface{} with UseNumber.
+func parseValue(value []byte) (interface{}, bool) {
+	switch value[0] {
+	case '"':
+		return unquote(value), true
+	case 't':
+		return true, true
+	case 'f':
+		return false, true
+	case 'n':
+		return nil, true
+	case '[':
+		array := []interface{}{}
+		s := docScanner{data: value, pos: 1}
+		s.skipSpace()
+		if s.consume(']') {
+			return array, true
+		}
+		for {
+			s.skipSpace()
+			v, ok := parseValue(s.value())
+			if !ok {
+				return nil, false
+			}
+			array = append(array, v)
+			s.skipSpace()
+			if s.consume(']') {
+				return array, true
+			}
+			s.consume(',')
+		}
+	case '{':
+		object := map[string]interface{}{}
+		s := docScanner{data: value, pos: 1}
+		s.skipSpace()
+		if s.consume('}') {
+			return object, true
+		}
+		for {
+			s.skipSpace()
+			key, ok := s.key()
+			if !ok {
+				return nil, false
+			}
+			s.skipSpace()
+			s.consume(':')
+			s.skipSpace()
+			v, ok := parseValue(s.value())
+			if !ok {
+				return nil, false
+			}
+			object[string(key)] = v
+			s.skipSpace()
+			if s.consume('}') {
+				return object, true
+			}
+			s.consume(',')
+		}
+	default:
+		return json.Number(value), true
+	}
+}
+
+func isNull(value []byte) bool {
+	return value[0] == 'n'
+}
+
+// unquote returns the string of a json string value, the escaped or invalid utf-8 strings are
+// decoded by encoding/json.
+func unquote(value []byte) string {
+	inner := value[1 : len(value)-1]
+	if bytes.IndexByte(inner, '\\') < 0 && utf8.Valid(inner) {
+		return string(inner)
+	}
+	var s string
+	json.Unmarshal(value, &s)
+	return s
+}
+
+func parseFloat32(value []byte) (float32, bool) {
+	f, err := strconv.ParseFloat(string(value), 32)
+	if err != nil {
+		return 0, false
+	}
+	return float32(f), true
+}
+
+// parseVector parses an array of numbers, which is allocated once with the counted length.
+func parseVector(value []byte) ([]float32, bool) {
+	if value[0] != '[' {
+		return nil, false
+	}
+	inner := bytes.TrimSpace(value[1 : len(value)-1])
+	if len(inner) == 0 {
+		return []float32{}, true
+	}
+	vector := make([]float32, 0, bytes.Count(inner, []byte{','})+1)
+	for len(inner) != 0 {
+		item := inner
+		if i := bytes.IndexByte(inner, ','); i >= 0 {
+			item, inner = inner[:i], inner[i+1:]
+		} else {
+			inner = nil
+		}
+		item = bytes.TrimSpace(item)
+		if len(item) == 0 || (item[0] != '-' && (item[0] < '0' || item[0] > '9')) {
+			return nil, false
+		}
+		f, ok := parseFloat32(item)
+		if !ok {
+			return nil, false
+		}
+		vector = append(vector, f)
+	}
+	return vector, true
+}
+
+// docScanner walks the top level of a valid json object.
+type docScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *docScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\r', '\n':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *docScanner) consume(c byte) bool {
+	if s.pos < len(s.data) && s.data[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// key returns the unquoted key, it fails on the keys with escapes or non-ascii characters.
+func (s *docScanner) key() ([]byte, bool) {
+	value := s.value()
+	if len(value) < 2 || value[0] != '"' {
+		return nil, false
+	}
+	key := value[1 : len(value)-1]
+	for _, c := range key {
+		if c == '\\' || c >= utf8.RuneSelf {
+			return nil, false
+		}
+	}
+	return key, true
+}
+
+// value returns the raw bytes of the next value.
+func (s *docScanner) value() []byte {
+	start := s.pos
+	depth := 0
+	for s.pos < len(s.data) {
+		c := s.data[s.pos]
+		switch c {
+		case '"':
+			s.pos++
+			for s.pos < len(s.data) && s.data[s.pos] != '"' {
+				if s.data[s.pos] == '\\' {
+					s.pos++
+				}
+				s.pos++
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return s.data[start:s.pos]
+			}
+			depth--
+		case ',', ' ', '\t', '\r', '\n', ':':
+			if depth == 0 {
+				return s.data[start:s.pos]
+			}
+		}
+		s.pos++
+		if depth == 0 && (c == '"' || c == '}' || c == ']') {
+			return s.data[start:s.pos]
+		}
+	}
+	return s.data[start:s.pos]
+}
@@ -0,0 +1,134 @@
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// genericDocument decodes with the encoding/json path only.
+type genericDocument Document
+
+func (d *genericDocument) UnmarshalJSON(data []byte) error {
+	return (*Document)(d).unmarshalGeneric(data)
+}
+
+func TestDocumentUnmarshalFast(t *testing.T) {
+	cases := []string{
+		`{}`,
+		`{"id":"1","score":0.5,"vector":[0.1,-2e-3, 3 ,4.5e1]}`,
+		`{"id":"1","vector":[]}`,
+		` { "id" : "a\"bé" , "score" : 1 } `,
+		`{"id":null,"vector":null,"score":null}`,
+		`{"id":"1","author":"x","page":12,"big":18446744073709551615,"rate":-1.5e3,"ok":true,"no":false,"nil":null}`,
+		`{"id":"1","tags":["a",1,{"k":[2.5]}],"obj":{"a":{"b":"c"},"n":1}}`,
+		`{"id":"1","text":"a,b]}\"\\","Fields":1,"-":2}`,
+		`{"id":"1","sparse_vector":[[1,0.5],[2,0.25]],"doc_info":"YWJj"}`,
+		`{"ID":"1","Score":2}`,
+		`{"id":"1","vector":[1,"2"]}`,
+		`{"id":"1","vector":[1,null]}`,
+		`{"id":"1","vector":[[1,2]]}`,
+		`{"id":"1","score":1e50}`,
+		`{"id":1}`,
+		`{"id":"1","score":"1"}`,
+		`{"id":"\xff","key":"v","name":"\xfe"}`,
+		`{"id":"1","id":"2","a":1,"a":"2"}`,
+		`{"id":"1",}`,
+		`{"id":"1"`,
+		`[1]`,
+		`null`,
+	}
+	for _, c := range cases {
+		var fast Document
+		var generic genericDocument
+		fastErr := json.Unmarshal([]byte(c), &fast)
+		genericErr := json.Unmarshal([]byte(c), &generic)
+		if fmt.Sprint(fastErr) != fmt.Sprint(genericErr) {
+			t.Errorf("%s: expect error %v, got %v", c, genericErr, fastErr)
+		}
+		if !reflect.DeepEqual(fast, Document(generic)) {
+			t.Errorf("%s: expect %+v, got %+v", c, Document(generic), fast)
+		}
+
+		fastErr = fast.UnmarshalJSON([]byte(c))
+		genericErr = generic.UnmarshalJSON([]byte(c))
+		if fmt.Sprint(fastErr) != fmt.Sprint(genericErr) || !reflect.DeepEqual(fast, Document(generic)) {
+			t.Errorf("%s: expect %+v, %v, got %+v, %v", c, Document(generic), genericErr, fast, fastErr)
+		}
+	}
+
+	payload := searchResponse(10, 10, 16)
+	var fast SearchRes
+	var generic struct {
+		Documents [][]*genericDocument `json:"documents"`
+	}
+	if err := json.Unmarshal(payload, &fast); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(payload, &generic); err != nil {
+		t.Fatal(err)
+	}
+	for i := range generic.Documents {
+		for j := range generic.Documents[i] {
+			if !reflect.DeepEqual(*fast.Documents[i][j], Document(*generic.Documents[i][j])) {
+				t.Fatalf("expect %+v, got %+v", generic.Documents[i][j], fast.Documents[i][j])
+			}
+		}
+	}
+}
+
+// searchResponse returns a search response of queries * hits documents, each with a vector of dimension.
+func searchResponse(queries, hits, dimension int) []byte {
+	var sb strings.Builder
+	sb.WriteString(`{"code":0,"msg":"operation success","documents":[`)
+	for i := 0; i < queries; i++ {
+		if i != 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteByte('[')
+		for j := 0; j < hits; j++ {
+			if j != 0 {
+				sb.WriteByte(',')
+			}
+			fmt.Fprintf(&sb, `{"id":"doc-%d-%d","score":%g,"vector":[`, i, j, 1/float32(j+1))
+			for k := 0; k < dimension; k++ {
+				if k != 0 {
+					sb.WriteByte(',')
+				}
+				fmt.Fprintf(&sb, "%g", float32(i*k+j)/997)
+			}
+			fmt.Fprintf(&sb, `],"author":"author %d","page":%d,"tags":["a","b"]}`, j, i*j)
+		}
+		sb.WriteByte(']')
+	}
+	sb.WriteString(`]}`)
+	return []byte(sb.String())
+}
+
+func BenchmarkDecodeSearchResponse(b *testing.B) {
+	payload := searchResponse(100, 100, 768)
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		for i := 0; i < b.N; i++ {
+			var res SearchRes
+			if err := json.Unmarshal(payload, &res); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(payload)))
+		for i := 0; i < b.N; i++ {
+			var res struct {
+				Documents [][]*genericDocument `json:"documents"`
+			}
+			if err := json.Unmarshal(payload, &res); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -20,6 +20,7 @@ package tcvectordb
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/alias"
 )
@@ -30,6 +31,8 @@ type AliasInterface interface {
 	SdkClient
 	SetAlias(ctx context.Context, collectionName, aliasName string) (result *SetAliasResult, err error)
 	DeleteAlias(ctx context.Context, aliasName string) (result *DeleteAliasResult, err error)
+	ListAliases(ctx context.Context) (result *ListAliasesResult, err error)
+	DescribeAlias(ctx context.Context, aliasName string) (result *DescribeAliasResult, err error)
 }
 
 type implementerAlias struct {
@@ -83,3 +86,58 @@ func (i *implementerAlias) DeleteAlias(ctx context.Context, aliasName string) (*
 	result.AffectedCount = res.AffectedCount
 	return result, nil
 }
+
+type AliasItem struct {
+	Alias      string
+	Collection string
+}
+
+type ListAliasesResult struct {
+	Aliases []AliasItem
+}
+
+// ListAliases list the aliases of all collections in the database.
+func (i *implementerAlias) ListAliases(ctx context.Context) (*ListAliasesResult, error) {
+	if i.database.IsAIDatabase() {
+		return nil, AIDbTypeError
+	}
+	req := new(alias.ListReq)
+	req.Database = i.database.DatabaseName
+	res := new(alias.ListRes)
+	err := i.Request(ctx, req, res)
+	if err != nil {
+		return nil, err
+	}
+	result := new(ListAliasesResult)
+	for _, item := range res.Aliases {
+		if item != nil {
+			result.Aliases = append(result.Aliases, AliasItem{Alias: item.Alias, Collection: item.Collection})
+		}
+	}
+	return result, nil
+}
+
+type DescribeAliasResult struct {
+	AliasItem
+}
+
+// DescribeAlias get the collection which the alias points to.
+func (i *implementerAlias) DescribeAlias(ctx context.Context, aliasName string) (*DescribeAliasResult, error) {
+	if i.database.IsAIDatabase() {
+		return nil, AIDbTypeError
+	}
+	req := new(alias.DescribeReq)
+	req.Database = i.database.DatabaseName
+	req.Alias = aliasName
+	res := new(alias.DescribeRes)
+	err := i.Request(ctx, req, res)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range res.Aliases {
+		if item != nil && item.Alias == aliasName {
+			return &DescribeAliasResult{AliasItem{Alias: item.Alias, Collection: item.Collection}}, nil
+		}
+	}
+	return nil, fmt.Errorf("alias %s not exist in database %s", aliasName, i.database.DatabaseName)
+}
@@ -0,0 +1,72 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+func TestAlias(t *testing.T) {
+	var upserted []string
+	target := "v1"
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/alias/list":
+			w.Write([]byte(`{"code":0,"aliases":[{"alias":"current","collection":"v1"},{"alias":"next","collection":"v2"}]}`))
+		case "/alias/describe":
+			w.Write([]byte(`{"code":0,"aliases":[{"alias":"current","collection":"` + target + `"}]}`))
+		case "/collection/describe":
+			w.Write([]byte(`{"code":0,"collection":{"database":"db","collection":"v1","alias":["current"],
+				"indexes":[{"fieldName":"vector","fieldType":"vector","indexType":"HNSW","dimension":2}]}}`))
+		case "/document/upsert":
+			req := new(document.UpsertReq)
+			json.NewDecoder(r.Body).Decode(req)
+			upserted = append(upserted, req.Collection)
+			w.Write([]byte(`{"code":0,"affectedCount":1}`))
+		}
+	}, ClientOption{})
+	ctx := context.Background()
+	db := cli.Database("db")
+
+	list, err := db.ListAliases(ctx)
+	if err != nil || len(list.Aliases) != 2 || list.Aliases[1].Collection != "v2" {
+		t.Fatalf("unexpected aliases %+v, err %v", list, err)
+	}
+	desc, err := db.DescribeAlias(ctx, "current")
+	if err != nil || desc.Collection != "v1" {
+		t.Fatalf("unexpected alias %+v, err %v", desc, err)
+	}
+
+	res, err := db.DescribeCollection(ctx, "current")
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := &res.Collection
+	if coll.CollectionName != "current" || coll.ResolvedCollection != "v1" {
+		t.Errorf("expect the handle of alias, got %s resolved %s", coll.CollectionName, coll.ResolvedCollection)
+	}
+	// a bad request to the same collection does not turn off the check
+	docs := []Document{{Id: "0001", Vector: []float32{0.1, 0.2, 0.3}}}
+	for n := 0; n < 2; n++ {
+		_, err = coll.Upsert(ctx, docs)
+		if err == nil || strings.Contains(err.Error(), "alias current") || len(upserted) != 0 {
+			t.Fatalf("expect dimension check failed without alias hint, got %v", err)
+		}
+	}
+	// the alias is switched to a collection of dimension 3
+	target = "v2"
+	_, err = coll.Upsert(ctx, docs)
+	if err == nil || !strings.Contains(err.Error(), "alias current") || len(upserted) != 0 {
+		t.Fatalf("expect dimension check failed with alias hint, got %v", err)
+	}
+	if _, err = coll.Upsert(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+	if len(upserted) != 1 || upserted[0] != "current" {
+		t.Errorf("expect upsert sent to the alias without the stale check, got %v", upserted)
+	}
+}
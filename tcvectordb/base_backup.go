@@ -0,0 +1,191 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/backup"
+)
+
+var _ BackupInterface = &implementerBackup{}
+
+// BackupInterface backup api, the snapshots of a collection which could be restored to another collection.
+// CreateBackup returns ErrBackupInProgress if the collection is being backed up,
+// and RestoreBackup returns ErrTargetCollectionExists if the target collection exists.
+type BackupInterface interface {
+	CreateBackup(ctx context.Context, databaseName, collectionName string, option BackupOption) (*BackupJob, error)
+	ListBackups(ctx context.Context, databaseName, collectionName string) ([]*Backup, error)
+	DescribeBackup(ctx context.Context, backupId string) (*Backup, error)
+	RestoreBackup(ctx context.Context, backupId, targetDatabase, targetCollection string) (*BackupJob, error)
+	DeleteBackup(ctx context.Context, backupId string) error
+}
+
+type implementerBackup struct {
+	SdkClient
+}
+
+const (
+	BackupStatusCreating  = "creating"
+	BackupStatusReady     = "ready"
+	BackupStatusRestoring = "restoring"
+	BackupStatusFailed    = "failed"
+)
+
+type BackupOption struct {
+	// Description: the description of the backup
+	Description string
+}
+
+// Backup the snapshot of collection
+type Backup struct {
+	BackupId       string
+	DatabaseName   string
+	CollectionName string
+	Description    string
+	// Status: creating, ready, restoring or failed
+	Status string
+	// Progress: the progress of creating or restoring, such as 30%
+	Progress   string
+	Size       uint64
+	CreateTime string
+}
+
+// ProgressPercent parses the Progress as a percent in [0, 100], ok is false if the server reports no progress.
+func (b *Backup) ProgressPercent() (percent float64, ok bool) {
+	return parseProgressPercent(b.Progress)
+}
+
+// BackupJob the backup being created or restored, use Wait to wait until it is done.
+type BackupJob struct {
+	BackupId string
+	// Backup: the backup returned when the job is submitted
+	Backup *Backup
+
+	backups BackupInterface
+}
+
+// Wait describe the backup every pollInterval (default 1s) until it is ready, it fails, or the ctx is done.
+// It returns the last described backup.
+func (j *BackupJob) Wait(ctx context.Context, pollInterval time.Duration) (*Backup, error) {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		b, err := j.backups.DescribeBackup(ctx, j.BackupId)
+		if err != nil {
+			return nil, err
+		}
+		switch b.Status {
+		case BackupStatusReady:
+			return b, nil
+		case BackupStatusFailed:
+			return b, fmt.Errorf("backup %s failed", j.BackupId)
+		}
+		select {
+		case <-ctx.Done():
+			return b, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CreateBackup create a backup of the collection, the job is done when the backup is ready.
+func (i *implementerBackup) CreateBackup(ctx context.Context, databaseName, collectionName string, option BackupOption) (*BackupJob, error) {
+	req := &backup.CreateReq{Database: databaseName, Collection: collectionName, Description: option.Description}
+	res := new(backup.CreateRes)
+	if err := i.Request(ctx, req, res); err != nil {
+		return nil, err
+	}
+	return i.job(res.Backup)
+}
+
+// ListBackups list the backups of the collection.
+func (i *implementerBackup) ListBackups(ctx context.Context, databaseName, collectionName string) ([]*Backup, error) {
+	req := &backup.ListReq{Database: databaseName, Collection: collectionName}
+	res := new(backup.ListRes)
+	if err := i.Request(ctx, req, res); err != nil {
+		return nil, err
+	}
+	backups := make([]*Backup, 0, len(res.Backups))
+	for _, b := range res.Backups {
+		if b == nil {
+			continue
+		}
+		backups = append(backups, toBackup(b))
+	}
+	return backups, nil
+}
+
+// DescribeBackup describe the backup, including its status and progress.
+func (i *implementerBackup) DescribeBackup(ctx context.Context, backupId string) (*Backup, error) {
+	req := &backup.DescribeReq{BackupId: backupId}
+	res := new(backup.DescribeRes)
+	if err := i.Request(ctx, req, res); err != nil {
+		return nil, err
+	}
+	if res.Backup == nil {
+		return nil, fmt.Errorf("backup %s not found", backupId)
+	}
+	return toBackup(res.Backup), nil
+}
+
+// RestoreBackup restore the backup to the target collection which must not exist,
+// the job is done when the backup is ready again.
+func (i *implementerBackup) RestoreBackup(ctx context.Context, backupId, targetDatabase, targetCollection string) (*BackupJob, error) {
+	req := &backup.RestoreReq{BackupId: backupId, Database: targetDatabase, Collection: targetCollection}
+	res := new(backup.RestoreRes)
+	if err := i.Request(ctx, req, res); err != nil {
+		return nil, err
+	}
+	if res.Backup == nil {
+		res.Backup = &backup.Backup{BackupId: backupId, Status: BackupStatusRestoring}
+	}
+	return i.job(res.Backup)
+}
+
+// DeleteBackup delete the backup.
+func (i *implementerBackup) DeleteBackup(ctx context.Context, backupId string) error {
+	req := &backup.DeleteReq{BackupId: backupId}
+	return i.Request(ctx, req, new(backup.DeleteRes))
+}
+
+func (i *implementerBackup) job(b *backup.Backup) (*BackupJob, error) {
+	if b == nil || b.BackupId == "" {
+		return nil, fmt.Errorf("the server returns no backup id")
+	}
+	return &BackupJob{BackupId: b.BackupId, Backup: toBackup(b), backups: i}, nil
+}
+
+func toBackup(b *backup.Backup) *Backup {
+	return &Backup{
+		BackupId:       b.BackupId,
+		DatabaseName:   b.Database,
+		CollectionName: b.Collection,
+		Description:    b.Description,
+		Status:         b.Status,
+		Progress:       b.Progress,
+		Size:           b.Size,
+		CreateTime:     b.CreateTime,
+	}
+}
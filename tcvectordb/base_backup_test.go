@@ -0,0 +1,96 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackup(t *testing.T) {
+	var describes int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/backup/create":
+			if body["collection"] == "busy" {
+				w.Write([]byte(`{"code":1,"msg":"backup of collection busy is in progress"}`))
+				return
+			}
+			if body["description"] != "nightly" {
+				t.Errorf("unexpected create body %v", body)
+			}
+			w.Write([]byte(`{"code":0,"backup":{"backupId":"bk-1","database":"db","collection":"coll","status":"creating","progress":"0%"}}`))
+		case "/backup/describe":
+			if atomic.AddInt32(&describes, 1) == 1 {
+				w.Write([]byte(`{"code":0,"backup":{"backupId":"bk-1","status":"creating","progress":"40%"}}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"backup":{"backupId":"bk-1","database":"db","collection":"coll","status":"ready","size":1024}}`))
+		case "/backup/list":
+			w.Write([]byte(`{"code":0,"backups":[{"backupId":"bk-1","status":"ready"},null,{"backupId":"bk-0","status":"failed"}]}`))
+		case "/backup/restore":
+			if body["collection"] == "coll" {
+				w.Write([]byte(`{"code":15203,"msg":"target collection is not empty"}`))
+				return
+			}
+			if body["collection"] == "legacy" {
+				w.Write([]byte(`{"code":1,"msg":"collection legacy already exist"}`))
+				return
+			}
+			w.Write([]byte(`{"code":0}`))
+		default:
+			w.Write([]byte(`{"code":0}`))
+		}
+	}, ClientOption{})
+	ctx := context.Background()
+
+	job, err := cli.CreateBackup(ctx, "db", "coll", BackupOption{Description: "nightly"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.BackupId != "bk-1" || job.Backup.Status != BackupStatusCreating {
+		t.Fatalf("unexpected job %+v", job.Backup)
+	}
+	b, err := job.Wait(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Status != BackupStatusReady || b.Size != 1024 || atomic.LoadInt32(&describes) != 2 {
+		t.Errorf("unexpected backup %+v after %d describes", b, atomic.LoadInt32(&describes))
+	}
+	if b, err = job.Wait(ctx, 0); err != nil || b.Status != BackupStatusReady {
+		t.Errorf("expect the default poll interval without pollInterval, got %+v, %v", b, err)
+	}
+
+	if _, err := cli.CreateBackup(ctx, "db", "busy", BackupOption{}); !errors.Is(err, ErrBackupInProgress) {
+		t.Errorf("expect backup in progress, got %v", err)
+	}
+	if _, err := cli.RestoreBackup(ctx, "bk-1", "db", "coll"); !errors.Is(err, ErrTargetCollectionExists) {
+		t.Errorf("expect target collection exists, got %v", err)
+	}
+	if _, err := cli.RestoreBackup(ctx, "bk-1", "db", "legacy"); !errors.Is(err, ErrTargetCollectionExists) {
+		t.Errorf("expect target collection exists matched by the message, got %v", err)
+	}
+	if (&APIError{Code: ERR_COLLECTION_EXIST, RequestPath: "/collection/create"}).Is(ErrTargetCollectionExists) {
+		t.Error("expect the code matched only for RestoreBackup")
+	}
+	if job, err = cli.RestoreBackup(ctx, "bk-1", "db", "restored"); err != nil || job.BackupId != "bk-1" {
+		t.Fatalf("unexpected restore job %+v, %v", job, err)
+	}
+
+	backups, err := cli.ListBackups(ctx, "db", "coll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[1].BackupId != "bk-0" || backups[1].Status != BackupStatusFailed {
+		t.Errorf("unexpected backups %+v", backups)
+	}
+	if err := cli.DeleteBackup(ctx, "bk-0"); err != nil {
+		t.Fatal(err)
+	}
+}
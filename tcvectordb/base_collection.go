@@ -21,8 +21,12 @@ package tcvectordb
 import (
 	"context"
 	"fmt"
+	"math"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
@@ -36,13 +40,14 @@ type CollectionInterface interface {
 	SdkClient
 	ExistsCollection(ctx context.Context, name string) (bool, error)
 	CreateCollectionIfNotExists(ctx context.Context, name string, shardNum, replicasNum uint32, description string,
-		indexes Indexes, params ...*CreateCollectionParams) (*Collection, error)
+		indexes Indexes, params ...*CreateCollectionParams) (*CreateCollectionResult, error)
 	CreateCollection(ctx context.Context, name string, shardNum, replicasNum uint32, description string,
 		indexes Indexes, params ...*CreateCollectionParams) (*Collection, error)
-	ListCollection(ctx context.Context) (result *ListCollectionResult, err error)
+	ListCollection(ctx context.Context, params ...*ListCollectionParams) (result *ListCollectionResult, err error)
 	DescribeCollection(ctx context.Context, name string) (result *DescribeCollectionResult, err error)
 	DropCollection(ctx context.Context, name string) (result *DropCollectionResult, err error)
 	TruncateCollection(ctx context.Context, name string) (result *TruncateCollectionResult, err error)
+	ModifyCollection(ctx context.Context, name string, param ModifyCollectionParams) (result *DescribeCollectionResult, err error)
 	Collection(name string) *Collection
 }
 
@@ -58,12 +63,14 @@ type CreateCollectionParams struct {
 
 type CreateCollectionResult struct {
 	Collection
+	// Created: false if the collection already exists
+	Created bool
 }
 
 func (i *implementerCollection) ExistsCollection(ctx context.Context, name string) (bool, error) {
 	res, err := i.DescribeCollection(ctx, name)
 	if err != nil {
-		if strings.Contains(err.Error(), strconv.Itoa(ERR_UNDEFINED_COLLECTION)) {
+		if IsCollectionNotExist(err) {
 			return false, nil
 		}
 		return false, fmt.Errorf("get collection %s failed, err: %v", name, err.Error())
@@ -74,19 +81,194 @@ func (i *implementerCollection) ExistsCollection(ctx context.Context, name strin
 	return true, nil
 }
 
+// CreateCollectionIfNotExists create the collection if it does not exist, otherwise returns the existing one.
+// It returns SchemaMismatchError if the indexes of the existing collection are different from indexes.
 func (i *implementerCollection) CreateCollectionIfNotExists(ctx context.Context, name string, shardNum, replicasNum uint32, description string,
-	indexes Indexes, params ...*CreateCollectionParams) (*Collection, error) {
+	indexes Indexes, params ...*CreateCollectionParams) (*CreateCollectionResult, error) {
+	return createCollectionIfNotExists(ctx, i, name, shardNum, replicasNum, description, indexes, params...)
+}
+
+func createCollectionIfNotExists(ctx context.Context, i CollectionInterface, name string, shardNum, replicasNum uint32, description string,
+	indexes Indexes, params ...*CreateCollectionParams) (*CreateCollectionResult, error) {
 	res, err := i.DescribeCollection(ctx, name)
 	if err != nil {
-		if strings.Contains(err.Error(), strconv.Itoa(ERR_UNDEFINED_COLLECTION)) {
-			return i.CreateCollection(ctx, name, shardNum, replicasNum, description, indexes, params...)
+		if !IsCollectionNotExist(err) {
+			return nil, fmt.Errorf("get collection %s failed, err: %v", name, err.Error())
 		}
-		return nil, fmt.Errorf("get collection %s failed, err: %v", name, err.Error())
+		coll, err := i.CreateCollection(ctx, name, shardNum, replicasNum, description, indexes, params...)
+		if err != nil {
+			return nil, err
+		}
+		return &CreateCollectionResult{Collection: *coll, Created: true}, nil
 	}
 	if res == nil {
 		return nil, fmt.Errorf("get collection %s failed", name)
 	}
-	return &res.Collection, nil
+	diffs := diffIndexes(indexes, res.Indexes)
+	if len(params) != 0 && params[0] != nil && params[0].TtlConfig != nil {
+		if diff := diffTtlConfig(params[0].TtlConfig, res.TtlConfig); diff != "" {
+			diffs = append(diffs, diff)
+		}
+	}
+	if len(diffs) != 0 {
+		return nil, &SchemaMismatchError{Collection: name, Diffs: diffs}
+	}
+	return &CreateCollectionResult{Collection: res.Collection}, nil
+}
+
+type ModifyCollectionParams struct {
+	// Description: the new description, unchanged if nil
+	Description *string
+	// NewName: the new name of collection, unchanged if nil
+	NewName *string
+	// MoveAliases: point the aliases of collection to the renamed one, otherwise the collection with aliases is not renamed
+	MoveAliases bool
+	// EmulateRename: the server has no api to modify a collection, so it is renamed by creating the collection
+	// of NewName with the same schema, copying the documents by MigrateCollection, moving the aliases and
+	// dropping the old collection. It must be set explicitly since it copies all documents.
+	EmulateRename bool
+	// Migrate: the option to copy the documents with EmulateRename, its CollectionName, DryRun, Filter and Offset
+	// are ignored. The old collection is kept if any document is not copied.
+	Migrate MigrateOption
+}
+
+// ModifyCollection modify the description and name of collection, and returns the collection described after modified.
+// The server has no api to modify a collection, see ModifyCollectionParams.EmulateRename.
+// It returns RenameCollectionError if NewName exists, or the collection has aliases without MoveAliases.
+func (i *implementerCollection) ModifyCollection(ctx context.Context, name string, param ModifyCollectionParams) (*DescribeCollectionResult, error) {
+	return modifyCollection(ctx, i, i.database, name, param)
+}
+
+func modifyCollection(ctx context.Context, i CollectionInterface, db *Database, name string, param ModifyCollectionParams) (*DescribeCollectionResult, error) {
+	if param.NewName == nil || *param.NewName == name {
+		if param.Description == nil {
+			return i.DescribeCollection(ctx, name)
+		}
+		return nil, fmt.Errorf("modify the description of collection %s failed, the server has no api to modify it, "+
+			"set NewName with EmulateRename to copy it with the description", name)
+	}
+	newName := *param.NewName
+	if !param.EmulateRename {
+		return nil, fmt.Errorf("rename collection %s to %s failed, the server has no api to rename it, set EmulateRename to copy it", name, newName)
+	}
+	src, err := i.DescribeCollection(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := i.ExistsCollection(ctx, newName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, &RenameCollectionError{Collection: name, NewName: newName, Exists: true}
+	}
+	if len(src.Alias) != 0 && !param.MoveAliases {
+		return nil, &RenameCollectionError{Collection: name, NewName: newName, Aliases: src.Alias}
+	}
+
+	description := src.Description
+	if param.Description != nil {
+		description = *param.Description
+	}
+	params := &CreateCollectionParams{TtlConfig: src.TtlConfig}
+	if src.Embedding.Field != "" {
+		params.Embedding = &src.Embedding
+	}
+	_, err = i.CreateCollection(ctx, newName, src.ShardNum, src.ReplicasNum, description, src.Indexes, params)
+	if err != nil {
+		return nil, err
+	}
+	option := param.Migrate
+	// all documents are copied, since the old collection is dropped after that
+	option.CollectionName, option.DryRun, option.Filter, option.Offset = newName, false, nil, 0
+	migrated, err := MigrateCollection(ctx, i.Collection(name), db, option)
+	if err == nil && len(migrated.FailedDocuments) != 0 {
+		err = fmt.Errorf("%d documents are rejected, the first is %s: %s", len(migrated.FailedDocuments),
+			migrated.FailedDocuments[0].Id, migrated.FailedDocuments[0].Reason)
+	}
+	if err == nil && int64(migrated.Documents) < src.DocumentCount {
+		err = fmt.Errorf("%d of %d documents are copied", migrated.Documents, src.DocumentCount)
+	}
+	if err != nil {
+		// the old collection is kept, drop the incomplete copy
+		i.DropCollection(ctx, newName)
+		return nil, fmt.Errorf("copy collection %s to %s failed: %w", name, newName, err)
+	}
+	for _, alias := range src.Alias {
+		// setting the existing alias points it to the new collection at once
+		if _, err = db.SetAlias(ctx, newName, alias); err != nil {
+			return nil, fmt.Errorf("move alias %s to collection %s failed: %w", alias, newName, err)
+		}
+	}
+	if _, err = i.DropCollection(ctx, name); err != nil {
+		return nil, fmt.Errorf("drop collection %s renamed to %s failed: %w", name, newName, err)
+	}
+	return i.DescribeCollection(ctx, newName)
+}
+
+// diffTtlConfig describes the difference of the existing TtlConfig from the requested one,
+// since the server could not modify the TTL of an existing collection.
+func diffTtlConfig(requested, existing *TtlConfig) string {
+	describe := func(ttl *TtlConfig) string {
+		if ttl == nil || !ttl.Enable {
+			return "disabled"
+		}
+		return "enabled on " + ttl.TimeField
+	}
+	if describe(requested) == describe(existing) {
+		return ""
+	}
+	return fmt.Sprintf("ttlConfig: requested %s, exist %s, the TTL of existing collection could not be modified",
+		describe(requested), describe(existing))
+}
+
+// diffIndexes returns the differences of the existing indexes from the requested ones.
+// The index params and the unset element type of the requested array index are not compared.
+func diffIndexes(requested, existing Indexes) []string {
+	describe := func(indexes Indexes, elemType bool) map[string]string {
+		res := make(map[string]string)
+		for _, v := range indexes.VectorIndex {
+			res[v.FieldName] = fmt.Sprintf("%s %s dimension %d metric %s", v.FieldType, v.IndexType, v.Dimension, v.MetricType)
+		}
+		for _, v := range indexes.SparseVectorIndex {
+			res[v.FieldName] = fmt.Sprintf("%s %s metric %s", v.FieldType, v.IndexType, v.MetricType)
+		}
+		for _, v := range indexes.BinaryVectorIndex {
+			res[v.FieldName] = fmt.Sprintf("%s %s dimension %d metric %s", v.FieldType, v.IndexType, v.Dimension, v.MetricType)
+		}
+		for _, v := range indexes.FilterIndex {
+			res[v.FieldName] = fmt.Sprintf("%s %s", v.FieldType, v.IndexType)
+			if elemType && v.ElemType != "" {
+				res[v.FieldName] = fmt.Sprintf("%s<%s> %s", v.FieldType, v.ElemType, v.IndexType)
+			}
+		}
+		return res
+	}
+	compareElemType := true
+	for _, v := range requested.FilterIndex {
+		if v.FieldType == Array && v.ElemType == "" {
+			compareElemType = false
+		}
+	}
+	want := describe(requested, compareElemType)
+	got := describe(existing, compareElemType)
+
+	var diffs []string
+	for name, w := range want {
+		g, ok := got[name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: requested %s, not exist", name, w))
+		} else if g != w {
+			diffs = append(diffs, fmt.Sprintf("%s: requested %s, exist %s", name, w, g))
+		}
+	}
+	for name, g := range got {
+		if _, ok := want[name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: not requested, exist %s", name, g))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
 }
 
 // CreateCollection create a collection. It returns collection struct if err is nil.
@@ -99,6 +281,18 @@ func (i *implementerCollection) CreateCollection(ctx context.Context, name strin
 	if i.database.IsAIDatabase() {
 		return nil, AIDbTypeError
 	}
+	if err := checkMetricType(indexes); err != nil {
+		return nil, err
+	}
+	if err := checkIndexParams(indexes.VectorIndex); err != nil {
+		return nil, err
+	}
+	if err := checkEmbeddingDimension(indexes, params...); err != nil {
+		return nil, err
+	}
+	if err := checkShardReplica(i.Options(), shardNum, replicasNum); err != nil {
+		return nil, err
+	}
 	req := new(collection.CreateReq)
 	req.Database = i.database.DatabaseName
 	req.Collection = name
@@ -129,6 +323,20 @@ func (i *implementerCollection) CreateCollection(ctx context.Context, name strin
 		req.Indexes = append(req.Indexes, &column)
 	}
 
+	for _, v := range indexes.BinaryVectorIndex {
+		if v.Dimension%8 != 0 {
+			return nil, fmt.Errorf("the dimension of binary vector index %s must be a multiple of 8", v.FieldName)
+		}
+		var column api.IndexColumn
+		column.FieldName = v.FieldName
+		column.FieldType = string(v.FieldType)
+		column.IndexType = string(v.IndexType)
+		column.MetricType = string(v.MetricType)
+		column.Dimension = v.Dimension
+
+		req.Indexes = append(req.Indexes, &column)
+	}
+
 	for _, v := range indexes.FilterIndex {
 		var column api.IndexColumn
 		column.FieldName = v.FieldName
@@ -137,6 +345,7 @@ func (i *implementerCollection) CreateCollection(ctx context.Context, name strin
 			column.FieldElementType = string(v.ElemType)
 		}
 		column.IndexType = string(v.IndexType)
+		column.AutoId = v.AutoId
 		req.Indexes = append(req.Indexes, &column)
 	}
 	if len(params) != 0 && params[0] != nil {
@@ -167,17 +376,77 @@ func (i *implementerCollection) CreateCollection(ctx context.Context, name strin
 	coll.ReplicasNum = req.ReplicaNum
 	coll.Description = req.Description
 	coll.Indexes = indexes
+	coll.setCreateParams(params...)
 
 	return coll, nil
 }
 
+// setCreateParams sets the Embedding and TtlConfig of the collection created with params,
+// so the collection works as the described one, such as SearchByText with the embedding.
+func (c *Collection) setCreateParams(params ...*CreateCollectionParams) {
+	if len(params) == 0 || params[0] == nil {
+		return
+	}
+	if params[0].Embedding != nil {
+		c.Embedding = *params[0].Embedding
+	}
+	if params[0].TtlConfig != nil {
+		ttl := *params[0].TtlConfig
+		c.TtlConfig = &ttl
+	}
+}
+
+// ListCollectionParams the paging and filter of ListCollection. The server returns all collections
+// at once, so they are applied before the collections are built.
+type ListCollectionParams struct {
+	// NamePrefix: only list the collections whose name has the prefix
+	NamePrefix string
+	// Offset: skip the first Offset collections matching NamePrefix
+	Offset int
+	// Limit: the max number of collections returned, 0 means no limit
+	Limit int
+	// WithSchema: keep the Indexes of the listed collections, which are left empty if the params are set without it
+	WithSchema bool
+}
+
 type ListCollectionResult struct {
 	Collections []*Collection
+	// Total: the number of collections matching NamePrefix, regardless of Offset and Limit
+	Total int
+}
+
+// listRange returns the positions of names selected by the params, and the number of names matching the prefix.
+func listRange(names []string, param *ListCollectionParams) ([]int, int) {
+	var matched []int
+	for i, name := range names {
+		if strings.HasPrefix(name, param.NamePrefix) {
+			matched = append(matched, i)
+		}
+	}
+	total := len(matched)
+	if param.Offset >= total {
+		return nil, total
+	}
+	if param.Offset > 0 {
+		matched = matched[param.Offset:]
+	}
+	if param.Limit > 0 && param.Limit < len(matched) {
+		matched = matched[:param.Limit]
+	}
+	return matched, total
+}
+
+func listCollectionParam(params []*ListCollectionParams) *ListCollectionParams {
+	if len(params) != 0 && params[0] != nil {
+		return params[0]
+	}
+	return &ListCollectionParams{WithSchema: true}
 }
 
 // ListCollection get collection list.
 // It return the list of collection, each collection same as DescribeCollection return.
-func (i *implementerCollection) ListCollection(ctx context.Context) (*ListCollectionResult, error) {
+// Use ListCollectionParams to page or filter the collections, and WithSchema to keep their Indexes.
+func (i *implementerCollection) ListCollection(ctx context.Context, params ...*ListCollectionParams) (*ListCollectionResult, error) {
 	if i.database.IsAIDatabase() {
 		return nil, AIDbTypeError
 	}
@@ -188,12 +457,23 @@ func (i *implementerCollection) ListCollection(ctx context.Context) (*ListCollec
 	if err != nil {
 		return nil, err
 	}
+	param := listCollectionParam(params)
+	names := make([]string, len(res.Collections))
+	for n, item := range res.Collections {
+		names[n] = item.Collection
+	}
+	selected, total := listRange(names, param)
 	var collections []*Collection
-	for _, collection := range res.Collections {
-		collections = append(collections, i.toCollection(collection))
+	for _, n := range selected {
+		coll := i.toCollection(res.Collections[n])
+		if !param.WithSchema {
+			coll.Indexes = Indexes{}
+		}
+		collections = append(collections, coll)
 	}
 	result := new(ListCollectionResult)
 	result.Collections = collections
+	result.Total = total
 	return result, nil
 }
 
@@ -219,6 +499,7 @@ func (i *implementerCollection) DescribeCollection(ctx context.Context, name str
 		return nil, fmt.Errorf("get collection %s failed", name)
 	}
 	coll := i.toCollection(res.Collection)
+	resolveAlias(coll, name)
 	result := new(DescribeCollectionResult)
 	result.Collection = *coll
 	return result, nil
@@ -239,9 +520,10 @@ func (i *implementerCollection) DropCollection(ctx context.Context, name string)
 
 	res := new(collection.DropRes)
 	err = i.Request(ctx, req, res)
+	i.database.schemas.invalidate(name)
 	result = new(DropCollectionResult)
 	if err != nil {
-		if strings.Contains(err.Error(), "not exist") {
+		if isNotExist(err) {
 			return result, nil
 		}
 		return
@@ -254,6 +536,7 @@ type TruncateCollectionResult struct {
 	AffectedCount int
 }
 
+// TruncateCollection clear all documents of the collection, the indexes and alias of the collection are kept.
 func (i *implementerCollection) TruncateCollection(ctx context.Context, name string) (result *TruncateCollectionResult, err error) {
 	if i.database.IsAIDatabase() {
 		return nil, AIDbTypeError
@@ -279,6 +562,7 @@ func (i *implementerCollection) Collection(name string) *Collection {
 	coll := new(Collection)
 	coll.DatabaseName = i.database.DatabaseName
 	coll.CollectionName = name
+	coll.schemas = i.database.schemas
 
 	flatImpl := new(implementerFlatDocument)
 	flatImpl.SdkClient = i.SdkClient
@@ -329,7 +613,8 @@ func (i *implementerCollection) toCollection(collectionItem *collection.Describe
 
 	if collectionItem.IndexStatus != nil {
 		coll.IndexStatus = IndexStatus{
-			Status: collectionItem.IndexStatus.Status,
+			Status:   collectionItem.IndexStatus.Status,
+			Progress: collectionItem.IndexStatus.Progress,
 		}
 		coll.IndexStatus.StartTime, _ = time.Parse("2006-01-02 15:04:05", collectionItem.IndexStatus.StartTime)
 	}
@@ -371,6 +656,16 @@ func (i *implementerCollection) toCollection(collectionItem *collection.Describe
 			vector.MetricType = MetricType(index.MetricType)
 			coll.Indexes.SparseVectorIndex = append(coll.Indexes.SparseVectorIndex, vector)
 
+		case string(BinaryVector):
+			vector := BinaryVectorIndex{}
+			vector.FieldName = index.FieldName
+			vector.FieldType = FieldType(index.FieldType)
+			vector.IndexType = IndexType(index.IndexType)
+			vector.Dimension = index.Dimension
+			vector.MetricType = MetricType(index.MetricType)
+			vector.IndexedCount = index.IndexedCount
+			coll.Indexes.BinaryVectorIndex = append(coll.Indexes.BinaryVectorIndex, vector)
+
 		case string(Array):
 			filter := FilterIndex{}
 			filter.FieldName = index.FieldName
@@ -384,6 +679,7 @@ func (i *implementerCollection) toCollection(collectionItem *collection.Describe
 			filter.FieldName = index.FieldName
 			filter.FieldType = FieldType(index.FieldType)
 			filter.IndexType = IndexType(index.IndexType)
+			filter.AutoId = index.AutoId
 			coll.Indexes.FilterIndex = append(coll.Indexes.FilterIndex, filter)
 		}
 	}
@@ -435,6 +731,109 @@ func optionParams(column *api.IndexColumn, v VectorIndex) {
 	}
 }
 
+// checkIndexParams checks the Params of vector indexes match their IndexType,
+// otherwise the params would be dropped silently by optionParams.
+func checkIndexParams(vectorIndexes []VectorIndex) error {
+	for _, v := range vectorIndexes {
+		if v.Params == nil || reflect.ValueOf(v.Params).IsNil() {
+			continue
+		}
+		var ok bool
+		switch v.IndexType {
+		case HNSW:
+			_, ok = v.Params.(*HNSWParam)
+		case IVF_FLAT:
+			_, ok = v.Params.(*IVFFLATParams)
+		case IVF_SQ4, IVF_SQ8, IVF_SQ16:
+			_, ok = v.Params.(*IVFSQParams)
+		case IVF_PQ:
+			_, ok = v.Params.(*IVFPQParams)
+		}
+		if !ok {
+			return fmt.Errorf("the params %s could not be used by the %s index of field %s", v.Params.Name(), v.IndexType, v.FieldName)
+		}
+	}
+	return nil
+}
+
+// checkMetricType checks the MetricType of vector indexes is supported by their FieldType,
+// the vector supports L2, IP and COSINE, the binary vector supports HAMMING and the sparse vector supports IP.
+// The empty MetricType is left to the server.
+func checkMetricType(indexes Indexes) error {
+	check := func(field string, metric MetricType, supported ...MetricType) error {
+		if metric == "" {
+			return nil
+		}
+		for _, m := range supported {
+			if metric == m {
+				return nil
+			}
+		}
+		return fmt.Errorf("the metric type %s of field %s is not supported, which must be one of %v", metric, field, supported)
+	}
+	for _, v := range indexes.VectorIndex {
+		if err := check(v.FieldName, v.MetricType, L2, IP, COSINE); err != nil {
+			return err
+		}
+	}
+	for _, v := range indexes.BinaryVectorIndex {
+		if err := check(v.FieldName, v.MetricType, HAMMING); err != nil {
+			return err
+		}
+	}
+	for _, v := range indexes.SparseVectorIndex {
+		if err := check(v.FieldName, v.MetricType, IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkShardReplica checks the shardNum is at least 1, and the shardNum and replicasNum are in
+// the bounds of ClientOption.
+func checkShardReplica(option ClientOption, shardNum, replicasNum uint32) error {
+	check := func(name string, val, min, max uint32) error {
+		if val >= min && (max == 0 || val <= max) {
+			return nil
+		}
+		if max == 0 {
+			return fmt.Errorf("invalid %s %d, which must be at least %d", name, val, min)
+		}
+		return fmt.Errorf("invalid %s %d, which must be in [%d, %d]", name, val, min, max)
+	}
+	if err := check("shardNum", shardNum, 1, option.MaxShardNum); err != nil {
+		return err
+	}
+	return check("replicasNum", replicasNum, 0, option.MaxReplicaNum)
+}
+
+// checkEmbeddingDimension checks the dimension of the vector index filled by the embedding
+// is the output dimension of the embedding model. The unknown model is left to the server.
+func checkEmbeddingDimension(indexes Indexes, params ...*CreateCollectionParams) error {
+	if len(params) == 0 || params[0] == nil || params[0].Embedding == nil {
+		return nil
+	}
+	embedding := params[0].Embedding
+	model := embedding.Model
+	if embedding.ModelName != "" {
+		model = EmbeddingModel(embedding.ModelName)
+	}
+	dimension := model.Dimension()
+	if dimension == 0 {
+		return nil
+	}
+	for _, v := range indexes.VectorIndex {
+		if v.FieldName != embedding.VectorField && (embedding.VectorField != "" || len(indexes.VectorIndex) != 1) {
+			continue
+		}
+		if v.Dimension != dimension {
+			return fmt.Errorf("the dimension %d of vector index %s does not match the embedding model %s, whose dimension is %d",
+				v.Dimension, v.FieldName, model, dimension)
+		}
+	}
+	return nil
+}
+
 // Collection wrap the collection parameters and document interface to operating the document api
 type Collection struct {
 	DocumentInterface `json:"-"`
@@ -452,6 +851,63 @@ type Collection struct {
 	Size              uint64      `json:"size"`
 	CreateTime        time.Time   `json:"createTime"`
 	TtlConfig         *TtlConfig  `json:"ttlConfig,omitempty"`
+	// ResolvedCollection: the collection which the alias points to, if the collection is described by an alias.
+	// The CollectionName keeps the alias, so the requests follow the alias switched to another collection.
+	ResolvedCollection string `json:"resolvedCollection,omitempty"`
+
+	dimension uint32
+	// schemaStale is set once a request of the alias fails the check and the alias is switched to another collection
+	schemaStale int32
+	// schemas: the cache of the schema used by the handle not described, nil if it is not enabled
+	schemas *databaseSchemas
+}
+
+// resolveAlias keeps name as the CollectionName of coll described by name, if name is an alias.
+func resolveAlias(coll *Collection, name string) {
+	if coll.CollectionName != name {
+		coll.ResolvedCollection, coll.CollectionName = coll.CollectionName, name
+	}
+}
+
+// schema returns the collection whose indexes are used to check the requests before sending,
+// it has no indexes if the schema resolved through the alias is stale, so the requests are checked by the server.
+func (c *Collection) schema(ctx context.Context) *Collection {
+	if atomic.LoadInt32(&c.schemaStale) != 0 {
+		return &Collection{DatabaseName: c.DatabaseName, CollectionName: c.CollectionName}
+	}
+	if c.cachesSchema() {
+		if coll := c.schemas.get(ctx, c.CollectionName); coll != nil {
+			return coll
+		}
+	}
+	return c
+}
+
+// cachesSchema returns true if the handle has no schema of its own and uses the cached one.
+func (c *Collection) cachesSchema() bool {
+	indexes := c.Indexes
+	return c.schemas != nil && c.dimension == 0 && len(indexes.VectorIndex) == 0 && len(indexes.SparseVectorIndex) == 0 &&
+		len(indexes.BinaryVectorIndex) == 0 && len(indexes.FilterIndex) == 0
+}
+
+// schemaError returns the error of the check of schema. The cached schema is invalidated, since the collection
+// may be recreated since it is described. The schema of the collection resolved through alias is marked stale
+// only if the server resolves the alias to another collection now, so a bad request does not turn off the checks.
+func (c *Collection) schemaError(ctx context.Context, err error) error {
+	if c.cachesSchema() {
+		c.schemas.invalidate(c.CollectionName)
+		return fmt.Errorf("%w, the cached schema of collection %s is invalidated", err, c.CollectionName)
+	}
+	if c.ResolvedCollection == "" || atomic.LoadInt32(&c.schemaStale) != 0 {
+		return err
+	}
+	db := (&implementerDatabase{SdkClient: c.DocumentInterface}).Database(c.DatabaseName)
+	res, descErr := db.DescribeAlias(ctx, c.CollectionName)
+	if descErr != nil || res.Collection == c.ResolvedCollection || atomic.SwapInt32(&c.schemaStale, 1) != 0 {
+		return err
+	}
+	return fmt.Errorf("%w, the alias %s is switched from collection %s to %s, the schema is not checked any more",
+		err, c.CollectionName, c.ResolvedCollection, res.Collection)
 }
 
 func (c *Collection) Debug(v bool) {
@@ -472,10 +928,107 @@ type Embedding struct {
 }
 
 type IndexStatus struct {
-	Status    string
+	// Status: ready, training, building or failed
+	Status string
+	// Progress: the progress of index building reported by the server, such as 35%
+	Progress  string
 	StartTime time.Time
 }
 
+// ProgressPercent parses the Progress as a percent in [0, 100], ok is false if the server reports no progress.
+func (s IndexStatus) ProgressPercent() (percent float64, ok bool) {
+	return parseProgressPercent(s.Progress)
+}
+
+// parseProgressPercent parses the progress such as 30% reported by the server as a percent in [0, 100].
+func parseProgressPercent(progress string) (percent float64, ok bool) {
+	percent, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(progress), "%")), 64)
+	if err != nil {
+		return 0, false
+	}
+	return math.Max(0, math.Min(100, percent)), true
+}
+
+// IndexReady reports whether the index of collection is ready, the IndexStatus is got by DescribeCollection.
+func (c *Collection) IndexReady() bool {
+	return c.IndexStatus.Status == IndexStatusReady
+}
+
+// defaultPollInterval is the interval of describing by the Wait functions if their pollInterval is not positive.
+const defaultPollInterval = time.Second
+
+// WaitForIndexReady describe the collection every pollInterval (default 1s) until its index is ready,
+// the index building fails, or the ctx is done. It returns the last described collection, c is not modified.
+func (c *Collection) WaitForIndexReady(ctx context.Context, pollInterval time.Duration) (*DescribeCollectionResult, error) {
+	return c.WaitIndexRebuilt(ctx, pollInterval, nil)
+}
+
+// WaitIndexRebuilt is WaitForIndexReady reporting the progress percent of each describe to onProgress,
+// used to follow the RebuildIndex after upserting with BuildIndex false. onProgress could be nil.
+func (c *Collection) WaitIndexRebuilt(ctx context.Context, pollInterval time.Duration,
+	onProgress func(percent float64)) (*DescribeCollectionResult, error) {
+	db := (&implementerDatabase{SdkClient: c.DocumentInterface}).Database(c.DatabaseName)
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		res, err := db.DescribeCollection(ctx, c.CollectionName)
+		if err != nil {
+			return nil, err
+		}
+		if onProgress != nil {
+			if res.IndexStatus.Status == IndexStatusReady {
+				onProgress(100)
+			} else if percent, ok := res.IndexStatus.ProgressPercent(); ok {
+				onProgress(percent)
+			}
+		}
+		switch res.IndexStatus.Status {
+		case IndexStatusReady:
+			return res, nil
+		case IndexStatusFailed:
+			return res, fmt.Errorf("collection %s index build failed", c.CollectionName)
+		}
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithDimension set the vector dimension to validate the vectors of Upsert and Search,
+// used for the collection got by Collection without describing it.
+func (c *Collection) WithDimension(dimension uint32) *Collection {
+	c.dimension = dimension
+	return c
+}
+
+// vectorDimension returns the dimension set by WithDimension or described from the server, 0 if unknown.
+func (c *Collection) vectorDimension() uint32 {
+	if c.dimension != 0 {
+		return c.dimension
+	}
+	for _, v := range c.Indexes.VectorIndex {
+		if v.Dimension != 0 {
+			return v.Dimension
+		}
+	}
+	return 0
+}
+
+// binaryVectorDimension returns the dimension of the BinaryVector index, 0 if unknown.
+func (c *Collection) binaryVectorDimension() uint32 {
+	for _, v := range c.Indexes.BinaryVectorIndex {
+		if v.Dimension != 0 {
+			return v.Dimension
+		}
+	}
+	return 0
+}
+
 type TtlConfig struct {
 	Enable    bool   `json:"enable"`
 	TimeField string `json:"timeField,omitempty"`
@@ -0,0 +1,219 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+type MigrateOption struct {
+	// CollectionName: the name of destination collection, default the name of source collection
+	CollectionName string
+	// Filter: copy the documents matching the filter, all documents if nil
+	Filter *Filter
+	// BatchSize: the documents queried and upserted per request, default 100
+	BatchSize int64
+	// Concurrency: the max batches upserted at the same time, default 1
+	Concurrency int
+	// RebuildIndex: upsert the documents without building index, and rebuild the index of destination at the end
+	RebuildIndex bool
+	// DryRun: only check the destination collection is absent or has the same indexes, no collection is created
+	DryRun bool
+	// Offset: the offset of source documents to start from, the offset of the last checkpoint to resume
+	Offset int64
+	// OnCheckpoint: called with the offset of source documents, before which all documents have been upserted
+	OnCheckpoint func(offset int64)
+}
+
+type MigrateResult struct {
+	// Created: false if the destination collection already exists
+	Created   bool
+	Documents int
+	// Bytes: the size of the documents copied, encoded as JSON
+	Bytes    int64
+	Duration time.Duration
+	// FailedDocuments: the documents rejected by the destination, whose Index is the offset in source
+	FailedDocuments []FailedDocument
+	// Offset: the offset of source documents migrated, used as MigrateOption.Offset to resume after a failure
+	Offset int64
+}
+
+// MigrateCollection copy the collection src into the database dst, which could be on another client.
+// The destination collection is created with the schema of src if it does not exist, otherwise its indexes
+// must be the same as src. The documents are queried page by page, see QueryIterator for the consistency.
+// The batches upserted before a failure are not rolled back, resume from MigrateResult.Offset.
+func MigrateCollection(ctx context.Context, src *Collection, dst *Database, option MigrateOption) (*MigrateResult, error) {
+	start := time.Now()
+	result := &MigrateResult{Offset: option.Offset}
+	srcDb := (&implementerDatabase{SdkClient: src.DocumentInterface}).Database(src.DatabaseName)
+	desc, err := srcDb.DescribeCollection(ctx, src.CollectionName)
+	if err != nil {
+		return nil, err
+	}
+	schema := desc.Collection
+	name := option.CollectionName
+	if name == "" {
+		name = schema.CollectionName
+	}
+	params := &CreateCollectionParams{TtlConfig: schema.TtlConfig}
+	if schema.Embedding.Field != "" {
+		params.Embedding = &schema.Embedding
+	}
+
+	if option.DryRun {
+		existing, err := dst.DescribeCollection(ctx, name)
+		if err != nil {
+			if IsCollectionNotExist(err) {
+				return result, nil
+			}
+			return nil, err
+		}
+		diffs := diffIndexes(schema.Indexes, existing.Indexes)
+		if diff := diffTtlConfig(schema.TtlConfig, existing.TtlConfig); diff != "" {
+			diffs = append(diffs, diff)
+		}
+		if len(diffs) != 0 {
+			return nil, &SchemaMismatchError{Collection: name, Diffs: diffs}
+		}
+		return result, nil
+	}
+
+	created, err := dst.CreateCollectionIfNotExists(ctx, name, schema.ShardNum, schema.ReplicasNum,
+		schema.Description, schema.Indexes, params)
+	if err != nil {
+		return nil, err
+	}
+	result.Created = created.Created
+	target := &created.Collection
+
+	concurrency := option.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	upsertParams := &UpsertDocumentParams{DiagnoseOnFailure: true}
+	if option.RebuildIndex {
+		buildIndex := false
+		upsertParams.BuildIndex = &buildIndex
+	}
+	// the iterator shares the ctx canceled on the first failure, so that no more pages are queried
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// the vectors are generated again by the embedding of destination
+	it := src.QueryIterator(ctx, option.Filter, option.BatchSize, &QueryDocumentParams{RetrieveVector: params.Embedding == nil})
+	it.SetOffset(option.Offset)
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		// finished the end offsets of the upserted batches by their start offsets, merged into result.Offset in order
+		finished = make(map[int64]int64)
+	)
+	sem := make(chan struct{}, concurrency)
+	upsert := func(offset int64, docs []Document) {
+		defer func() {
+			<-sem
+			wg.Done()
+		}()
+		res, err := target.Upsert(ctx, docs, upsertParams)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("migrate the documents from offset %d failed: %w", offset, err)
+				cancel()
+			}
+			return
+		}
+		for _, f := range res.FailedDocuments {
+			f.Index += int(offset)
+			result.FailedDocuments = append(result.FailedDocuments, f)
+		}
+		result.Documents += len(docs) - len(res.FailedDocuments)
+		for _, doc := range docs {
+			if data, err := json.Marshal(exportDocument(doc)); err == nil {
+				result.Bytes += int64(len(data))
+			}
+		}
+		finished[offset] = offset + int64(len(docs))
+		advanced := false
+		for end, ok := finished[result.Offset]; ok; end, ok = finished[result.Offset] {
+			delete(finished, result.Offset)
+			result.Offset, advanced = end, true
+		}
+		if advanced && option.OnCheckpoint != nil {
+			option.OnCheckpoint(result.Offset)
+		}
+	}
+
+	fail := func(offset int64, err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = fmt.Errorf("migrate failed at offset %d: %w", offset, err)
+		}
+		mu.Unlock()
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+	for !it.Done() && !failed() {
+		offset := it.Offset()
+		docs, err := it.Next()
+		if err != nil {
+			fail(offset, err)
+			break
+		}
+		if len(docs) == 0 {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fail(offset, ctx.Err())
+		}
+		if failed() {
+			break
+		}
+		wg.Add(1)
+		go upsert(offset, docs)
+	}
+	wg.Wait()
+	sort.Slice(result.FailedDocuments, func(i, j int) bool {
+		return result.FailedDocuments[i].Index < result.FailedDocuments[j].Index
+	})
+	if firstErr != nil {
+		result.Duration = time.Since(start)
+		return result, firstErr
+	}
+
+	if option.RebuildIndex {
+		if _, err = target.RebuildIndex(ctx); err != nil {
+			result.Duration = time.Since(start)
+			return result, fmt.Errorf("rebuild the index of %s failed: %w", name, err)
+		}
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+func TestMigrateCollection(t *testing.T) {
+	const describe = `{"code":0,"collection":{"database":"prod","collection":"coll","shardNum":2,"replicaNum":1,"indexes":[
+		{"fieldName":"id","fieldType":"string","indexType":"primaryKey"},
+		{"fieldName":"vector","fieldType":"vector","indexType":"HNSW","dimension":3,"metricType":"COSINE"},
+		{"fieldName":"page","fieldType":"uint64","indexType":"filter"}]}}`
+	var queries []string
+	src := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collection/describe":
+			w.Write([]byte(describe))
+		case "/document/query":
+			var req document.QueryReq
+			json.NewDecoder(r.Body).Decode(&req)
+			queries = append(queries, req.Query.Filter)
+			var docs []string
+			for i := req.Query.Offset; i < 5 && i < req.Query.Offset+req.Query.Limit; i++ {
+				docs = append(docs, fmt.Sprintf(`{"id":"%04d","vector":[0.1,0.2,0.3],"page":%d}`, i, i))
+			}
+			fmt.Fprintf(w, `{"code":0,"count":%d,"documents":[%s]}`, len(docs), strings.Join(docs, ","))
+		}
+	}, ClientOption{})
+
+	var (
+		mu       sync.Mutex
+		exists   bool
+		created  string
+		upserted []string
+		rebuilt  bool
+	)
+	dst := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/collection/describe":
+			if !exists {
+				w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+				return
+			}
+			w.Write([]byte(strings.Replace(describe, `"indexType":"filter"`, `"indexType":"filter"},{"fieldName":"tag","fieldType":"string","indexType":"filter"`, 1)))
+		case "/collection/create":
+			created = string(body)
+			w.Write([]byte(`{"code":0}`))
+		case "/document/upsert":
+			var req document.UpsertReq
+			json.Unmarshal(body, &req)
+			if req.BuildIndex == nil || *req.BuildIndex {
+				t.Errorf("expect upsert without building index, got %s", body)
+			}
+			for _, doc := range req.Documents {
+				if doc.Id == "0003" {
+					w.Write([]byte(`{"code":15000,"msg":"invalid document"}`))
+					return
+				}
+			}
+			for _, doc := range req.Documents {
+				upserted = append(upserted, doc.Id)
+			}
+			w.Write([]byte(`{"code":0}`))
+		case "/index/rebuild":
+			rebuilt = true
+			w.Write([]byte(`{"code":0}`))
+		}
+	}, ClientOption{})
+	coll := src.Database("prod").Collection("coll")
+
+	res, err := MigrateCollection(context.Background(), coll, dst.Database("staging"), MigrateOption{DryRun: true})
+	if err != nil || res.Documents != 0 || created != "" || len(queries) != 0 {
+		t.Fatalf("expect dry run without writes, got %+v, %v", res, err)
+	}
+
+	var checkpoints []int64
+	res, err = MigrateCollection(context.Background(), coll, dst.Database("staging"), MigrateOption{
+		Filter: NewFilter(Gt("page", 0)), BatchSize: 2, Concurrency: 2, RebuildIndex: true, Offset: 1,
+		OnCheckpoint: func(offset int64) { checkpoints = append(checkpoints, offset) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Created || !strings.Contains(created, `"database":"staging"`) || !strings.Contains(created, `"shardNum":2`) ||
+		!strings.Contains(created, `"fieldName":"page"`) {
+		t.Errorf("expect collection created with the schema of source, got %s", created)
+	}
+	if res.Documents != 3 || len(res.FailedDocuments) != 1 || res.FailedDocuments[0].Index != 3 ||
+		res.FailedDocuments[0].Id != "0003" || res.Offset != 5 || res.Bytes == 0 || !rebuilt {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if len(upserted) != 3 || queries[0] != "page > 0" {
+		t.Errorf("unexpected upserted %v, queries %v", upserted, queries)
+	}
+	if len(checkpoints) == 0 || checkpoints[len(checkpoints)-1] != 5 {
+		t.Errorf("expect checkpoints up to 5, got %v", checkpoints)
+	}
+
+	exists = true
+	_, err = MigrateCollection(context.Background(), coll, dst.Database("staging"), MigrateOption{DryRun: true})
+	var mismatch *SchemaMismatchError
+	if !errors.As(err, &mismatch) || len(mismatch.Diffs) != 1 || !strings.Contains(mismatch.Diffs[0], "tag") {
+		t.Errorf("expect SchemaMismatchError of tag, got %v", err)
+	}
+}
@@ -0,0 +1,418 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForIndexReady(t *testing.T) {
+	var calls int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Write([]byte(`{"code":0,"collection":{"collection":"coll","documentCount":10,"indexStatus":{"status":"building","progress":"50%"}}}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"collection":{"collection":"coll","documentCount":10,"indexStatus":{"status":"ready"}}}`))
+	}, ClientOption{})
+
+	coll := cli.Database("db").Collection("coll")
+	if coll.IndexReady() {
+		t.Error("expect index not ready before describe")
+	}
+	res, err := coll.WaitForIndexReady(context.Background(), time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IndexReady() || res.DocumentCount != 10 || calls != 3 {
+		t.Errorf("unexpected collection after wait, status %+v, documentCount %d, calls %d", res.IndexStatus, res.DocumentCount, calls)
+	}
+	if coll.IndexReady() || coll.DocumentCount != 0 {
+		t.Errorf("expect the collection handle not modified, got %+v", coll.IndexStatus)
+	}
+}
+
+func TestWaitForIndexReadyDefaultInterval(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"collection":{"collection":"coll","indexStatus":{"status":"ready"}}}`))
+	}, ClientOption{})
+	if _, err := cli.Database("db").Collection("coll").WaitForIndexReady(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitIndexRebuilt(t *testing.T) {
+	var describes int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index/rebuild" {
+			w.Write([]byte(`{"code":0,"task_ids":["task-1"]}`))
+			return
+		}
+		switch atomic.AddInt32(&describes, 1) {
+		case 1:
+			w.Write([]byte(`{"code":0,"collection":{"collection":"coll","indexStatus":{"status":"building","progress":"20%"}}}`))
+		case 2:
+			w.Write([]byte(`{"code":0,"collection":{"collection":"coll","indexStatus":{"status":"building"}}}`))
+		default:
+			w.Write([]byte(`{"code":0,"collection":{"collection":"coll","indexStatus":{"status":"failed","progress":"80%"}}}`))
+		}
+	}, ClientOption{})
+
+	coll := cli.Database("db").Collection("coll")
+	rebuild, err := coll.RebuildIndex(context.Background())
+	if err != nil || len(rebuild.TaskIds) != 1 || rebuild.TaskIds[0] != "task-1" {
+		t.Fatalf("unexpected rebuild result %+v, %v", rebuild, err)
+	}
+	var progress []float64
+	res, err := coll.WaitIndexRebuilt(context.Background(), time.Millisecond, func(percent float64) {
+		progress = append(progress, percent)
+	})
+	if err == nil || res.IndexStatus.Status != IndexStatusFailed || len(progress) != 2 || progress[0] != 20 || progress[1] != 80 {
+		t.Errorf("expect failed rebuild with progress 20 and 80, got %v, %v", progress, err)
+	}
+}
+
+func TestCreateCollectionIfNotExists(t *testing.T) {
+	var creates int32
+	exists := false
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collection/create":
+			atomic.AddInt32(&creates, 1)
+			w.Write([]byte(`{"code":0}`))
+		case "/collection/describe":
+			if !exists {
+				w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"collection":{"collection":"coll","indexes":[
+				{"fieldName":"id","fieldType":"string","indexType":"primaryKey"},
+				{"fieldName":"vector","fieldType":"vector","indexType":"HNSW","dimension":3,"metricType":"COSINE"},
+				{"fieldName":"tags","fieldType":"array","fieldElementType":"string","indexType":"filter"}]}}`))
+		}
+	}, ClientOption{})
+	db := cli.Database("db")
+	indexes := Indexes{
+		VectorIndex: []VectorIndex{{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW}, Dimension: 3, MetricType: COSINE}},
+		FilterIndex: []FilterIndex{
+			{FieldName: "id", FieldType: String, IndexType: PRIMARY},
+			{FieldName: "tags", FieldType: Array, IndexType: FILTER},
+		},
+	}
+
+	res, err := db.CreateCollectionIfNotExists(context.Background(), "coll", 1, 1, "", indexes)
+	if err != nil || !res.Created || creates != 1 {
+		t.Fatalf("expect collection created, res %+v, err %v", res, err)
+	}
+	exists = true
+	res, err = db.CreateCollectionIfNotExists(context.Background(), "coll", 1, 1, "", indexes)
+	if err != nil || res.Created || creates != 1 || res.CollectionName != "coll" {
+		t.Fatalf("expect the existing collection returned, res %+v, err %v", res, err)
+	}
+
+	indexes.VectorIndex[0].Dimension = 768
+	indexes.FilterIndex = indexes.FilterIndex[:1]
+	_, err = db.CreateCollectionIfNotExists(context.Background(), "coll", 1, 1, "", indexes)
+	var mismatch *SchemaMismatchError
+	if !errors.As(err, &mismatch) || len(mismatch.Diffs) != 2 {
+		t.Errorf("expect SchemaMismatchError of vector and tags, got %v", err)
+	}
+
+	indexes.VectorIndex[0].Dimension = 3
+	indexes.FilterIndex = append(indexes.FilterIndex, FilterIndex{FieldName: "tags", FieldType: Array, IndexType: FILTER})
+	ttl := &CreateCollectionParams{TtlConfig: &TtlConfig{Enable: true, TimeField: "expire_at"}}
+	_, err = db.CreateCollectionIfNotExists(context.Background(), "coll", 1, 1, "", indexes, ttl)
+	if !errors.As(err, &mismatch) || len(mismatch.Diffs) != 1 || !strings.Contains(mismatch.Diffs[0], "TTL") {
+		t.Errorf("expect SchemaMismatchError of enabling TTL on the existing collection, got %v", err)
+	}
+}
+
+func TestCreateCollectionIndexParams(t *testing.T) {
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{})
+	db := cli.Database("db")
+	index := VectorIndex{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: IVF_PQ}, Dimension: 4, MetricType: L2}
+
+	index.Params = &IVFPQParams{NList: 128, M: 2}
+	_, err := db.CreateCollection(context.Background(), "coll", 1, 1, "", Indexes{VectorIndex: []VectorIndex{index}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, `"params":{"M":2,"nlist":128}`) {
+		t.Errorf("expect IVF_PQ params in request, got %s", body)
+	}
+
+	index.IndexType = HNSW
+	_, err = db.CreateCollection(context.Background(), "coll", 1, 1, "", Indexes{VectorIndex: []VectorIndex{index}})
+	if err == nil {
+		t.Error("expect error of IVF_PQ params on HNSW index")
+	}
+
+	body = ""
+	index.Params, index.MetricType = nil, "cosine"
+	_, err = db.CreateCollection(context.Background(), "coll", 1, 1, "", Indexes{VectorIndex: []VectorIndex{index}})
+	if err == nil || body != "" {
+		t.Errorf("expect unknown metric type rejected before sending, got %v", err)
+	}
+	binary := BinaryVectorIndex{FieldName: "vector", FieldType: BinaryVector, IndexType: BIN_FLAT, Dimension: 16, MetricType: COSINE}
+	_, err = db.CreateCollection(context.Background(), "coll", 1, 1, "", Indexes{BinaryVectorIndex: []BinaryVectorIndex{binary}})
+	if err == nil {
+		t.Error("expect COSINE rejected for the binary vector")
+	}
+
+	body = ""
+	index.MetricType = COSINE
+	embedding := &CreateCollectionParams{Embedding: &Embedding{Field: "text", VectorField: "vector", ModelName: string(BGE_BASE_ZH)}}
+	_, err = db.CreateCollection(context.Background(), "coll", 1, 1, "", Indexes{VectorIndex: []VectorIndex{index}}, embedding)
+	if err == nil || !strings.Contains(err.Error(), "768") || body != "" {
+		t.Errorf("expect dimension mismatch of embedding model rejected before sending, got %v", err)
+	}
+	index.Dimension = BGE_BASE_ZH.Dimension()
+	embedding.TtlConfig = &TtlConfig{Enable: true, TimeField: "expire_at"}
+	coll, err := db.CreateCollection(context.Background(), "coll", 1, 1, "", Indexes{VectorIndex: []VectorIndex{index}}, embedding)
+	if err != nil || !strings.Contains(body, `"model":"bge-base-zh"`) {
+		t.Errorf("expect embedding collection created, got %v, body %s", err, body)
+	}
+	if coll.Embedding.Field != "text" || coll.TtlConfig == nil || coll.TtlConfig.TimeField != "expire_at" {
+		t.Errorf("expect the created collection with the embedding and ttl, got %+v %+v", coll.Embedding, coll.TtlConfig)
+	}
+	if _, err = coll.SearchByText(context.Background(), map[string][]string{"text": {"a"}}); err != nil {
+		t.Errorf("expect SearchByText on the created collection, got %v", err)
+	}
+	embedding.TtlConfig = nil
+	embedding.Embedding.ModelName = "custom-model"
+	index.Dimension = 4
+	if _, err = db.CreateCollection(context.Background(), "coll", 1, 1, "", Indexes{VectorIndex: []VectorIndex{index}}, embedding); err != nil {
+		t.Errorf("expect unknown model left to server, got %v", err)
+	}
+}
+
+func TestCreateCollectionShardReplica(t *testing.T) {
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{MaxShardNum: 8, MaxReplicaNum: 2})
+	db := cli.Database("db")
+	indexes := Indexes{FilterIndex: []FilterIndex{{FieldName: "id", FieldType: String, IndexType: PRIMARY}}}
+
+	_, err := db.CreateCollection(context.Background(), "coll", 0, 1, "", indexes)
+	if err == nil || !strings.Contains(err.Error(), "shardNum") || body != "" {
+		t.Errorf("expect shardNum 0 rejected before sending, got %v", err)
+	}
+	_, err = db.CreateCollection(context.Background(), "coll", 1, 3, "", indexes)
+	if err == nil || !strings.Contains(err.Error(), "replicasNum 3, which must be in [0, 2]") {
+		t.Errorf("expect replicasNum over bound rejected, got %v", err)
+	}
+	coll, err := db.CreateCollectionBySpec(context.Background(), CollectionSpec{Name: "coll", ShardNum: 2, ReplicaNum: 2,
+		Indexes: indexes, TtlConfig: &TtlConfig{Enable: true, TimeField: "expire_at"}})
+	if err != nil || coll.ShardNum != 2 || !strings.Contains(body, `"ttlConfig":{"enable":true,"timeField":"expire_at"}`) {
+		t.Errorf("expect collection created by spec, got %v, body %s", err, body)
+	}
+}
+
+func TestEmbeddingModels(t *testing.T) {
+	models := EmbeddingModels()
+	if len(models) != 7 || models[0] != BAAI_BGE_M3 {
+		t.Errorf("unexpected embedding models %v", models)
+	}
+	if M3E_BASE.Dimension() != 768 || E5_LARGE_V2.Dimension() != 1024 || EmbeddingModel("unknown").Dimension() != 0 {
+		t.Error("unexpected embedding model dimension")
+	}
+}
+
+func TestFlatCollectionAndAlias(t *testing.T) {
+	var requests []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.URL.Path+" "+strings.TrimSpace(string(body)))
+		if r.URL.Path == "/collection/describe" {
+			w.Write([]byte(`{"code":0,"collection":{"database":"db","collection":"coll","alias":["a"]}}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"affectedCount":1}`))
+	}, ClientOption{})
+
+	ctx := context.Background()
+	res, err := cli.DescribeCollection(ctx, "db", "coll")
+	if err != nil || res.DatabaseName != "db" || res.CollectionName != "coll" || len(res.Alias) != 1 {
+		t.Fatalf("unexpected described collection %+v, err %v", res, err)
+	}
+	if _, err = cli.SetAlias(ctx, "db", "coll", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.DeleteAlias(ctx, "db", "a"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`/collection/describe {"database":"db","collection":"coll"}`,
+		`/alias/set {"database":"db","collection":"coll","alias":"a"}`,
+		`/alias/delete {"database":"db","alias":"a"}`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
+
+func TestListCollectionParams(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"collections":[
+			{"database":"db","collection":"user_a","indexes":[{"fieldName":"id","fieldType":"string","indexType":"primaryKey"}]},
+			{"database":"db","collection":"item_a","indexes":[{"fieldName":"id","fieldType":"string","indexType":"primaryKey"}]},
+			{"database":"db","collection":"user_b","indexes":[{"fieldName":"id","fieldType":"string","indexType":"primaryKey"}]},
+			{"database":"db","collection":"user_c","indexes":[{"fieldName":"id","fieldType":"string","indexType":"primaryKey"}]}]}`))
+	}, ClientOption{})
+	db := cli.Database("db")
+
+	all, err := db.ListCollection(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all.Total != 4 || len(all.Collections) != 4 || len(all.Collections[0].Indexes.FilterIndex) != 1 {
+		t.Errorf("expect all collections with schema, got %+v", all)
+	}
+
+	page, err := db.ListCollection(context.Background(), &ListCollectionParams{NamePrefix: "user_", Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || len(page.Collections) != 1 || page.Collections[0].CollectionName != "user_b" ||
+		len(page.Collections[0].Indexes.FilterIndex) != 0 {
+		t.Errorf("unexpected collections page %+v", page)
+	}
+	page, err = db.ListCollection(context.Background(), &ListCollectionParams{NamePrefix: "item_", WithSchema: true})
+	if err != nil || len(page.Collections) != 1 || len(page.Collections[0].Indexes.FilterIndex) != 1 {
+		t.Errorf("expect collection with schema, got %+v, %v", page, err)
+	}
+}
+
+func TestModifyCollection(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		renamed bool
+		calls   []string
+	)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.URL.Path)
+		var req struct{ Collection string }
+		json.Unmarshal(body, &req)
+		switch r.URL.Path {
+		case "/collection/describe":
+			if req.Collection == "coll_v2" && !renamed {
+				w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+				return
+			}
+			if req.Collection == "coll_v1" {
+				w.Write([]byte(`{"code":0,"collection":{"database":"db","collection":"coll_v1","exists":true}}`))
+				return
+			}
+			fmt.Fprintf(w, `{"code":0,"collection":{"database":"db","collection":%q,"description":"old","shardNum":1,
+				"replicaNum":1,"alias":["live"],"indexes":[{"fieldName":"id","fieldType":"string","indexType":"primaryKey"}]}}`, req.Collection)
+		case "/collection/create":
+			if !strings.Contains(string(body), `"description":"new"`) {
+				t.Errorf("expect the new description, got %s", body)
+			}
+			renamed = true
+			w.Write([]byte(`{"code":0}`))
+		case "/document/query":
+			w.Write([]byte(`{"code":0,"documents":[{"id":"0001"}]}`))
+		case "/alias/set":
+			if !strings.Contains(string(body), `"collection":"coll_v2","alias":"live"`) {
+				t.Errorf("expect alias moved to coll_v2, got %s", body)
+			}
+			w.Write([]byte(`{"code":0}`))
+		default:
+			w.Write([]byte(`{"code":0}`))
+		}
+	}, ClientOption{})
+	db := cli.Database("db")
+	newName, description := "coll_v2", "new"
+
+	_, err := db.ModifyCollection(context.Background(), "coll", ModifyCollectionParams{NewName: &newName})
+	if err == nil || !strings.Contains(err.Error(), "EmulateRename") {
+		t.Errorf("expect rename rejected without EmulateRename, got %v", err)
+	}
+	param := ModifyCollectionParams{NewName: &newName, Description: &description, EmulateRename: true}
+	_, err = db.ModifyCollection(context.Background(), "coll", param)
+	var renameErr *RenameCollectionError
+	if !errors.As(err, &renameErr) || renameErr.Exists || len(renameErr.Aliases) != 1 {
+		t.Errorf("expect RenameCollectionError of aliases, got %v", err)
+	}
+	existing := "coll_v1"
+	_, err = db.ModifyCollection(context.Background(), "coll", ModifyCollectionParams{NewName: &existing, EmulateRename: true})
+	if !errors.As(err, &renameErr) || !renameErr.Exists {
+		t.Errorf("expect RenameCollectionError of existing collection, got %v", err)
+	}
+
+	calls = nil
+	param.MoveAliases = true
+	res, err := db.ModifyCollection(context.Background(), "coll", param)
+	if err != nil || res.CollectionName != "coll_v2" {
+		t.Fatalf("unexpected result %+v, %v", res, err)
+	}
+	if joined := strings.Join(calls, " "); !strings.Contains(joined, "/collection/create") || !strings.Contains(joined, "/document/upsert") ||
+		!strings.HasSuffix(joined, "/alias/set /collection/drop /collection/describe") {
+		t.Errorf("unexpected requests %s", joined)
+	}
+}
+
+func TestModifyCollectionKeepsSourceOnFailedDocuments(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		dropped []string
+	)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		var req struct{ Collection string }
+		json.Unmarshal(body, &req)
+		switch r.URL.Path {
+		case "/collection/describe":
+			if req.Collection == "coll_v2" {
+				w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"collection":{"database":"db","collection":"coll","shardNum":1,"replicaNum":1,
+				"documentCount":2,"indexes":[{"fieldName":"id","fieldType":"string","indexType":"primaryKey"}]}}`))
+		case "/document/query":
+			w.Write([]byte(`{"code":0,"documents":[{"id":"0001"},{"id":"0002"}]}`))
+		case "/document/upsert":
+			if strings.Contains(string(body), `"0002"`) {
+				w.Write([]byte(`{"code":1,"msg":"invalid document"}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"affectedCount":1}`))
+		case "/collection/drop":
+			dropped = append(dropped, req.Collection)
+			w.Write([]byte(`{"code":0}`))
+		default:
+			w.Write([]byte(`{"code":0}`))
+		}
+	}, ClientOption{})
+	newName := "coll_v2"
+	param := ModifyCollectionParams{NewName: &newName, EmulateRename: true, Migrate: MigrateOption{Offset: 1}}
+	_, err := cli.Database("db").ModifyCollection(context.Background(), "coll", param)
+	if err == nil || !strings.Contains(err.Error(), "1 documents are rejected") {
+		t.Errorf("expect the rename failed of the rejected document, got %v", err)
+	}
+	if len(dropped) != 1 || dropped[0] != "coll_v2" {
+		t.Errorf("expect only the copy dropped and the source kept, dropped %v", dropped)
+	}
+}
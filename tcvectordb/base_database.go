@@ -21,7 +21,6 @@ package tcvectordb
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/ai_database"
@@ -37,7 +36,8 @@ type DatabaseInterface interface {
 	CreateDatabaseIfNotExists(ctx context.Context, name string) (*CreateDatabaseResult, error)
 	CreateDatabase(ctx context.Context, name string) (*CreateDatabaseResult, error)
 	DropDatabase(ctx context.Context, name string) (*DropDatabaseResult, error)
-	ListDatabase(ctx context.Context) (result *ListDatabaseResult, err error)
+	ListDatabase(ctx context.Context, params ...*ListDatabaseParams) (result *ListDatabaseResult, err error)
+	DescribeDatabase(ctx context.Context, name string) (result *DescribeDatabaseResult, err error)
 	CreateAIDatabase(ctx context.Context, name string) (result *CreateAIDatabaseResult, err error)
 	DropAIDatabase(ctx context.Context, name string) (result *DropAIDatabaseResult, err error)
 	Database(name string) *Database
@@ -51,6 +51,8 @@ type implementerDatabase struct {
 type CreateDatabaseResult struct {
 	Database
 	AffectedCount int
+	// Created: false if the database already exists, set by CreateDatabaseIfNotExists
+	Created bool
 }
 
 func (i *implementerDatabase) ExistsDatabase(ctx context.Context, name string) (bool, error) {
@@ -94,6 +96,7 @@ func (i *implementerDatabase) CreateDatabase(ctx context.Context, name string) (
 	result = new(CreateDatabaseResult)
 	result.AffectedCount = res.AffectedCount
 	result.Database = *(i.Database(name))
+	result.Created = true
 	return result, err
 }
 
@@ -130,7 +133,7 @@ func (i *implementerDatabase) DropDatabase(ctx context.Context, name string) (re
 	res := new(database.DropRes)
 	err = i.Request(ctx, req, res)
 	if err != nil {
-		if strings.Contains(err.Error(), "not exist") || strings.Contains(err.Error(), "can not find database") {
+		if isNotExist(err) {
 			return result, nil
 		}
 		return
@@ -151,7 +154,7 @@ func (i *implementerDatabase) DropAIDatabase(ctx context.Context, name string) (
 	res := new(ai_database.DropRes)
 	err = i.Request(ctx, req, res)
 	if err != nil {
-		if strings.Contains(err.Error(), "not exist") {
+		if isNotExist(err) {
 			return result, nil
 		}
 		return
@@ -160,13 +163,35 @@ func (i *implementerDatabase) DropAIDatabase(ctx context.Context, name string) (
 	return
 }
 
+// ListDatabaseParams the paging of ListDatabase, which is applied by the client in the order returned by the server.
+type ListDatabaseParams struct {
+	// Offset: skip the first Offset databases, counting both base and AI databases
+	Offset int
+	// Limit: the max number of databases returned, 0 means no limit
+	Limit int
+}
+
 type ListDatabaseResult struct {
 	Databases   []Database
 	AIDatabases []AIDatabase
+	// Total: the number of all databases, regardless of Offset and Limit
+	Total int
+}
+
+func listDatabaseNames(names []string, params []*ListDatabaseParams) []string {
+	if len(params) == 0 || params[0] == nil {
+		return names
+	}
+	selected, _ := listRange(names, &ListCollectionParams{Offset: params[0].Offset, Limit: params[0].Limit})
+	page := make([]string, 0, len(selected))
+	for _, n := range selected {
+		page = append(page, names[n])
+	}
+	return page
 }
 
 // ListDatabase get database list. It returns the database list to operate the collection.
-func (i *implementerDatabase) ListDatabase(ctx context.Context) (result *ListDatabaseResult, err error) {
+func (i *implementerDatabase) ListDatabase(ctx context.Context, params ...*ListDatabaseParams) (result *ListDatabaseResult, err error) {
 	req := database.ListReq{}
 	res := new(database.ListRes)
 	err = i.Request(ctx, req, res)
@@ -175,21 +200,56 @@ func (i *implementerDatabase) ListDatabase(ctx context.Context) (result *ListDat
 	}
 
 	result = new(ListDatabaseResult)
-	for _, v := range res.Databases {
+	result.Total = len(res.Databases)
+	for _, v := range listDatabaseNames(res.Databases, params) {
 		if res.Info[v].DbType == AIDOCDbType || res.Info[v].DbType == DbTypeAI {
 			db := i.AIDatabase(v)
 			db.Info.CreateTime = res.Info[v].CreateTime
+			db.Info.Count = res.Info[v].Count
 			result.AIDatabases = append(result.AIDatabases, *db)
 		} else {
 			db := i.Database(v)
 			db.Info.CreateTime = res.Info[v].CreateTime
 			db.Info.DbType = res.Info[v].DbType
+			db.Info.Count = res.Info[v].Count
 			result.Databases = append(result.Databases, *db)
 		}
 	}
 	return result, nil
 }
 
+type DescribeDatabaseResult struct {
+	// Database: the Info is described from the server, use AIDatabase to operate it if IsAIDatabase.
+	Database
+	// AIDatabase: set only if the database IsAIDatabase, with the same Info
+	AIDatabase *AIDatabase
+}
+
+// DescribeDatabase get the create time, db type and collection count of the database.
+func (i *implementerDatabase) DescribeDatabase(ctx context.Context, name string) (*DescribeDatabaseResult, error) {
+	req := database.DescribeReq{Database: name}
+	res := new(database.DescribeRes)
+	err := i.Request(ctx, req, res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Database == nil {
+		return nil, fmt.Errorf("get database %s failed", name)
+	}
+	db := i.Database(name)
+	db.Info.CreateTime = res.Database.CreateTime
+	if res.Database.DbType != "" {
+		db.Info.DbType = res.Database.DbType
+	}
+	db.Info.Count = res.Database.Count
+	result := &DescribeDatabaseResult{Database: *db}
+	if db.IsAIDatabase() {
+		result.AIDatabase = i.AIDatabase(name)
+		result.AIDatabase.Info = db.Info
+	}
+	return result, nil
+}
+
 // Database get a database interface to operate collection.  It could not send http request to vectordb.
 func (i *implementerDatabase) Database(name string) *Database {
 	database := new(Database)
@@ -197,6 +257,7 @@ func (i *implementerDatabase) Database(name string) *Database {
 	database.Info = DatabaseItem{
 		DbType: DbTypeBase,
 	}
+	database.schemas = databaseSchemasOf(i.SdkClient, name)
 
 	collImpl := new(implementerCollection)
 	collImpl.SdkClient = i.SdkClient
@@ -245,6 +306,8 @@ type Database struct {
 	IndexInterface      `json:"-"`
 	DatabaseName        string
 	Info                DatabaseItem
+
+	schemas *databaseSchemas
 }
 
 func (d Database) IsAIDatabase() bool {
@@ -254,6 +317,37 @@ func (d Database) IsAIDatabase() bool {
 type DatabaseItem struct {
 	CreateTime string `json:"createTime,omitempty"`
 	DbType     string `json:"dbType,omitempty"`
+	// Count: the number of collections in the database
+	Count int64 `json:"count,omitempty"`
+}
+
+// CollectionSpec the parameters of CreateCollectionBySpec, instead of the positional ones of CreateCollection.
+type CollectionSpec struct {
+	Name string
+	// ShardNum: at least 1
+	ShardNum   uint32
+	ReplicaNum uint32
+	// Description: could be empty
+	Description string
+	Indexes     Indexes
+	// Embedding, TtlConfig: optional, same as CreateCollectionParams
+	Embedding *Embedding
+	TtlConfig *TtlConfig
+	// Schema: optional, the Indexes and Embedding are built by it if it is set
+	Schema *SchemaBuilder
+}
+
+// CreateCollectionBySpec create a collection by spec, same as CreateCollection.
+func (d *Database) CreateCollectionBySpec(ctx context.Context, spec CollectionSpec) (*Collection, error) {
+	if spec.Schema != nil {
+		var err error
+		spec.Indexes, spec.Embedding, err = spec.Schema.Build()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return d.CreateCollection(ctx, spec.Name, spec.ShardNum, spec.ReplicaNum, spec.Description, spec.Indexes,
+		&CreateCollectionParams{Embedding: spec.Embedding, TtlConfig: spec.TtlConfig})
 }
 
 func (d *Database) Debug(v bool) {
@@ -0,0 +1,91 @@
+package tcvectordb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDescribeDatabase(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/database/describe":
+			w.Write([]byte(`{"code":0,"database":{"database":"ai","createTime":"2024-01-01 00:00:00","dbType":"AI_DOC","count":2}}`))
+		case "/database/list":
+			w.Write([]byte(`{"code":0,"databases":["db"],"info":{"db":{"createTime":"2024-01-01 00:00:00","dbType":"BASE","count":3}}}`))
+		}
+	}, ClientOption{})
+
+	res, err := cli.DescribeDatabase(context.Background(), "ai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsAIDatabase() || res.Info.Count != 2 || res.Info.CreateTime != "2024-01-01 00:00:00" {
+		t.Errorf("unexpected database %+v", res.Info)
+	}
+	list, err := cli.ListDatabase(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Databases) != 1 || list.Databases[0].Info.Count != 3 || list.Databases[0].IsAIDatabase() {
+		t.Errorf("unexpected databases %+v", list.Databases)
+	}
+}
+
+func TestListDatabasePaging(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"databases":["db1","ai","db2","db3"],"info":{"ai":{"dbType":"AI_DOC"}}}`))
+	}, ClientOption{})
+
+	list, err := cli.ListDatabase(context.Background(), &ListDatabaseParams{Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 4 || len(list.AIDatabases) != 1 || len(list.Databases) != 1 || list.Databases[0].DatabaseName != "db2" {
+		t.Errorf("unexpected databases page %+v", list)
+	}
+	list, err = cli.ListDatabase(context.Background(), &ListDatabaseParams{Offset: 4})
+	if err != nil || len(list.Databases)+len(list.AIDatabases) != 0 || list.Total != 4 {
+		t.Errorf("expect empty page beyond the total, got %+v, %v", list, err)
+	}
+}
+
+func TestAIDatabaseAlias(t *testing.T) {
+	var paths []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/database/describe":
+			w.Write([]byte(`{"code":0,"database":{"database":"ai","dbType":"AI_DOC"}}`))
+		case "/ai/alias/set", "/ai/alias/delete":
+			w.Write([]byte(`{"code":0,"affectedCount":1}`))
+		}
+	}, ClientOption{})
+
+	res, err := cli.DescribeDatabase(context.Background(), "ai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AIDatabase == nil || !res.AIDatabase.IsAIDatabase() || res.AIDatabase.DatabaseName != "ai" {
+		t.Fatalf("expect an ai database described, got %+v", res.AIDatabase)
+	}
+	if _, err = res.Database.SetAlias(context.Background(), "view", "alias"); err != AIDbTypeError {
+		t.Errorf("expect base alias rejected on ai database, got %v", err)
+	}
+	if _, err = res.Database.DeleteAlias(context.Background(), "alias"); err != AIDbTypeError {
+		t.Errorf("expect base alias rejected on ai database, got %v", err)
+	}
+
+	setRes, err := res.AIDatabase.SetAlias(context.Background(), "view", "alias")
+	if err != nil || setRes.AffectedCount != 1 {
+		t.Errorf("expect ai alias set, got %+v, %v", setRes, err)
+	}
+	deleteRes, err := res.AIDatabase.DeleteAlias(context.Background(), "alias")
+	if err != nil || deleteRes.AffectedCount != 1 {
+		t.Errorf("expect ai alias deleted, got %+v, %v", deleteRes, err)
+	}
+	if strings.Join(paths, ",") != "/database/describe,/ai/alias/set,/ai/alias/delete" {
+		t.Errorf("unexpected requests %v", paths)
+	}
+}
@@ -20,11 +20,21 @@ package tcvectordb
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvdbtext/encoder"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/collection"
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/vectorutil"
 )
 
 var _ DocumentInterface = &implementerDocument{}
@@ -35,7 +45,10 @@ type DocumentInterface interface {
 	SdkClient
 	Upsert(ctx context.Context, documents interface{}, params ...*UpsertDocumentParams) (result *UpsertDocumentResult, err error)
 	Query(ctx context.Context, documentIds []string, params ...*QueryDocumentParams) (result *QueryDocumentResult, err error)
+	QueryIterator(ctx context.Context, filter *Filter, batchSize int64, params ...*QueryDocumentParams) *QueryIterator
+	Count(ctx context.Context, filter *Filter, params ...*CountDocumentParams) (result *CountDocumentResult, err error)
 	Search(ctx context.Context, vectors [][]float32, params ...*SearchDocumentParams) (result *SearchDocumentResult, err error)
+	SearchBinary(ctx context.Context, vectors [][]byte, params ...*SearchDocumentParams) (result *SearchDocumentResult, err error)
 	HybridSearch(ctx context.Context, params HybridSearchDocumentParams) (result *SearchDocumentResult, err error)
 	SearchById(ctx context.Context, documentIds []string, params ...*SearchDocumentParams) (result *SearchDocumentResult, err error)
 	SearchByText(ctx context.Context, text map[string][]string, params ...*SearchDocumentParams) (result *SearchDocumentResult, err error)
@@ -46,12 +59,18 @@ type DocumentInterface interface {
 type FlatInterface interface {
 	Upsert(ctx context.Context, databaseName, collectionName string, documents interface{}, params ...*UpsertDocumentParams) (result *UpsertDocumentResult, err error)
 	Query(ctx context.Context, databaseName, collectionName string, documentIds []string, params ...*QueryDocumentParams) (result *QueryDocumentResult, err error)
+	Count(ctx context.Context, databaseName, collectionName string, filter *Filter, params ...*CountDocumentParams) (result *CountDocumentResult, err error)
 	Search(ctx context.Context, databaseName, collectionName string, vectors [][]float32, params ...*SearchDocumentParams) (result *SearchDocumentResult, err error)
+	SearchBinary(ctx context.Context, databaseName, collectionName string, vectors [][]byte, params ...*SearchDocumentParams) (result *SearchDocumentResult, err error)
 	HybridSearch(ctx context.Context, databaseName, collectionName string, params HybridSearchDocumentParams) (result *SearchDocumentResult, err error)
 	SearchById(ctx context.Context, databaseName, collectionName string, documentIds []string, params ...*SearchDocumentParams) (result *SearchDocumentResult, err error)
 	SearchByText(ctx context.Context, databaseName, collectionName string, text map[string][]string, params ...*SearchDocumentParams) (result *SearchDocumentResult, err error)
 	Delete(ctx context.Context, databaseName, collectionName string, param DeleteDocumentParams) (result *DeleteDocumentResult, err error)
 	Update(ctx context.Context, databaseName, collectionName string, param UpdateDocumentParams) (result *UpdateDocumentResult, err error)
+	TruncateCollection(ctx context.Context, databaseName, collectionName string) (result *TruncateCollectionResult, err error)
+	DescribeCollection(ctx context.Context, databaseName, collectionName string) (result *DescribeCollectionResult, err error)
+	SetAlias(ctx context.Context, databaseName, collectionName, aliasName string) (result *SetAliasResult, err error)
+	DeleteAlias(ctx context.Context, databaseName, aliasName string) (result *DeleteAliasResult, err error)
 }
 
 type implementerDocument struct {
@@ -63,15 +82,118 @@ type implementerDocument struct {
 
 type UpsertDocumentParams struct {
 	BuildIndex *bool
+	// BatchSize splits the documents into batches of at most BatchSize documents,
+	// each batch is sent in its own request. 0 means sending all documents in one request.
+	BatchSize int
+	// BatchConcurrency is the number of batches sent at the same time, default 1.
+	BatchConcurrency int
+	// SkipDimensionCheck skips validating the vector dimension with the collection before sending.
+	SkipDimensionCheck bool
+	// SanitizeVectors replaces the NaN and Inf components of vectors with 0, instead of rejecting the
+	// documents. The documents passed in are not modified.
+	SanitizeVectors bool
+	// SkipTtlCheck skips validating the documents have the uint64 TimeField when the TTL of collection is enabled.
+	SkipTtlCheck bool
+	// AutoId allows the documents without id, whose ids are generated by server.
+	// It is enabled by default for the Collection described with AutoId on its primary key.
+	AutoId bool
+	// DiagnoseOnFailure: if the server rejects the documents, upsert them again by halves to find the rejected ones,
+	// which are reported in FailedDocuments instead of the error. It costs about log2(n) requests for each
+	// rejected document, and every accepted document is upserted once. The auth, rate limit and server errors are
+	// returned without diagnosing, so is the error repeated by both halves of the documents.
+	DiagnoseOnFailure bool
+	// FieldMask: the fields, including vector and sparse_vector, written over the existing documents, whose
+	// other fields are kept, so the services owning different fields of a document don't clobber each other.
+	// The existing documents are updated one by one with Update if the server supports FeatureUpdate, one request
+	// for each document and BatchConcurrency of them at the same time, skipping the documents without any field
+	// of FieldMask. Or else they are queried with strong consistency and upserted with the masked fields merged.
+	// Both are done in batches of BatchSize (default 100). The documents not existing are upserted as they are. It is a read-modify-write
+	// which is NOT atomic: a write between the query and the upsert of the same document is lost.
+	FieldMask []string
 }
 
 type UpsertDocumentResult struct {
 	AffectedCount int
+	// EmbeddingExtraInfo: the tokens used by the embedding of collection, zero if the embedding is not enabled
+	EmbeddingExtraInfo EmbeddingExtraInfo
+	// Ids: the ids generated by server for the documents without id, in order of the documents
+	Ids []string
+	// Warning: the warning of server, such as the documents are not indexed because BuildIndex is false
+	Warning string
+	// FailedDocuments: the documents rejected by the server, only set with DiagnoseOnFailure
+	FailedDocuments []FailedDocument
+}
+
+// FailedDocument the document rejected by the server
+type FailedDocument struct {
+	// Index: the position in the documents of Upsert
+	Index  int
+	Id     string
+	Reason string
+}
+
+// EmbeddingExtraInfo the usage of the embedding model reported by the server
+type EmbeddingExtraInfo struct {
+	TokenUsed uint64
+}
+
+// UpsertBatchError is returned by Upsert when the documents are sent in batches and a batch fails.
+// Offset is the index of the first document of the failed batch, documents before it could be
+// resumed from there. AffectedCount of the returned result counts the succeeded batches.
+type UpsertBatchError struct {
+	Batch  int
+	Offset int
+	Err    error
+}
+
+func (e *UpsertBatchError) Error() string {
+	return fmt.Sprintf("upsert batch %d (offset %d) failed: %v", e.Batch, e.Offset, e.Err)
+}
+
+func (e *UpsertBatchError) Unwrap() error {
+	return e.Err
 }
 
 // Upsert upsert documents into collection. Support for repeated insertion
 func (i *implementerDocument) Upsert(ctx context.Context, documents interface{}, params ...*UpsertDocumentParams) (result *UpsertDocumentResult, err error) {
-	return i.flat.Upsert(ctx, i.database.DatabaseName, i.collection.CollectionName, documents, params...)
+	if len(params) != 0 && params[0] != nil && len(params[0].FieldMask) != 0 {
+		documents, err := checkMaskedDocuments(ctx, i.collection, documents, params[0])
+		if err != nil {
+			return nil, err
+		}
+		return upsertWithFieldMask(ctx, i.SdkClient, i.flat, i.database.DatabaseName, i.collection.CollectionName, documents, params[0],
+			func(ctx context.Context, docs interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+				return i.Upsert(ctx, docs, param)
+			})
+	}
+	if len(params) == 0 || params[0] == nil || !params[0].SkipDimensionCheck {
+		err = checkDocumentsDimension(i.collection.schema(ctx), documents)
+		if err != nil {
+			return nil, i.collection.schemaError(ctx, err)
+		}
+	}
+	if len(params) == 0 || params[0] == nil || !params[0].SkipTtlCheck {
+		err = checkTtlField(i.collection.schema(ctx), documents)
+		if err != nil {
+			return nil, i.collection.schemaError(ctx, err)
+		}
+	}
+	documents, err = coerceDocumentFields(i.collection.schema(ctx), documents)
+	if err != nil {
+		return nil, i.collection.schemaError(ctx, err)
+	}
+	var zeros []string
+	if autoNormalize(i.SdkClient.Options(), i.collection.schema(ctx)) {
+		// the NaN and Inf components are checked before normalizing, which spreads them to the whole vector
+		documents, err = checkDocumentVectors(documents, len(params) != 0 && params[0] != nil && params[0].SanitizeVectors)
+		if err != nil {
+			return nil, err
+		}
+		documents, zeros = normalizeDocuments(documents)
+	}
+	params = withCollectionAutoId(i.collection.schema(ctx), params)
+	result, err = i.flat.Upsert(ctx, i.database.DatabaseName, i.collection.CollectionName, documents, params...)
+	return result, zeroVectorError(err, zeros, nil)
 }
 
 type QueryDocumentParams struct {
@@ -80,18 +202,178 @@ type QueryDocumentParams struct {
 	OutputFields   []string
 	Offset         int64
 	Limit          int64
+	// Sort: sort the documents by the filter indexed fields, in order of the rules
+	Sort []SortRule
+	// ReadConsistency: default is the ReadConsistency of ClientOption
+	ReadConsistency ReadConsistency
+	// RawResponse: keep the response body in QueryDocumentResult.RawResponse and each document in Document.Raw,
+	// to read the fields not decoded by the sdk. It is sent by http for the rpc client.
+	RawResponse bool
+}
+
+type SortRule struct {
+	FieldName string
+	// Direction: default SortAsc
+	Direction SortDirection
 }
 
 type QueryDocumentResult struct {
 	Documents     []Document
 	AffectedCount int
 	Total         uint64
+	// RawResponse: the response body, only set with QueryDocumentParams.RawResponse
+	RawResponse json.RawMessage
+	// Timing: the latency breakdown of the request, nil if the client does not send it over http
+	Timing *RequestTiming
 }
 
 // Query query the document by document ids.
 // The parameters retrieveVector set true, will return the vector field, but will reduce the api speed.
 func (i *implementerDocument) Query(ctx context.Context, documentIds []string, params ...*QueryDocumentParams) (*QueryDocumentResult, error) {
-	return i.flat.Query(ctx, i.database.DatabaseName, i.collection.CollectionName, documentIds, params...)
+	if len(params) != 0 && params[0] != nil {
+		if err := checkSortFields(i.collection.schema(ctx), params[0].Sort); err != nil {
+			return nil, i.collection.schemaError(ctx, err)
+		}
+		if err := checkOutputFields(i.SdkClient, i.collection.schema(ctx), params[0].OutputFields); err != nil {
+			return nil, err
+		}
+	}
+	res, err := i.flat.Query(ctx, i.database.DatabaseName, i.collection.CollectionName, documentIds, params...)
+	if err != nil {
+		return nil, err
+	}
+	fillBinaryVector(i.collection.schema(ctx), res.Documents)
+	return res, nil
+}
+
+// QueryIterator iterate the documents matching the filter, batchSize documents per page.
+// The Filter, Offset and Limit of params are ignored, use SetOffset to start from an offset.
+func (i *implementerDocument) QueryIterator(ctx context.Context, filter *Filter, batchSize int64, params ...*QueryDocumentParams) *QueryIterator {
+	return newQueryIterator(ctx, filter, batchSize, params, func(ctx context.Context, param *QueryDocumentParams) (*QueryDocumentResult, error) {
+		return i.flat.Query(ctx, i.database.DatabaseName, i.collection.CollectionName, nil, param)
+	})
+}
+
+type GetDocumentParams struct {
+	RetrieveVector bool
+	OutputFields   []string
+	// ReadConsistency: default is the ReadConsistency of ClientOption
+	ReadConsistency ReadConsistency
+}
+
+func (p *GetDocumentParams) queryParams(limit int) *QueryDocumentParams {
+	params := &QueryDocumentParams{Limit: int64(limit)}
+	if p != nil {
+		params.RetrieveVector = p.RetrieveVector
+		params.OutputFields = p.OutputFields
+		params.ReadConsistency = p.ReadConsistency
+	}
+	return params
+}
+
+// Get get the document by id, returns ErrDocumentNotExist if it is not found.
+func (c *Collection) Get(ctx context.Context, id string, params ...*GetDocumentParams) (*Document, error) {
+	var param *GetDocumentParams
+	if len(params) != 0 {
+		param = params[0]
+	}
+	res, err := c.Query(ctx, []string{id}, param.queryParams(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Documents) == 0 {
+		return nil, fmt.Errorf("get document %s failed: %w", id, ErrDocumentNotExist)
+	}
+	return &res.Documents[0], nil
+}
+
+// GetMulti get the documents by ids, returns the found documents by id and the missing ids.
+func (c *Collection) GetMulti(ctx context.Context, ids []string, params ...*GetDocumentParams) (map[string]Document, []string, error) {
+	if len(ids) == 0 {
+		return map[string]Document{}, nil, nil
+	}
+	var param *GetDocumentParams
+	if len(params) != 0 {
+		param = params[0]
+	}
+	res, err := c.Query(ctx, ids, param.queryParams(len(ids)))
+	if err != nil {
+		return nil, nil, err
+	}
+	found := make(map[string]Document, len(res.Documents))
+	for _, doc := range res.Documents {
+		found[doc.Id] = doc
+	}
+	var missing []string
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return found, missing, nil
+}
+
+// ExistsDocumentParams the optional params of Collection.Exists.
+type ExistsDocumentParams struct {
+	// ReadConsistency: default is the ReadConsistency of ClientOption,
+	// use StrongConsistency to check the documents just upserted
+	ReadConsistency ReadConsistency
+	// BatchSize: the max ids of each query, default 1000
+	BatchSize int
+}
+
+const defaultExistsBatchSize = 1000
+
+// Exists reports whether each of the ids exists in the collection. Only the ids of documents are queried,
+// in batches of ExistsDocumentParams.BatchSize, and the duplicated ids are queried once.
+func (c *Collection) Exists(ctx context.Context, ids []string, params ...*ExistsDocumentParams) (map[string]bool, error) {
+	param := new(ExistsDocumentParams)
+	if len(params) != 0 && params[0] != nil {
+		param = params[0]
+	}
+	batchSize := param.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExistsBatchSize
+	}
+	exists := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := exists[id]; !ok {
+			exists[id] = false
+			unique = append(unique, id)
+		}
+	}
+	for start := 0; start < len(unique); start += batchSize {
+		end := start + batchSize
+		if end > len(unique) {
+			end = len(unique)
+		}
+		res, err := c.Query(ctx, unique[start:end], &QueryDocumentParams{OutputFields: []string{"id"},
+			Limit: int64(end - start), ReadConsistency: param.ReadConsistency})
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range res.Documents {
+			if _, ok := exists[doc.Id]; ok {
+				exists[doc.Id] = true
+			}
+		}
+	}
+	return exists, nil
+}
+
+type CountDocumentParams struct {
+	// ReadConsistency: default is the ReadConsistency of ClientOption
+	ReadConsistency ReadConsistency
+}
+
+type CountDocumentResult struct {
+	Count uint64
+}
+
+// Count count the documents matching the filter. A nil filter counts all documents in the collection.
+func (i *implementerDocument) Count(ctx context.Context, filter *Filter, params ...*CountDocumentParams) (*CountDocumentResult, error) {
+	return i.flat.Count(ctx, i.database.DatabaseName, i.collection.CollectionName, filter, params...)
 }
 
 type SearchDocumentParams struct {
@@ -100,6 +382,34 @@ type SearchDocumentParams struct {
 	RetrieveVector bool
 	OutputFields   []string
 	Limit          int64
+	// SkipDimensionCheck skips validating the vector dimension with the collection before sending.
+	SkipDimensionCheck bool
+	// SanitizeVectors replaces the NaN and Inf components of vectors with 0, instead of rejecting them.
+	SanitizeVectors bool
+	// ReadConsistency: default is the ReadConsistency of ClientOption
+	ReadConsistency ReadConsistency
+	// PartialFailure: if the search of multiple vectors failed, search each vector in its own request,
+	// and return the results of the successful vectors with the errors of the failed ones.
+	// The vector dimension is checked by the server instead of the Collection.
+	PartialFailure bool
+	// FailOnMissing: SearchById returns error wrapping ErrDocumentNotExist with the missing ids,
+	// instead of the results with ErrDocumentNotExist in Errors of them.
+	FailOnMissing bool
+	// RawResponse: same as QueryDocumentParams.RawResponse. The search split by PartialFailure
+	// or SearchById keeps the Document.Raw only.
+	RawResponse bool
+	// GroupByField: return at most GroupSize documents of each value of the field, which is added to OutputFields.
+	// The server has no grouping, so it is approximate: GroupOverFetch times of Limit documents are searched,
+	// and fewer than Limit documents are returned if they are in fewer groups. The default Limit is 10.
+	GroupByField string
+	// GroupSize: the max documents of each group, default 1
+	GroupSize int
+	// GroupOverFetch: the multiplier of Limit to search for grouping, default 3
+	GroupOverFetch int
+	// Radius: the threshold of score, 0 means no threshold. It is sent as Params.Radius if that is not set.
+	// The Collection described by DescribeCollection also removes the documents beyond it, in case the server
+	// ignores it: the score must be at least Radius for IP and COSINE, and at most Radius for L2 and HAMMING.
+	Radius float32
 }
 
 type SearchDocParams struct {
@@ -111,22 +421,161 @@ type SearchDocParams struct {
 type SearchDocumentResult struct {
 	Warning   string
 	Documents [][]Document
+	// Errors: the errors of each vector, only set by the search with PartialFailure and the SearchById
+	// with missing ids, nil for the successful vectors and the Documents of the failed vectors are nil.
+	Errors []error
+	// MissingIds: the ids of SearchById not found in the collection, whose Errors are ErrDocumentNotExist
+	MissingIds []string
+	// EmbeddingExtraInfo: the tokens used by embedding the texts, zero if the search is not by text
+	EmbeddingExtraInfo EmbeddingExtraInfo
+	// RawResponse: the response body, only set with SearchDocumentParams.RawResponse
+	RawResponse json.RawMessage
+	// Groups: the Documents of each vector grouped by SearchDocumentParams.GroupByField, in order of their best documents
+	Groups [][]DocumentGroup
+	// RadiusFilteredByClient: the server returned the documents beyond SearchDocumentParams.Radius, which are removed
+	// by the client, so fewer documents than Limit may be returned even if more documents are within the Radius.
+	RadiusFilteredByClient bool
+	// Timing: the latency breakdown of the request, nil if the client does not send it over http
+	// or the result is merged from several requests
+	Timing *RequestTiming
+}
+
+// DocumentGroup the documents with the same value of the GroupByField, the documents without the field are in the group of empty Key.
+type DocumentGroup struct {
+	Key       string
+	Documents []Document
 }
 
 // Search search document topK by vector. The optional parameters filter will add the filter condition to search.
 // The optional parameters hnswParam only be set with the HNSW vector index type.
 func (i *implementerDocument) Search(ctx context.Context, vectors [][]float32, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
-	return i.flat.Search(ctx, i.database.DatabaseName, i.collection.CollectionName, vectors, params...)
+	if err := checkOutputFields(i.SdkClient, i.collection.schema(ctx), searchOutputFields(params)); err != nil {
+		return nil, err
+	}
+	if len(params) == 0 || params[0] == nil || !(params[0].SkipDimensionCheck || params[0].PartialFailure) {
+		err := checkSearchDimension(i.collection.schema(ctx), vectors)
+		if err != nil {
+			return nil, i.collection.schemaError(ctx, err)
+		}
+	}
+	var zeros []int
+	if len(vectors) != 0 && autoNormalize(i.SdkClient.Options(), i.collection.schema(ctx)) {
+		// the NaN and Inf components are checked before normalizing, the same as Upsert
+		checked, err := checkSearchVectors(vectors, params)
+		if err != nil {
+			return nil, err
+		}
+		vectors, zeros = vectorutil.NormalizedL2Batch(checked)
+	}
+	res, err := i.flat.Search(ctx, i.database.DatabaseName, i.collection.CollectionName, vectors, params...)
+	res, err = filterByRadius(i.collection.schema(ctx), false, params, res, err)
+	return res, zeroVectorError(err, nil, zeros)
+}
+
+// SearchBinary search document topK by binary vectors of the BinaryVector index.
+func (i *implementerDocument) SearchBinary(ctx context.Context, vectors [][]byte, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	if err := checkOutputFields(i.SdkClient, i.collection.schema(ctx), searchOutputFields(params)); err != nil {
+		return nil, err
+	}
+	if len(params) == 0 || params[0] == nil || !params[0].SkipDimensionCheck {
+		err := checkSearchBinaryDimension(i.collection.schema(ctx), vectors)
+		if err != nil {
+			return nil, i.collection.schemaError(ctx, err)
+		}
+	}
+	res, err := i.flat.SearchBinary(ctx, i.database.DatabaseName, i.collection.CollectionName, vectors, params...)
+	if err != nil {
+		return nil, err
+	}
+	for _, docs := range res.Documents {
+		fillBinaryVector(i.collection.schema(ctx), docs)
+	}
+	return filterByRadius(i.collection.schema(ctx), true, params, res, nil)
 }
 
 // Search search document topK by document ids. The optional parameters filter will add the filter condition to search.
 // The optional parameters hnswParam only be set with the HNSW vector index type.
 func (i *implementerDocument) SearchById(ctx context.Context, documentIds []string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
-	return i.flat.SearchById(ctx, i.database.DatabaseName, i.collection.CollectionName, documentIds, params...)
+	if err := checkOutputFields(i.SdkClient, i.collection.schema(ctx), searchOutputFields(params)); err != nil {
+		return nil, err
+	}
+	res, err := i.flat.SearchById(ctx, i.database.DatabaseName, i.collection.CollectionName, documentIds, params...)
+	return filterByRadius(i.collection.schema(ctx), false, params, res, err)
 }
 
 func (i *implementerDocument) SearchByText(ctx context.Context, text map[string][]string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
-	return i.flat.SearchByText(ctx, i.database.DatabaseName, i.collection.CollectionName, text, params...)
+	if err := checkOutputFields(i.SdkClient, i.collection.schema(ctx), searchOutputFields(params)); err != nil {
+		return nil, err
+	}
+	if err := checkEmbeddingEnabled(i.collection.schema(ctx)); err != nil {
+		return nil, i.collection.schemaError(ctx, err)
+	}
+	res, err := i.flat.SearchByText(ctx, i.database.DatabaseName, i.collection.CollectionName, text, params...)
+	return filterByRadius(i.collection.schema(ctx), false, params, res, err)
+}
+
+// filterByRadius removes the documents beyond the Radius of params from res, by the metric type of the vector index
+// of coll, or the binary vector index if binary is set. It is skipped if the indexes of collection are unknown.
+func filterByRadius(coll *Collection, binary bool, params []*SearchDocumentParams, res *SearchDocumentResult, err error) (*SearchDocumentResult, error) {
+	if err != nil || res == nil || len(params) == 0 || params[0] == nil || params[0].Radius == 0 {
+		return res, err
+	}
+	var metric MetricType
+	if binary && len(coll.Indexes.BinaryVectorIndex) != 0 {
+		metric = coll.Indexes.BinaryVectorIndex[0].MetricType
+	} else if !binary && len(coll.Indexes.VectorIndex) != 0 {
+		metric = coll.Indexes.VectorIndex[0].MetricType
+	}
+	radius := params[0].Radius
+	var within func(score float32) bool
+	switch metric {
+	case IP, COSINE:
+		within = func(score float32) bool { return score >= radius }
+	case L2, HAMMING:
+		within = func(score float32) bool { return score <= radius }
+	default:
+		return res, nil
+	}
+	for n, docs := range res.Documents {
+		kept := docs[:0:0]
+		for _, doc := range docs {
+			if within(doc.Score) {
+				kept = append(kept, doc)
+			}
+		}
+		if len(kept) != len(docs) {
+			res.Documents[n] = kept
+			res.RadiusFilteredByClient = true
+		}
+	}
+	if !res.RadiusFilteredByClient {
+		return res, nil
+	}
+	for n, groups := range res.Groups {
+		var kept []DocumentGroup
+		for _, group := range groups {
+			docs := group.Documents[:0:0]
+			for _, doc := range group.Documents {
+				if within(doc.Score) {
+					docs = append(docs, doc)
+				}
+			}
+			if len(docs) != 0 {
+				kept = append(kept, DocumentGroup{Key: group.Key, Documents: docs})
+			}
+		}
+		res.Groups[n] = kept
+	}
+	return res, nil
+}
+
+// checkEmbeddingEnabled rejects searching by text on the described collection without embedding,
+// the collection got by Collection without describing it is not checked.
+func checkEmbeddingEnabled(coll *Collection) error {
+	if len(coll.Indexes.VectorIndex) == 0 || coll.Embedding.Enabled || coll.Embedding.Field != "" {
+		return nil
+	}
+	return fmt.Errorf("search by text failed, because the embedding of collection %s is not enabled", coll.CollectionName)
 }
 
 type HybridSearchDocumentParams struct {
@@ -135,6 +584,10 @@ type HybridSearchDocumentParams struct {
 	RetrieveVector bool
 	OutputFields   []string
 	Limit          *int
+	// ReadConsistency: default is the ReadConsistency of ClientOption
+	ReadConsistency ReadConsistency
+	// RawResponse: same as QueryDocumentParams.RawResponse
+	RawResponse bool
 
 	AnnParams []*AnnParam
 	Rerank    *RerankOption
@@ -154,25 +607,46 @@ type MatchOption struct {
 
 type AnnParam struct {
 	FieldName string
-	Data      interface{}
-	Params    *SearchDocParams
-	Limit     *int
+	// Data: []float32 vector, or string text when the collection is created with embedding
+	Data   interface{}
+	Params *SearchDocParams
+	Limit  *int
 }
 
 func (i *implementerDocument) HybridSearch(ctx context.Context, params HybridSearchDocumentParams) (*SearchDocumentResult, error) {
+	if err := checkOutputFields(i.SdkClient, i.collection.schema(ctx), params.OutputFields); err != nil {
+		return nil, err
+	}
 	return i.flat.HybridSearch(ctx, i.database.DatabaseName, i.collection.CollectionName, params)
 }
 
 type DeleteDocumentParams struct {
 	DocumentIds []string
-	Filter      *Filter
+	// Filter: delete the documents matching the filter, within DocumentIds if it is set
+	Filter *Filter
+	// Limit: the max number of documents deleted by the request, default 0 means no limit
+	Limit int64
+	// DeleteAll: must be set to delete all documents of the collection without DocumentIds and Filter
+	DeleteAll bool
+	// BatchSize splits the DocumentIds into batches of at most BatchSize ids in order, each batch is sent in its
+	// own request. Default 0 means 1000, negative means sending all ids in one request. With Limit, the batches
+	// are sent one by one until Limit documents are deleted.
+	BatchSize int
+	// BatchConcurrency is the number of batches sent at the same time, default 1.
+	BatchConcurrency int
+	// ContinueOnError sends the remaining batches after a batch fails, the failed batches are returned in
+	// IdBatchErrors. By default the batches stop at the first failure, which is returned in IdBatchError.
+	ContinueOnError bool
 }
 
 type DeleteDocumentResult struct {
 	AffectedCount int
+	// Warning: the warning of server, such as some ids are skipped
+	Warning string
 }
 
-// Delete delete document by document ids
+// Delete delete the documents by document ids, filter or both.
+// It returns error without sending the request if neither is set, unless DeleteAll is set.
 func (i *implementerDocument) Delete(ctx context.Context, param DeleteDocumentParams) (result *DeleteDocumentResult, err error) {
 	return i.flat.Delete(ctx, i.database.DatabaseName, i.collection.CollectionName, param)
 }
@@ -183,12 +657,24 @@ type UpdateDocumentParams struct {
 	UpdateVector    []float32
 	UpdateSparseVec []encoder.SparseVecItem
 	UpdateFields    interface{}
+	// BatchSize splits the QueryIds into batches of at most BatchSize ids in order, each batch is sent in its
+	// own request. Default 0 means 1000, negative means sending all ids in one request.
+	BatchSize int
+	// BatchConcurrency is the number of batches sent at the same time, default 1.
+	BatchConcurrency int
+	// ContinueOnError sends the remaining batches after a batch fails, the failed batches are returned in
+	// IdBatchErrors. By default the batches stop at the first failure, which is returned in IdBatchError.
+	ContinueOnError bool
 }
 
 type UpdateDocumentResult struct {
 	AffectedCount int
+	// Warning: the warning of server
+	Warning string
 }
 
+// Update update the vector, sparse vector or fields of the documents matching QueryIds or QueryFilter,
+// the other fields of the documents are kept, so the vector is not required to update the fields.
 func (i *implementerDocument) Update(ctx context.Context, param UpdateDocumentParams) (*UpdateDocumentResult, error) {
 	return i.flat.Update(ctx, i.database.DatabaseName, i.collection.CollectionName, param)
 }
@@ -197,9 +683,41 @@ type Document struct {
 	Id           string                  `json:"id"`
 	Vector       []float32               `json:"vector"`
 	SparseVector []encoder.SparseVecItem `json:"sparse_vector"`
+	// BinaryVector the vector of BinaryVector index, each byte holds 8 dimensions.
+	// It is returned by Collection's Query and SearchBinary if the collection has a BinaryVector index.
+	BinaryVector []byte `json:"binary_vector,omitempty"`
 	// omitempty when upsert
 	Score  float32 `json:"score"`
 	Fields map[string]Field
+	// Raw: the document in the response, only set by Query and Search with RawResponse
+	Raw json.RawMessage `json:"-"`
+}
+
+// ExpiresAt returns the expiration time of document by the TimeField of the collection TtlConfig,
+// ok is false if the TTL is not enabled or the document has no TimeField.
+func (d Document) ExpiresAt(ttl *TtlConfig) (expiresAt time.Time, ok bool) {
+	if ttl == nil || !ttl.Enable || ttl.TimeField == "" {
+		return time.Time{}, false
+	}
+	field, ok := d.Fields[ttl.TimeField]
+	if !ok || !field.Exists() {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(field.Uint64()), 0), true
+}
+
+// NormalizedScore maps the Score of search result into a similarity in [0, 1], the higher the more similar.
+// The L2 and HAMMING distances are mapped by 1/(1+distance), the COSINE similarity in [-1, 1] by (score+1)/2,
+// and the IP is treated as the COSINE of normalized vectors. The Score is returned for the unknown metric.
+func (d Document) NormalizedScore(metric MetricType) float64 {
+	score := float64(d.Score)
+	switch metric {
+	case L2, HAMMING:
+		return 1 / (1 + math.Max(score, 0))
+	case COSINE, IP:
+		return math.Min(math.Max((score+1)/2, 0), 1)
+	}
+	return score
 }
 
 type implementerFlatDocument struct {
@@ -207,6 +725,28 @@ type implementerFlatDocument struct {
 }
 
 func (i *implementerFlatDocument) Upsert(ctx context.Context, db, coll string, documents interface{}, params ...*UpsertDocumentParams) (result *UpsertDocumentResult, err error) {
+	documents, err = checkDocumentVectors(documents, len(params) != 0 && params[0] != nil && params[0].SanitizeVectors)
+	if err != nil {
+		return nil, err
+	}
+	if len(params) != 0 && params[0] != nil && params[0].BatchSize > 0 {
+		return upsertInBatches(ctx, documents, params[0], i.Options().MaxRequestBytes, func(ctx context.Context, docs interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+			return i.Upsert(ctx, db, coll, docs, param)
+		})
+	}
+	if len(params) != 0 && params[0] != nil && params[0].DiagnoseOnFailure {
+		return upsertDiagnosed(ctx, documents, params[0], func(ctx context.Context, docs interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+			return i.Upsert(ctx, db, coll, docs, param)
+		})
+	}
+
+	if err := checkArrayFields(documents); err != nil {
+		return nil, err
+	}
+	if err := checkDocumentIds(documents, len(params) != 0 && params[0] != nil && params[0].AutoId); err != nil {
+		return nil, err
+	}
+
 	req := new(document.UpsertReq)
 	req.Database = db
 	req.Collection = coll
@@ -216,9 +756,16 @@ func (i *implementerFlatDocument) Upsert(ctx context.Context, db, coll string, d
 			d := &document.Document{}
 			d.Id = doc.Id
 			d.Vector = doc.Vector
+			if len(doc.BinaryVector) != 0 {
+				d.Vector = binaryToFloat32(doc.BinaryVector)
+			}
 
+			sparseVector, err := checkSparseVector(doc.SparseVector)
+			if err != nil {
+				return nil, fmt.Errorf("upsert failed. doc's sparse_vector data is incorrect. doc id is %v. err: %v", d.Id, err.Error())
+			}
 			d.SparseVector = make([][]interface{}, 0)
-			for _, sv := range doc.SparseVector {
+			for _, sv := range sparseVector {
 				d.SparseVector = append(d.SparseVector, []interface{}{sv.TermId, sv.Score})
 			}
 
@@ -234,30 +781,38 @@ func (i *implementerFlatDocument) Upsert(ctx context.Context, db, coll string, d
 			if id, ok := doc["id"]; ok {
 				if sId, ok := id.(string); ok {
 					d.Id = sId
-					delete(doc, "id")
 				} else {
 					return nil, fmt.Errorf("upsert failed, because of incorrect id field type, which must be string")
 				}
 			}
 			if vector, ok := doc["vector"]; ok {
-				if aVector, ok := vector.([]float32); ok {
+				switch aVector := vector.(type) {
+				case []float32:
 					d.Vector = aVector
-					delete(doc, "vector")
-				} else {
-					return nil, fmt.Errorf("upsert failed, because of incorrect vector field type, which must be []float32")
+				case []byte:
+					d.Vector = binaryToFloat32(aVector)
+				default:
+					return nil, fmt.Errorf("upsert failed, because of incorrect vector field type, which must be []float32 or []byte")
 				}
 			}
 			if sparseVector, ok := doc["sparse_vector"]; ok {
 				if aSparseVector, ok := sparseVector.([][]interface{}); ok {
 					d.SparseVector = make([][]interface{}, 0)
+					svItems := make([]encoder.SparseVecItem, 0, len(aSparseVector))
 					for _, sv := range aSparseVector {
 						svItem, err := ConvSliceInterface2SparseVecItem(sv)
 						if err != nil {
 							return nil, fmt.Errorf("upsert failed. doc's sparse_vector data is incorrect. doc id is %v. err: %v", d.Id, err.Error())
 						}
+						svItems = append(svItems, *svItem)
+					}
+					svItems, err := checkSparseVector(svItems)
+					if err != nil {
+						return nil, fmt.Errorf("upsert failed. doc's sparse_vector data is incorrect. doc id is %v. err: %v", d.Id, err.Error())
+					}
+					for _, svItem := range svItems {
 						d.SparseVector = append(d.SparseVector, []interface{}{svItem.TermId, svItem.Score})
 					}
-					delete(doc, "sparse_vector")
 				} else {
 					return nil, fmt.Errorf("upsert failed, because of incorrect sparse_vector field type, which must be [][]interface{}")
 				}
@@ -265,6 +820,9 @@ func (i *implementerFlatDocument) Upsert(ctx context.Context, db, coll string, d
 
 			d.Fields = make(map[string]interface{})
 			for k, v := range doc {
+				if isUpsertReservedKey(k) {
+					continue
+				}
 				d.Fields[k] = v
 			}
 			req.Documents = append(req.Documents, d)
@@ -287,9 +845,56 @@ func (i *implementerFlatDocument) Upsert(ctx context.Context, db, coll string, d
 		return
 	}
 	result.AffectedCount = int(res.AffectedCount)
+	if res.EmbeddingExtraInfo != nil {
+		result.EmbeddingExtraInfo.TokenUsed = res.EmbeddingExtraInfo.TokenUsed
+	}
+	result.Ids = res.Ids
+	result.Warning = res.Warning
 	return
 }
 
+// withCollectionAutoId enables the AutoId of params if the primary key of collection has AutoId.
+func withCollectionAutoId(coll *Collection, params []*UpsertDocumentParams) []*UpsertDocumentParams {
+	if !coll.Indexes.autoId() {
+		return params
+	}
+	param := new(UpsertDocumentParams)
+	if len(params) != 0 && params[0] != nil {
+		*param = *params[0]
+	}
+	param.AutoId = true
+	return []*UpsertDocumentParams{param}
+}
+
+// checkDocumentIds rejects the documents without id, unless the ids are generated by server.
+func checkDocumentIds(documents interface{}, autoId bool) error {
+	if autoId {
+		return nil
+	}
+	empty := -1
+	switch docs := documents.(type) {
+	case []Document:
+		for n, doc := range docs {
+			if doc.Id == "" {
+				empty = n
+				break
+			}
+		}
+	case []map[string]interface{}:
+		for n, doc := range docs {
+			if id, ok := doc["id"]; !ok || id == "" {
+				empty = n
+				break
+			}
+		}
+	}
+	if empty >= 0 {
+		return fmt.Errorf("upsert failed, because the id of document %d is empty, "+
+			"set UpsertDocumentParams.AutoId if the primary key of collection is created with AutoId", empty)
+	}
+	return nil
+}
+
 func (i *implementerFlatDocument) Query(ctx context.Context, databaseName, collectionName string, documentIds []string, params ...*QueryDocumentParams) (*QueryDocumentResult, error) {
 	req := new(document.QueryReq)
 	req.Database = databaseName
@@ -302,18 +907,36 @@ func (i *implementerFlatDocument) Query(ctx context.Context, databaseName, colle
 		param := params[0]
 		req.Query.Filter = param.Filter.Cond()
 		req.Query.RetrieveVector = param.RetrieveVector
-		req.Query.OutputFields = param.OutputFields
+		req.Query.OutputFields = uniqueFields(param.OutputFields)
 		req.Query.Offset = param.Offset
 		req.Query.Limit = param.Limit
+		if param.ReadConsistency != "" {
+			req.ReadConsistency = string(param.ReadConsistency)
+		}
+		for _, rule := range param.Sort {
+			if rule.Direction != "" && rule.Direction != SortAsc && rule.Direction != SortDesc {
+				return nil, fmt.Errorf("invalid sort direction %s of field %s, which must be asc or desc", rule.Direction, rule.FieldName)
+			}
+			req.Query.Sort = append(req.Query.Sort, &document.SortRule{
+				FieldName: rule.FieldName,
+				Direction: string(rule.Direction),
+			})
+		}
 	}
 
 	res := new(document.QueryRes)
-	err := i.Request(ctx, req, res)
+	var raw *rawResponse
+	if len(params) != 0 && params[0] != nil && params[0].RawResponse {
+		raw = &rawResponse{res: res}
+	}
+	ctx, timing := withRequestTiming(ctx)
+	err := i.Request(ctx, req, responseOf(res, raw))
 	if err != nil {
 		return nil, err
 	}
 
 	result := new(QueryDocumentResult)
+	result.Timing = collectedTiming(timing)
 	var documents []Document
 	for _, doc := range res.Documents {
 		var d Document
@@ -339,17 +962,149 @@ func (i *implementerFlatDocument) Query(ctx context.Context, databaseName, colle
 	result.Documents = documents
 	result.AffectedCount = len(documents)
 	result.Total = res.Count
+	if raw != nil {
+		result.RawResponse = raw.body
+		var docs struct {
+			Documents []json.RawMessage `json:"documents"`
+		}
+		if json.Unmarshal(raw.body, &docs) == nil && len(docs.Documents) == len(result.Documents) {
+			for n := range result.Documents {
+				result.Documents[n].Raw = docs.Documents[n]
+			}
+		}
+	}
 	return result, nil
 }
 
+func (i *implementerFlatDocument) Count(ctx context.Context, databaseName, collectionName string,
+	filter *Filter, params ...*CountDocumentParams) (*CountDocumentResult, error) {
+	req := new(document.CountReq)
+	req.Database = databaseName
+	req.Collection = collectionName
+	req.Query = &document.CountQueryCond{
+		Filter: filter.Cond(),
+	}
+	req.ReadConsistency = string(i.SdkClient.Options().ReadConsistency)
+	if len(params) != 0 && params[0] != nil && params[0].ReadConsistency != "" {
+		req.ReadConsistency = string(params[0].ReadConsistency)
+	}
+
+	res := new(document.CountRes)
+	err := i.Request(ctx, req, res)
+	if err != nil {
+		return nil, err
+	}
+	return &CountDocumentResult{Count: res.Count}, nil
+}
+
+// Search search document topK by vectors. The vectors could be nil if the Filter of params is set,
+// which returns at most Limit documents matching the filter in one group, without scores.
 func (i *implementerFlatDocument) Search(ctx context.Context, databaseName, collectionName string,
 	vectors [][]float32, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
-	return i.search(ctx, databaseName, collectionName, nil, vectors, nil, params...)
+	vectors, err := checkSearchVectors(vectors, params)
+	if err != nil {
+		return nil, err
+	}
+	return searchWithPartialFailure(ctx, vectors, params, func(ctx context.Context, vectors [][]float32) (*SearchDocumentResult, error) {
+		return i.search(ctx, databaseName, collectionName, nil, vectors, nil, params...)
+	})
 }
 
+// SearchBinary search document topK by binary vectors, which are sent as the arrays of uint8.
+func (i *implementerFlatDocument) SearchBinary(ctx context.Context, databaseName, collectionName string,
+	vectors [][]byte, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	return i.Search(ctx, databaseName, collectionName, binariesToFloat32(vectors), params...)
+}
+
+// SearchById search by the vectors of documents, the Documents of result are aligned with documentIds.
+// The missing ids get nil Documents and ErrDocumentNotExist in Errors, unless FailOnMissing is set.
 func (i *implementerFlatDocument) SearchById(ctx context.Context, databaseName, collectionName string,
 	documentIds []string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
-	return i.search(ctx, databaseName, collectionName, documentIds, nil, nil, params...)
+	return searchByIdAligned(ctx, i, databaseName, collectionName, documentIds, params,
+		func(ctx context.Context, documentIds []string) (*SearchDocumentResult, error) {
+			return i.search(ctx, databaseName, collectionName, documentIds, nil, nil, params...)
+		})
+}
+
+// searchByIdAligned aligns the result of search with documentIds. If the server fails for the missing ids,
+// or returns less results than documentIds, the missing ids are found by querying them,
+// and the existing ones are searched again if needed.
+func searchByIdAligned(ctx context.Context, flat FlatInterface, databaseName, collectionName string, documentIds []string,
+	params []*SearchDocumentParams, search func(ctx context.Context, documentIds []string) (*SearchDocumentResult, error)) (*SearchDocumentResult, error) {
+	result, err := search(ctx, documentIds)
+	if err == nil && len(result.Documents) == len(documentIds) {
+		return result, nil
+	}
+	if err != nil && (!isNotExist(err) || IsCollectionNotExist(err) || IsDatabaseNotExist(err)) {
+		return nil, err
+	}
+	queryParam := &QueryDocumentParams{OutputFields: []string{"id"}, Limit: int64(len(documentIds))}
+	if len(params) != 0 && params[0] != nil {
+		queryParam.ReadConsistency = params[0].ReadConsistency
+	}
+	queried, queryErr := flat.Query(ctx, databaseName, collectionName, documentIds, queryParam)
+	if queryErr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, queryErr
+	}
+	existing := make(map[string]bool)
+	for _, doc := range queried.Documents {
+		existing[doc.Id] = true
+	}
+	var found, missing []string
+	for _, id := range documentIds {
+		if existing[id] {
+			found = append(found, id)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		// the results could not be aligned by the missing ids
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if len(params) != 0 && params[0] != nil && params[0].FailOnMissing {
+		return nil, fmt.Errorf("%w: %s", ErrDocumentNotExist, strings.Join(missing, ", "))
+	}
+	if err != nil || len(result.Documents) != len(found) {
+		result = new(SearchDocumentResult)
+		if len(found) != 0 {
+			if result, err = search(ctx, found); err != nil {
+				return nil, err
+			}
+			if len(result.Documents) != len(found) {
+				return nil, fmt.Errorf("search by %d ids returns %d results", len(found), len(result.Documents))
+			}
+		}
+	}
+	documents := make([][]Document, len(documentIds))
+	errs := make([]error, len(documentIds))
+	var groups [][]DocumentGroup
+	if len(result.Groups) == len(result.Documents) {
+		groups = make([][]DocumentGroup, len(documentIds))
+	}
+	n := 0
+	for i, id := range documentIds {
+		if !existing[id] {
+			errs[i] = fmt.Errorf("document %s: %w", id, ErrDocumentNotExist)
+			continue
+		}
+		documents[i] = result.Documents[n]
+		if groups != nil {
+			groups[i] = result.Groups[n]
+		}
+		n++
+	}
+	result.Documents, result.Errors, result.MissingIds = documents, errs, missing
+	if result.Groups != nil {
+		result.Groups = groups
+	}
+	return result, nil
 }
 
 func (i *implementerFlatDocument) SearchByText(ctx context.Context, databaseName, collectionName string,
@@ -359,6 +1114,11 @@ func (i *implementerFlatDocument) SearchByText(ctx context.Context, databaseName
 
 func (i *implementerFlatDocument) search(ctx context.Context, databaseName, collectionName string,
 	documentIds []string, vectors [][]float32, text map[string][]string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	if len(params) != 0 && params[0] != nil && params[0].GroupByField != "" {
+		return searchGrouped(params, func(params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+			return i.search(ctx, databaseName, collectionName, documentIds, vectors, text, params...)
+		})
+	}
 	req := new(document.SearchReq)
 	req.Database = databaseName
 	req.Collection = collectionName
@@ -370,12 +1130,17 @@ func (i *implementerFlatDocument) search(ctx context.Context, databaseName, coll
 		req.Search.EmbeddingItems = v
 	}
 
+	rawWanted := false
 	if len(params) != 0 && params[0] != nil {
 		param := params[0]
+		rawWanted = param.RawResponse
 		req.Search.Filter = param.Filter.Cond()
-		req.Search.RetrieveVector = param.RetrieveVector
-		req.Search.OutputFields = param.OutputFields
+		req.Search.RetrieveVector = retrieveVector(param.RetrieveVector, param.OutputFields)
+		req.Search.OutputFields = uniqueFields(param.OutputFields)
 		req.Search.Limit = param.Limit
+		if param.ReadConsistency != "" {
+			req.ReadConsistency = string(param.ReadConsistency)
+		}
 
 		if param.Params != nil {
 			req.Search.Params = new(document.SearchParams)
@@ -383,10 +1148,21 @@ func (i *implementerFlatDocument) search(ctx context.Context, databaseName, coll
 			req.Search.Params.Ef = param.Params.Ef
 			req.Search.Params.Radius = param.Params.Radius
 		}
+		if param.Radius != 0 && (param.Params == nil || param.Params.Radius == 0) {
+			if req.Search.Params == nil {
+				req.Search.Params = new(document.SearchParams)
+			}
+			req.Search.Params.Radius = param.Radius
+		}
 	}
 
 	res := new(document.SearchRes)
-	err := i.Request(ctx, req, res)
+	var raw *rawResponse
+	if rawWanted {
+		raw = &rawResponse{res: res}
+	}
+	ctx, timing := withRequestTiming(ctx)
+	err := i.Request(ctx, req, responseOf(res, raw))
 	if err != nil {
 		return nil, err
 	}
@@ -400,6 +1176,16 @@ func (i *implementerFlatDocument) search(ctx context.Context, databaseName, coll
 				Score:  doc.Score,
 				Fields: make(map[string]Field),
 			}
+
+			d.SparseVector = make([]encoder.SparseVecItem, 0)
+			for _, sv := range doc.SparseVector {
+				svItem, err := ConvSliceInterface2SparseVecItem(sv)
+				if err != nil {
+					return nil, fmt.Errorf("the search response's doc sparse_vector data is incorrect. doc id is %v. err: %v", d.Id, err.Error())
+				}
+				d.SparseVector = append(d.SparseVector, *svItem)
+			}
+
 			for n, v := range doc.Fields {
 				d.Fields[n] = Field{Val: v}
 			}
@@ -410,6 +1196,14 @@ func (i *implementerFlatDocument) search(ctx context.Context, databaseName, coll
 	result := new(SearchDocumentResult)
 	result.Warning = res.Warning
 	result.Documents = documents
+	result.Timing = collectedTiming(timing)
+	if res.EmbeddingExtraInfo != nil {
+		result.EmbeddingExtraInfo.TokenUsed = res.EmbeddingExtraInfo.TokenUsed
+	}
+	if raw != nil {
+		result.RawResponse = raw.body
+		setRawSearchDocuments(raw.body, result.Documents)
+	}
 	return result, nil
 }
 
@@ -419,6 +1213,9 @@ func (i *implementerFlatDocument) HybridSearch(ctx context.Context, databaseName
 	req.Database = databaseName
 	req.Collection = collectionName
 	req.ReadConsistency = string(i.SdkClient.Options().ReadConsistency)
+	if params.ReadConsistency != "" {
+		req.ReadConsistency = string(params.ReadConsistency)
+	}
 	req.Search = new(document.HybridSearchCond)
 	req.Search.AnnParams = make([]*document.AnnParam, 0)
 	req.Search.Match = make([]*document.MatchOption, 0)
@@ -434,11 +1231,14 @@ func (i *implementerFlatDocument) HybridSearch(ctx context.Context, databaseName
 		})
 
 		req.Search.AnnParams[i].Data = make([]interface{}, 0)
-		if vec, ok := annParam.Data.([]float32); ok {
-			req.Search.AnnParams[i].Data = append(req.Search.AnnParams[i].Data, vec)
-		} else {
+		switch data := annParam.Data.(type) {
+		case []float32:
+			req.Search.AnnParams[i].Data = append(req.Search.AnnParams[i].Data, data)
+		case string:
+			req.Search.AnnParams[i].Data = append(req.Search.AnnParams[i].Data, data)
+		default:
 			return nil, fmt.Errorf("hybridSearch failed, because of AnnParam.Data field type, " +
-				"which must be []float32")
+				"which must be []float32 or string")
 		}
 
 		if annParam.Params != nil {
@@ -486,12 +1286,18 @@ func (i *implementerFlatDocument) HybridSearch(ctx context.Context, databaseName
 	}
 
 	req.Search.Filter = params.Filter.Cond()
-	req.Search.RetrieveVector = params.RetrieveVector
-	req.Search.OutputFields = params.OutputFields
+	req.Search.RetrieveVector = retrieveVector(params.RetrieveVector, params.OutputFields)
+	req.Search.OutputFields = uniqueFields(params.OutputFields)
 	req.Search.Limit = params.Limit
+	rawWanted := params.RawResponse
 
 	res := new(document.SearchRes)
-	err := i.Request(ctx, req, res)
+	var raw *rawResponse
+	if rawWanted {
+		raw = &rawResponse{res: res}
+	}
+	ctx, timing := withRequestTiming(ctx)
+	err := i.Request(ctx, req, responseOf(res, raw))
 	if err != nil {
 		return nil, err
 	}
@@ -525,27 +1331,37 @@ func (i *implementerFlatDocument) HybridSearch(ctx context.Context, databaseName
 	result := new(SearchDocumentResult)
 	result.Warning = res.Warning
 	result.Documents = documents
+	result.Timing = collectedTiming(timing)
+	if res.EmbeddingExtraInfo != nil {
+		result.EmbeddingExtraInfo.TokenUsed = res.EmbeddingExtraInfo.TokenUsed
+	}
+	if raw != nil {
+		result.RawResponse = raw.body
+		setRawSearchDocuments(raw.body, result.Documents)
+	}
 	return result, nil
 }
 
 func (i *implementerFlatDocument) Delete(ctx context.Context, databaseName, collectionName string,
 	param DeleteDocumentParams) (*DeleteDocumentResult, error) {
-	req := new(document.DeleteReq)
-	req.Database = databaseName
-	req.Collection = collectionName
-	req.Query = &document.QueryCond{
-		DocumentIds: param.DocumentIds,
-		Filter:      param.Filter.Cond(),
-	}
-
-	res := new(document.DeleteRes)
-	result := new(DeleteDocumentResult)
-	err := i.Request(ctx, req, res)
-	if err != nil {
+	if err := checkDeleteParams(param); err != nil {
 		return nil, err
 	}
-	result.AffectedCount = res.AffectedCount
-	return result, nil
+	return deleteInBatches(ctx, param, func(ctx context.Context, ids []string, limit int64) (int, string, error) {
+		req := new(document.DeleteReq)
+		req.Database = databaseName
+		req.Collection = collectionName
+		req.Query = &document.QueryCond{
+			DocumentIds: ids,
+			Filter:      param.Filter.Cond(),
+			Limit:       limit,
+		}
+		res := new(document.DeleteRes)
+		if err := i.Request(ctx, req, res); err != nil {
+			return 0, "", err
+		}
+		return res.AffectedCount, res.Warning, nil
+	})
 }
 
 func (i *implementerFlatDocument) Update(ctx context.Context, databaseName, collectionName string,
@@ -558,8 +1374,12 @@ func (i *implementerFlatDocument) Update(ctx context.Context, databaseName, coll
 	req.Query.DocumentIds = param.QueryIds
 	req.Query.Filter = param.QueryFilter.Cond()
 	req.Update.Vector = param.UpdateVector
+	sparseVector, err := checkSparseVector(param.UpdateSparseVec)
+	if err != nil {
+		return nil, fmt.Errorf("update failed. %v", err.Error())
+	}
 	req.Update.SparseVector = make([][]interface{}, 0)
-	for _, sv := range param.UpdateSparseVec {
+	for _, sv := range sparseVector {
 		req.Update.SparseVector = append(req.Update.SparseVector, []interface{}{sv.TermId, sv.Score})
 	}
 	req.Update.Fields = make(map[string]interface{}, 0)
@@ -593,17 +1413,652 @@ func (i *implementerFlatDocument) Update(ctx context.Context, databaseName, coll
 		return nil, fmt.Errorf("update failed, because of incorrect UpdateDocumentParams.UpdateFields field type, " +
 			"which must be map[string]Field or map[string]interface{}")
 	}
+	if len(req.Update.Vector) == 0 && len(req.Update.SparseVector) == 0 && len(req.Update.Fields) == 0 {
+		return nil, errEmptyUpdate
+	}
 
-	res := new(document.UpdateRes)
 	result := new(UpdateDocumentResult)
-	err := i.Request(ctx, req, res)
+	result.AffectedCount, result.Warning, err = sendIdBatches(ctx, param.QueryIds, updateIdBatchOption(param),
+		func(ctx context.Context, ids []string) (int, string, error) {
+			batchReq := *req
+			batchReq.Query = &document.QueryCond{DocumentIds: ids, Filter: req.Query.Filter}
+			res := new(document.UpdateRes)
+			if err := i.Request(ctx, &batchReq, res); err != nil {
+				return 0, "", err
+			}
+			return res.AffectedCount, res.Warning, nil
+		})
 	if err != nil {
 		return result, err
 	}
-	result.AffectedCount = res.AffectedCount
 	return result, nil
 }
 
+// TruncateCollection clear all documents of the collection, the indexes and alias of the collection are kept.
+func (i *implementerFlatDocument) TruncateCollection(ctx context.Context, databaseName, collectionName string) (*TruncateCollectionResult, error) {
+	req := new(collection.TruncateReq)
+	req.Database = databaseName
+	req.Collection = collectionName
+
+	res := new(collection.TruncateRes)
+	err := i.Request(ctx, req, res)
+	if err != nil {
+		return nil, err
+	}
+	return &TruncateCollectionResult{AffectedCount: res.AffectedCount}, nil
+}
+
+// DescribeCollection get the information of the collection in the database.
+func (i *implementerFlatDocument) DescribeCollection(ctx context.Context, databaseName, collectionName string) (*DescribeCollectionResult, error) {
+	collImpl := &implementerCollection{SdkClient: i.SdkClient, database: flatDatabase(databaseName)}
+	return collImpl.DescribeCollection(ctx, collectionName)
+}
+
+// SetAlias set the alias of the collection in the database.
+func (i *implementerFlatDocument) SetAlias(ctx context.Context, databaseName, collectionName, aliasName string) (*SetAliasResult, error) {
+	aliasImpl := &implementerAlias{SdkClient: i.SdkClient, database: flatDatabase(databaseName)}
+	return aliasImpl.SetAlias(ctx, collectionName, aliasName)
+}
+
+// DeleteAlias delete the alias in the database.
+func (i *implementerFlatDocument) DeleteAlias(ctx context.Context, databaseName, aliasName string) (*DeleteAliasResult, error) {
+	aliasImpl := &implementerAlias{SdkClient: i.SdkClient, database: flatDatabase(databaseName)}
+	return aliasImpl.DeleteAlias(ctx, aliasName)
+}
+
+// flatDatabase returns the database entity for the flat apis, which share the implementers of database.
+func flatDatabase(name string) *Database {
+	return &Database{DatabaseName: name, Info: DatabaseItem{DbType: DbTypeBase}}
+}
+
+var errEmptyUpdate = errors.New("update failed, because of nothing to update, " +
+	"which must set UpdateVector, UpdateSparseVec or UpdateFields")
+
+var errEmptyDelete = errors.New("delete failed, because of no DocumentIds or Filter, " +
+	"which would delete all documents, set DeleteAll to do it")
+
+// checkDeleteParams returns error if the delete has no condition without DeleteAll, or has a negative limit.
+func checkDeleteParams(param DeleteDocumentParams) error {
+	if len(param.DocumentIds) == 0 && param.Filter.Cond() == "" && !param.DeleteAll {
+		return errEmptyDelete
+	}
+	if param.Limit < 0 {
+		return fmt.Errorf("delete failed, because of negative limit %d", param.Limit)
+	}
+	return nil
+}
+
+// checkSparseVector returns error if the term ids of sparse vector are duplicated, and the sparse vector
+// sorted by term id, which is a sorted copy if the sparse vector passed in is not sorted.
+func checkSparseVector(sparseVector []encoder.SparseVecItem) ([]encoder.SparseVecItem, error) {
+	sorted := sparseVector
+	for i := 1; i < len(sparseVector); i++ {
+		if sparseVector[i].TermId <= sparseVector[i-1].TermId {
+			// the server requires the term ids in ascending order, such as the unsorted output of encoder
+			sorted = append([]encoder.SparseVecItem(nil), sparseVector...)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i].TermId < sorted[j].TermId })
+			break
+		}
+	}
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].TermId == sorted[i-1].TermId {
+			return nil, fmt.Errorf("incorrect sparse_vector data, which has duplicated term id %v", sorted[i].TermId)
+		}
+	}
+	return sorted, nil
+}
+
+// checkArrayFields returns error if any array field of the documents has elements of different types,
+// which the server rejects.
+func checkArrayFields(documents interface{}) error {
+	check := func(id interface{}, name string, val interface{}) error {
+		v := reflect.ValueOf(val)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil
+		}
+		var kind reflect.Kind
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+			if i == 0 {
+				kind = elem.Kind()
+			} else if elem.Kind() != kind {
+				return fmt.Errorf("upsert failed, because the array field %s of document %v has elements of mixed types %v and %v",
+					name, id, kind, elem.Kind())
+			}
+		}
+		return nil
+	}
+	switch docs := documents.(type) {
+	case []Document:
+		for _, doc := range docs {
+			for name, field := range doc.Fields {
+				if err := check(doc.Id, name, field.Val); err != nil {
+					return err
+				}
+			}
+		}
+	case []map[string]interface{}:
+		for _, doc := range docs {
+			for name, val := range doc {
+				if name == "vector" || name == "sparse_vector" {
+					continue
+				}
+				if err := check(doc["id"], name, val); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkSortFields returns error if the sort field is not a filter index of the collection,
+// which the server can not sort by. It is skipped if the indexes of collection are unknown.
+func checkSortFields(coll *Collection, sort []SortRule) error {
+	if len(sort) == 0 || len(coll.Indexes.FilterIndex) == 0 {
+		return nil
+	}
+	for _, rule := range sort {
+		found := false
+		for _, index := range coll.Indexes.FilterIndex {
+			if index.FieldName == rule.FieldName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("can not sort by field %s, which is not a filter index of collection %s, add the index by AddIndex first",
+				rule.FieldName, coll.CollectionName)
+		}
+	}
+	return nil
+}
+
+// checkOutputFields returns UnknownOutputFieldError with the StrictOutputFields of cli if one of fields
+// is not known by the schema of coll. It is skipped if the indexes of collection are unknown.
+func checkOutputFields(cli SdkClient, coll *Collection, fields []string) error {
+	indexes := coll.Indexes
+	if len(fields) == 0 || !cli.Options().StrictOutputFields || len(indexes.VectorIndex) == 0 && len(indexes.FilterIndex) == 0 &&
+		len(indexes.BinaryVectorIndex) == 0 && len(indexes.SparseVectorIndex) == 0 {
+		return nil
+	}
+	known := map[string]bool{"id": true, "vector": true, "sparse_vector": true}
+	for _, index := range indexes.FilterIndex {
+		known[index.FieldName] = true
+	}
+	for _, index := range indexes.VectorIndex {
+		known[index.FieldName] = true
+	}
+	for _, index := range indexes.SparseVectorIndex {
+		known[index.FieldName] = true
+	}
+	for _, index := range indexes.BinaryVectorIndex {
+		known[index.FieldName] = true
+	}
+	if coll.Embedding.Field != "" {
+		known[coll.Embedding.Field] = true
+	}
+	var (
+		unknown    []string
+		recognized []string
+	)
+	for _, field := range fields {
+		if known[field] {
+			recognized = append(recognized, field)
+		} else {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	var suggestions []string
+	for name := range known {
+		if editDistance(unknown[0], name) <= 2 {
+			suggestions = append(suggestions, name)
+		}
+	}
+	sort.Strings(suggestions)
+	return &UnknownOutputFieldError{Collection: coll.CollectionName, Field: unknown[0], Suggestions: suggestions, Recognized: recognized}
+}
+
+// searchOutputFields returns the OutputFields of the optional params of search.
+func searchOutputFields(params []*SearchDocumentParams) []string {
+	if len(params) == 0 || params[0] == nil {
+		return nil
+	}
+	return params[0].OutputFields
+}
+
+// editDistance returns the levenshtein distance of a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cur[j] = prev[j-1]
+			if a[i-1] != b[j-1] {
+				cur[j]++
+			}
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// uniqueFields returns the fields without the duplicated ones, in order of their first appearance.
+func uniqueFields(fields []string) []string {
+	seen := make(map[string]bool, len(fields))
+	unique := fields[:0:0]
+	for _, field := range fields {
+		if !seen[field] {
+			seen[field] = true
+			unique = append(unique, field)
+		}
+	}
+	if len(unique) == len(fields) {
+		return fields
+	}
+	return unique
+}
+
+// rawResponse keeps the response body while decoding it into res.
+type rawResponse struct {
+	res  interface{}
+	body json.RawMessage
+}
+
+func (r *rawResponse) UnmarshalJSON(data []byte) error {
+	r.body = append(json.RawMessage(nil), data...)
+	return json.Unmarshal(data, r.res)
+}
+
+// responseOf returns raw to decode the response if it is not nil, so the body is kept only if it is requested.
+func responseOf(res interface{}, raw *rawResponse) interface{} {
+	if raw != nil {
+		return raw
+	}
+	return res
+}
+
+// setRawSearchDocuments sets the Raw of documents by the documents of search response body.
+func setRawSearchDocuments(body []byte, documents [][]Document) {
+	var res struct {
+		Documents [][]json.RawMessage `json:"documents"`
+	}
+	if json.Unmarshal(body, &res) != nil || len(res.Documents) != len(documents) {
+		return
+	}
+	for n, docs := range res.Documents {
+		if len(docs) != len(documents[n]) {
+			continue
+		}
+		for m := range docs {
+			documents[n][m].Raw = docs[m]
+		}
+	}
+}
+
+// coerceDocumentFields converts the values of the filter indexed fields to their FieldType of the collection,
+// such as int or float64 decoded from json to uint64 and int to float64, and returns error with the document id and field name
+// if a value can not be converted. The documents are copied only if any field is converted.
+// It is skipped if the indexes of collection are unknown.
+func coerceDocumentFields(coll *Collection, documents interface{}) (interface{}, error) {
+	types := make(map[string]FieldType)
+	for _, index := range coll.Indexes.FilterIndex {
+		if index.IsPrimaryKey() {
+			continue
+		}
+		switch index.FieldType {
+		case Uint64, Int64, Float64, String:
+			types[index.FieldName] = index.FieldType
+		}
+	}
+	if len(types) == 0 {
+		return documents, nil
+	}
+	coerce := func(id interface{}, name string, val interface{}) (interface{}, error) {
+		if val == nil {
+			return nil, fmt.Errorf("upsert failed, because the field %s of document %v is nil, which is a filter index", name, id)
+		}
+		var err error
+		switch types[name] {
+		case Uint64:
+			val, err = coerceUint64(val)
+		case Int64:
+			val, err = coerceInt64(val)
+		case Float64:
+			val, err = coerceFloat64(val)
+		case String:
+			if _, ok := val.(string); !ok {
+				err = fmt.Errorf("%T is not a string", val)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("upsert failed, because the field %s of document %v is not %s: %v", name, id, types[name], err)
+		}
+		return val, nil
+	}
+	switch docs := documents.(type) {
+	case []Document:
+		var coerced []Document
+		for n, doc := range docs {
+			var fields map[string]Field
+			for name, field := range doc.Fields {
+				if _, ok := types[name]; !ok {
+					continue
+				}
+				val, err := coerce(doc.Id, name, field.Val)
+				if err != nil {
+					return nil, err
+				}
+				if val == field.Val {
+					continue
+				}
+				if fields == nil {
+					fields = make(map[string]Field, len(doc.Fields))
+					for k, v := range doc.Fields {
+						fields[k] = v
+					}
+				}
+				fields[name] = Field{Val: val}
+			}
+			if fields == nil {
+				continue
+			}
+			if coerced == nil {
+				coerced = append([]Document(nil), docs...)
+			}
+			coerced[n].Fields = fields
+		}
+		if coerced != nil {
+			return coerced, nil
+		}
+	case []map[string]interface{}:
+		var coerced []map[string]interface{}
+		for n, doc := range docs {
+			var fields map[string]interface{}
+			for name, field := range doc {
+				if _, ok := types[name]; !ok {
+					continue
+				}
+				val, err := coerce(doc["id"], name, field)
+				if err != nil {
+					return nil, err
+				}
+				if val == field {
+					continue
+				}
+				if fields == nil {
+					fields = make(map[string]interface{}, len(doc))
+					for k, v := range doc {
+						fields[k] = v
+					}
+				}
+				fields[name] = val
+			}
+			if fields == nil {
+				continue
+			}
+			if coerced == nil {
+				coerced = append([]map[string]interface{}(nil), docs...)
+			}
+			coerced[n] = fields
+		}
+		if coerced != nil {
+			return coerced, nil
+		}
+	}
+	return documents, nil
+}
+
+// coerceUint64 converts the integer value to uint64, the negative, fractional and string values are rejected.
+func coerceUint64(val interface{}) (uint64, error) {
+	switch v := val.(type) {
+	case uint64:
+		return v, nil
+	case uint, uint8, uint16, uint32:
+		return reflect.ValueOf(v).Uint(), nil
+	case int, int8, int16, int32, int64:
+		n := reflect.ValueOf(v).Int()
+		if n < 0 {
+			return 0, fmt.Errorf("%d is negative", n)
+		}
+		return uint64(n), nil
+	case float32, float64:
+		f := reflect.ValueOf(v).Float()
+		if f < 0 || f >= math.MaxUint64 || f != math.Trunc(f) {
+			return 0, fmt.Errorf("%v is not an uint64", f)
+		}
+		return uint64(f), nil
+	case json.Number:
+		n, err := strconv.ParseUint(string(v), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s is not an uint64", v)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("%T is not a number", val)
+}
+
+// coerceInt64 converts the integer value to int64, the fractional, string and overflowed values are rejected.
+func coerceInt64(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int64:
+		return v, nil
+	case int, int8, int16, int32:
+		return reflect.ValueOf(v).Int(), nil
+	case uint, uint8, uint16, uint32, uint64:
+		n := reflect.ValueOf(v).Uint()
+		if n > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows int64", n)
+		}
+		return int64(n), nil
+	case float32, float64:
+		f := reflect.ValueOf(v).Float()
+		if f < math.MinInt64 || f >= math.MaxInt64 || f != math.Trunc(f) {
+			return 0, fmt.Errorf("%v is not an int64", f)
+		}
+		return int64(f), nil
+	case json.Number:
+		n, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s is not an int64", v)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("%T is not a number", val)
+}
+
+// coerceFloat64 converts the number to float64, the float64 and json.Number are kept
+// so they are encoded without losing precision.
+func coerceFloat64(val interface{}) (interface{}, error) {
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		// use the shortest decimal of float32, instead of its binary value in float64
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+		return f, nil
+	case int, int8, int16, int32, int64:
+		return float64(reflect.ValueOf(v).Int()), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return float64(reflect.ValueOf(v).Uint()), nil
+	case json.Number:
+		if _, err := v.Float64(); err != nil {
+			return nil, fmt.Errorf("%s is not a number", v)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("%T is not a number", val)
+}
+
+// checkTtlField returns error if any document has no TimeField of uint64 when the TTL of collection is enabled.
+func checkTtlField(coll *Collection, documents interface{}) error {
+	if coll.TtlConfig == nil || !coll.TtlConfig.Enable || coll.TtlConfig.TimeField == "" {
+		return nil
+	}
+	field := coll.TtlConfig.TimeField
+	check := func(id, val interface{}, ok bool) error {
+		if !ok || val == nil {
+			return fmt.Errorf("upsert failed, because document %v has no field %s, which is the TTL time field", id, field)
+		}
+		if _, err := coerceUint64(val); err != nil {
+			return fmt.Errorf("upsert failed, because the TTL time field %s of document %v is not an uint64 unix time: %v", field, id, err)
+		}
+		return nil
+	}
+	switch docs := documents.(type) {
+	case []Document:
+		for _, doc := range docs {
+			val, ok := doc.Fields[field]
+			if err := check(doc.Id, val.Val, ok); err != nil {
+				return err
+			}
+		}
+	case []map[string]interface{}:
+		for _, doc := range docs {
+			val, ok := doc[field]
+			if err := check(doc["id"], val, ok); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkDocumentsDimension returns error if the vector length of any document is not the collection dimension.
+// The documents without vector are allowed if the embedding of collection is enabled.
+// The binary vector must have dimension/8 bytes of the BinaryVector index.
+func checkDocumentsDimension(coll *Collection, documents interface{}) error {
+	dimension := coll.vectorDimension()
+	binaryDimension := coll.binaryVectorDimension()
+	if dimension == 0 && binaryDimension == 0 {
+		return nil
+	}
+	check := func(id interface{}, length int, binary bool) error {
+		if binary {
+			if binaryDimension != 0 && length*8 != int(binaryDimension) {
+				return fmt.Errorf("document %v: binary vector length %d does not match the collection dimension %d/8", id, length, binaryDimension)
+			}
+			return nil
+		}
+		if dimension == 0 || length == 0 && coll.Embedding.Enabled {
+			return nil
+		}
+		if length != int(dimension) {
+			return fmt.Errorf("document %v: vector dimension %d does not match the collection dimension %d", id, length, dimension)
+		}
+		return nil
+	}
+	switch docs := documents.(type) {
+	case []Document:
+		for _, doc := range docs {
+			var err error
+			if len(doc.BinaryVector) != 0 {
+				err = check(doc.Id, len(doc.BinaryVector), true)
+			} else {
+				err = check(doc.Id, len(doc.Vector), false)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case []map[string]interface{}:
+		for _, doc := range docs {
+			length := 0
+			_, binary := doc["vector"].([]byte)
+			if vector, ok := doc["vector"]; ok {
+				v := reflect.ValueOf(vector)
+				if v.Kind() != reflect.Slice {
+					continue
+				}
+				length = v.Len()
+			}
+			if err := check(doc["id"], length, binary); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkSearchBinaryDimension returns error if the bits of any query binary vector is not the binary index dimension.
+func checkSearchBinaryDimension(coll *Collection, vectors [][]byte) error {
+	dimension := coll.binaryVectorDimension()
+	if dimension == 0 {
+		return nil
+	}
+	for i, vector := range vectors {
+		if len(vector)*8 != int(dimension) {
+			return fmt.Errorf("vectors[%d]: binary vector length %d does not match the collection dimension %d/8", i, len(vector), dimension)
+		}
+	}
+	return nil
+}
+
+// binaryToFloat32 convert the binary vector to the array of uint8, which the server expects.
+func binaryToFloat32(vector []byte) []float32 {
+	res := make([]float32, len(vector))
+	for i, b := range vector {
+		res[i] = float32(b)
+	}
+	return res
+}
+
+func binariesToFloat32(vectors [][]byte) [][]float32 {
+	res := make([][]float32, 0, len(vectors))
+	for _, vector := range vectors {
+		res = append(res, binaryToFloat32(vector))
+	}
+	return res
+}
+
+// fillBinaryVector move the vector returned as the array of uint8 to BinaryVector,
+// if the collection has a BinaryVector index.
+func fillBinaryVector(coll *Collection, docs []Document) {
+	if len(coll.Indexes.BinaryVectorIndex) == 0 {
+		return
+	}
+	for i := range docs {
+		if len(docs[i].Vector) == 0 {
+			continue
+		}
+		docs[i].BinaryVector = make([]byte, len(docs[i].Vector))
+		for j, v := range docs[i].Vector {
+			docs[i].BinaryVector[j] = byte(v)
+		}
+		docs[i].Vector = nil
+	}
+}
+
+// checkSearchDimension returns error if the length of any query vector is not the collection dimension.
+func checkSearchDimension(coll *Collection, vectors [][]float32) error {
+	dimension := coll.vectorDimension()
+	if dimension == 0 {
+		return nil
+	}
+	for i, vector := range vectors {
+		if len(vector) != int(dimension) {
+			return fmt.Errorf("vectors[%d]: vector dimension %d does not match the collection dimension %d", i, len(vector), dimension)
+		}
+	}
+	return nil
+}
+
 func ConvSliceInterface2SparseVecItem(sv []interface{}) (*encoder.SparseVecItem, error) {
 
 	svItem := new(encoder.SparseVecItem)
@@ -632,3 +2087,564 @@ func ConvSliceInterface2SparseVecItem(sv []interface{}) (*encoder.SparseVecItem,
 
 	return svItem, nil
 }
+
+const partialSearchConcurrency = 8
+
+const (
+	defaultGroupSearchLimit = 10
+	defaultGroupOverFetch   = 3
+)
+
+// searchGrouped searches with the over fetched limit by search without GroupByField,
+// and keeps at most GroupSize documents of each group.
+func searchGrouped(params []*SearchDocumentParams, search func(params ...*SearchDocumentParams) (*SearchDocumentResult, error)) (*SearchDocumentResult, error) {
+	param := *params[0]
+	field := param.GroupByField
+	limit := int(param.Limit)
+	if limit <= 0 {
+		limit = defaultGroupSearchLimit
+	}
+	size := param.GroupSize
+	if size <= 0 {
+		size = 1
+	}
+	overFetch := param.GroupOverFetch
+	if overFetch <= 0 {
+		overFetch = defaultGroupOverFetch
+	}
+	param.GroupByField = ""
+	param.Limit = int64(limit * overFetch)
+	if len(param.OutputFields) != 0 {
+		param.OutputFields = append(append([]string(nil), param.OutputFields...), field)
+	}
+	res, err := search(&param)
+	if err != nil {
+		return nil, err
+	}
+	res.Groups = make([][]DocumentGroup, len(res.Documents))
+	for n, docs := range res.Documents {
+		var (
+			kept   []Document
+			groups []DocumentGroup
+		)
+		index := make(map[string]int)
+		for _, doc := range docs {
+			if len(kept) == limit {
+				break
+			}
+			key := ""
+			if f, ok := doc.Fields[field]; ok && f.Exists() {
+				key = f.String()
+			}
+			g, ok := index[key]
+			if !ok {
+				g = len(groups)
+				index[key] = g
+				groups = append(groups, DocumentGroup{Key: key})
+			}
+			if len(groups[g].Documents) == size {
+				continue
+			}
+			groups[g].Documents = append(groups[g].Documents, doc)
+			kept = append(kept, doc)
+		}
+		res.Documents[n], res.Groups[n] = kept, groups
+	}
+	return res, nil
+}
+
+// checkSearchVectors returns error if the vectors are empty without Filter, or any of them is empty or has NaN or Inf
+// components, which are replaced with 0 in a copy of vectors with SanitizeVectors.
+func checkSearchVectors(vectors [][]float32, params []*SearchDocumentParams) ([][]float32, error) {
+	sanitize := len(params) != 0 && params[0] != nil && params[0].SanitizeVectors
+	if len(vectors) == 0 && (len(params) == 0 || params[0] == nil || params[0].Filter.Cond() == "") {
+		return nil, errors.New("search failed, because neither vectors nor SearchDocumentParams.Filter is set")
+	}
+	checked := vectors
+	for n, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("vectors[%d]: vector is empty", n)
+		}
+		v, err := checkVectorValues(vector, sanitize)
+		if err != nil {
+			return nil, fmt.Errorf("vectors[%d]: %v", n, err)
+		}
+		if &v[0] != &vector[0] {
+			if &checked[0] == &vectors[0] {
+				checked = append([][]float32(nil), vectors...)
+			}
+			checked[n] = v
+		}
+	}
+	return checked, nil
+}
+
+// checkVectorValues returns error on the first NaN or Inf component of vector, or a copy of vector with them
+// replaced with 0 if sanitize.
+func checkVectorValues(vector []float32, sanitize bool) ([]float32, error) {
+	checked := vector
+	for n, v := range vector {
+		if !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0) {
+			continue
+		}
+		if !sanitize {
+			return nil, fmt.Errorf("vector component %d is %v", n, v)
+		}
+		if &checked[0] == &vector[0] {
+			checked = append([]float32(nil), vector...)
+		}
+		checked[n] = 0
+	}
+	return checked, nil
+}
+
+// checkDocumentVectors returns error if the vector of any document is empty or has NaN or Inf components,
+// which are replaced with 0 in a copy of documents if sanitize. The documents without vector are allowed.
+func checkDocumentVectors(documents interface{}, sanitize bool) (interface{}, error) {
+	switch docs := documents.(type) {
+	case []Document:
+		checked := docs
+		for n, doc := range docs {
+			if doc.Vector == nil {
+				continue
+			}
+			if len(doc.Vector) == 0 {
+				return nil, fmt.Errorf("document %v: vector is empty", doc.Id)
+			}
+			v, err := checkVectorValues(doc.Vector, sanitize)
+			if err != nil {
+				return nil, fmt.Errorf("document %v: %v", doc.Id, err)
+			}
+			if &v[0] != &doc.Vector[0] {
+				if &checked[0] == &docs[0] {
+					checked = append([]Document(nil), docs...)
+				}
+				checked[n].Vector = v
+			}
+		}
+		return checked, nil
+	case []map[string]interface{}:
+		checked := docs
+		for n, doc := range docs {
+			vector, ok := doc["vector"].([]float32)
+			if !ok || vector == nil {
+				continue
+			}
+			if len(vector) == 0 {
+				return nil, fmt.Errorf("document %v: vector is empty", doc["id"])
+			}
+			v, err := checkVectorValues(vector, sanitize)
+			if err != nil {
+				return nil, fmt.Errorf("document %v: %v", doc["id"], err)
+			}
+			if &v[0] != &vector[0] {
+				if &checked[0] == &docs[0] {
+					checked = append([]map[string]interface{}(nil), docs...)
+				}
+				copied := make(map[string]interface{}, len(doc))
+				for k, value := range doc {
+					copied[k] = value
+				}
+				copied["vector"] = v
+				checked[n] = copied
+			}
+		}
+		return checked, nil
+	}
+	return documents, nil
+}
+
+// searchWithPartialFailure search each vector in its own request if the search of all vectors failed
+// with PartialFailure set, and collect the errors of the failed vectors.
+func searchWithPartialFailure(ctx context.Context, vectors [][]float32, params []*SearchDocumentParams,
+	search func(ctx context.Context, vectors [][]float32) (*SearchDocumentResult, error)) (*SearchDocumentResult, error) {
+	res, err := search(ctx, vectors)
+	if err == nil || len(vectors) < 2 || len(params) == 0 || params[0] == nil || !params[0].PartialFailure || ctx.Err() != nil {
+		return res, err
+	}
+
+	result := &SearchDocumentResult{
+		Documents: make([][]Document, len(vectors)),
+		Errors:    make([]error, len(vectors)),
+	}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		warnings []string
+		failed   int
+	)
+	sem := make(chan struct{}, partialSearchConcurrency)
+	for i := range vectors {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := search(ctx, vectors[i:i+1])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Errors[i] = err
+				failed++
+				return
+			}
+			if len(res.Documents) != 0 {
+				result.Documents[i] = res.Documents[0]
+			}
+			if len(res.Groups) != 0 {
+				if result.Groups == nil {
+					result.Groups = make([][]DocumentGroup, len(vectors))
+				}
+				result.Groups[i] = res.Groups[0]
+			}
+			if res.Warning != "" {
+				warnings = append(warnings, res.Warning)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if failed == len(vectors) {
+		return nil, err
+	}
+	result.Warning = strings.Join(warnings, "; ")
+	return result, nil
+}
+
+// retrieveVector reports whether the vector should be returned, "vector" in outputFields is the same as retrieveVector.
+func retrieveVector(retrieve bool, outputFields []string) bool {
+	for _, field := range outputFields {
+		if field == "vector" {
+			return true
+		}
+	}
+	return retrieve
+}
+
+// upsertInBatches splits documents by param.BatchSize and sends every batch with upsert,
+// at most param.BatchConcurrency batches at the same time.
+// The batch is split further if its json size exceeds maxBytes, no limit if maxBytes <= 0.
+func upsertInBatches(ctx context.Context, documents interface{}, param *UpsertDocumentParams, maxBytes int,
+	upsert func(ctx context.Context, documents interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error)) (*UpsertDocumentResult, error) {
+	docs := reflect.ValueOf(documents)
+	if docs.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("upsert failed, because of incorrect documents type, which must be []Document or []map[string]interface{}")
+	}
+	bounds := batchBounds(docs, param.BatchSize, maxBytes)
+	// every batch is upserted with the options of param, except the ones splitting the documents
+	batchParam := *param
+	batchParam.BatchSize = 0
+	batchParam.BatchConcurrency = 0
+	concurrency := param.BatchConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		affected  int
+		tokenUsed uint64
+		batchErr  *UpsertBatchError
+		batchIds  = make(map[int][]string)
+		warnings  []string
+		failed    []FailedDocument
+	)
+	fail := func(e *UpsertBatchError) {
+		if batchErr == nil || e.Offset < batchErr.Offset {
+			batchErr = e
+		}
+	}
+	sem := make(chan struct{}, concurrency)
+	for batch := 0; batch+1 < len(bounds); batch++ {
+		offset, end := bounds[batch], bounds[batch+1]
+		sem <- struct{}{}
+		mu.Lock()
+		if batchErr == nil && ctx.Err() != nil {
+			fail(&UpsertBatchError{Batch: batch, Offset: offset, Err: ctx.Err()})
+		}
+		stop := batchErr != nil
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(batch, offset, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := upsert(ctx, docs.Slice(offset, end).Interface(), &batchParam)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fail(&UpsertBatchError{Batch: batch, Offset: offset, Err: err})
+				return
+			}
+			affected += res.AffectedCount
+			tokenUsed += res.EmbeddingExtraInfo.TokenUsed
+			batchIds[batch] = res.Ids
+			if res.Warning != "" {
+				warnings = append(warnings, res.Warning)
+			}
+			for _, f := range res.FailedDocuments {
+				f.Index += offset
+				failed = append(failed, f)
+			}
+		}(batch, offset, end)
+	}
+	wg.Wait()
+
+	result := &UpsertDocumentResult{AffectedCount: affected, Warning: strings.Join(warnings, "; ")}
+	result.EmbeddingExtraInfo.TokenUsed = tokenUsed
+	for batch := 0; batch+1 < len(bounds); batch++ {
+		result.Ids = append(result.Ids, batchIds[batch]...)
+	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Index < failed[j].Index })
+	result.FailedDocuments = failed
+	if batchErr != nil {
+		return result, batchErr
+	}
+	return result, nil
+}
+
+// upsertDiagnosed upserts the documents, and bisects them to find the documents rejected by the server
+// if the upsert fails with a document-level APIError. The other errors are returned without bisecting,
+// so is the error of the first bisect if both halves fail with the same error of the whole documents.
+func upsertDiagnosed(ctx context.Context, documents interface{}, param *UpsertDocumentParams,
+	upsert func(ctx context.Context, documents interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error)) (*UpsertDocumentResult, error) {
+	docs := reflect.ValueOf(documents)
+	if docs.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("upsert failed, because of incorrect documents type, which must be []Document or []map[string]interface{}")
+	}
+	plain := *param
+	plain.DiagnoseOnFailure = false
+	result := new(UpsertDocumentResult)
+	var warnings []string
+	// send upserts the documents in [offset, end), it returns the document-level rejection, or the other error
+	send := func(offset, end int) (rejected error, err error) {
+		res, err := upsert(ctx, docs.Slice(offset, end).Interface(), &plain)
+		if err != nil {
+			if !documentLevelError(err) {
+				return nil, err
+			}
+			return err, nil
+		}
+		result.AffectedCount += res.AffectedCount
+		result.EmbeddingExtraInfo.TokenUsed += res.EmbeddingExtraInfo.TokenUsed
+		result.Ids = append(result.Ids, res.Ids...)
+		if res.Warning != "" {
+			warnings = append(warnings, res.Warning)
+		}
+		return nil, nil
+	}
+	var bisect func(offset, end int, rejected error, first bool) error
+	bisect = func(offset, end int, rejected error, first bool) error {
+		if end-offset == 1 {
+			result.FailedDocuments = append(result.FailedDocuments, FailedDocument{
+				Index: offset, Id: documentId(docs.Index(offset).Interface()), Reason: rejected.Error()})
+			return nil
+		}
+		mid := (offset + end) / 2
+		left, err := send(offset, mid)
+		if err != nil {
+			return err
+		}
+		right, err := send(mid, end)
+		if err != nil {
+			return err
+		}
+		if first && left != nil && right != nil && sameAPIError(left, rejected) && sameAPIError(right, rejected) {
+			return rejected
+		}
+		if left != nil {
+			if err = bisect(offset, mid, left, false); err != nil {
+				return err
+			}
+		}
+		if right != nil {
+			return bisect(mid, end, right, false)
+		}
+		return nil
+	}
+	rejected, err := send(0, docs.Len())
+	if err == nil && rejected != nil {
+		err = bisect(0, docs.Len(), rejected, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Warning = strings.Join(warnings, "; ")
+	return result, nil
+}
+
+// documentLevelError reports whether err is an APIError which could be caused by some of the documents,
+// rather than the auth, rate limit, server errors or the missing database and collection.
+func documentLevelError(err error) bool {
+	apiErr, ok := asAPIError(err)
+	if !ok || errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrRateLimited) || apiErr.HTTPStatus >= 500 {
+		return false
+	}
+	return apiErr.Code != ERR_UNDEFINED_DATABASE && apiErr.Code != ERR_UNDEFINED_COLLECTION
+}
+
+// sameAPIError reports whether the APIErrors of a and b have the same code and message.
+func sameAPIError(a, b error) bool {
+	x, okA := asAPIError(a)
+	y, okB := asAPIError(b)
+	return okA && okB && x.Code == y.Code && x.Message == y.Message
+}
+
+// documentId returns the id of Document or map document, empty if it has no id.
+func documentId(doc interface{}) string {
+	switch d := doc.(type) {
+	case Document:
+		return d.Id
+	case map[string]interface{}:
+		id, _ := d["id"].(string)
+		return id
+	}
+	return ""
+}
+
+// isUpsertReservedKey reports whether the key of map document is converted into the document instead of its fields.
+func isUpsertReservedKey(key string) bool {
+	return key == "id" || key == "vector" || key == "sparse_vector"
+}
+
+// upsertRequestOverhead the size reserved for the fields of upsert request other than the documents
+const upsertRequestOverhead = 1024
+
+// batchBounds returns the offsets where the batches start, followed by the length of docs.
+// Each batch has at most batchSize documents, and its documents are at most maxBytes in json if maxBytes > 0,
+// the document larger than maxBytes is in its own batch. The documents are encoded to measure only if the
+// estimated upper bound of any batch by count exceeds maxBytes.
+func batchBounds(docs reflect.Value, batchSize, maxBytes int) []int {
+	bounds := []int{0}
+	for offset := batchSize; offset < docs.Len(); offset += batchSize {
+		bounds = append(bounds, offset)
+	}
+	bounds = append(bounds, docs.Len())
+	if maxBytes <= 0 || !batchesMayExceed(docs, bounds, maxBytes) {
+		return bounds
+	}
+
+	bounds = []int{0}
+	count, size := 0, upsertRequestOverhead
+	for n := 0; n < docs.Len(); n++ {
+		doc := docs.Index(n).Interface()
+		if d, ok := doc.(Document); ok {
+			doc = exportDocument(d)
+		}
+		docSize := upsertRequestOverhead
+		if b, err := json.Marshal(doc); err == nil {
+			docSize = len(b) + 1
+		}
+		if count > 0 && (count >= batchSize || size+docSize > maxBytes) {
+			bounds = append(bounds, n)
+			count, size = 0, upsertRequestOverhead
+		}
+		count++
+		size += docSize
+	}
+	return append(bounds, docs.Len())
+}
+
+// batchesMayExceed reports whether the estimated upper bound of json size of any batch exceeds maxBytes,
+// or the size of any document could not be estimated.
+func batchesMayExceed(docs reflect.Value, bounds []int, maxBytes int) bool {
+	for b := 1; b < len(bounds); b++ {
+		size := upsertRequestOverhead
+		for n := bounds[b-1]; n < bounds[b]; n++ {
+			docSize, ok := estimateDocumentSize(docs.Index(n).Interface())
+			if !ok {
+				return true
+			}
+			if size += docSize; size > maxBytes {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The max json bytes of a float32 and a float64 or integer, with the separator.
+const (
+	maxFloat32JSONBytes = 16
+	maxNumberJSONBytes  = 25
+)
+
+// estimateDocumentSize returns the upper bound of the json size of the Document or map document,
+// ok is false if it has a field of type not estimated.
+func estimateDocumentSize(doc interface{}) (size int, ok bool) {
+	// the strings are estimated as all bytes escaped, such as \u00XX
+	key := func(name string) int { return len(name)*6 + 4 }
+	switch d := doc.(type) {
+	case Document:
+		size = 64 + len(d.Id)*6 + len(d.Vector)*maxFloat32JSONBytes + len(d.BinaryVector)*4 +
+			len(d.SparseVector)*(maxNumberJSONBytes+maxFloat32JSONBytes+3)
+		for name, field := range d.Fields {
+			valueSize, ok := estimateValueSize(field.Val)
+			if !ok {
+				return 0, false
+			}
+			size += key(name) + valueSize
+		}
+		return size, true
+	case map[string]interface{}:
+		size = 2
+		for name, val := range d {
+			valueSize, ok := estimateValueSize(val)
+			if !ok {
+				return 0, false
+			}
+			size += key(name) + valueSize
+		}
+		return size, true
+	}
+	return 0, false
+}
+
+func estimateValueSize(val interface{}) (int, bool) {
+	switch v := val.(type) {
+	case nil, bool:
+		return 6, true
+	case string:
+		return len(v)*6 + 3, true
+	case []float32:
+		return len(v)*maxFloat32JSONBytes + 2, true
+	case []byte:
+		// the binary vector sent as the float of each byte, or base64 if it is a field
+		return len(v)*4 + 2, true
+	case []string:
+		size := 2
+		for _, s := range v {
+			size += len(s)*6 + 3
+		}
+		return size, true
+	case []interface{}:
+		size := 2
+		for _, elem := range v {
+			elemSize, ok := estimateValueSize(elem)
+			if !ok {
+				return 0, false
+			}
+			size += elemSize
+		}
+		return size, true
+	case json.Number:
+		return len(v) + 1, true
+	}
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return maxNumberJSONBytes, true
+	case reflect.Slice, reflect.Array:
+		switch rv.Type().Elem().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint16,
+			reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+			return rv.Len()*maxNumberJSONBytes + 2, true
+		}
+	}
+	return 0, false
+}
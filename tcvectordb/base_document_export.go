@@ -0,0 +1,209 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+const defaultImportBatchSize = 100
+
+type ExportOption struct {
+	// BatchSize: the documents queried per page, default 100
+	BatchSize int64
+	// Filter: export the documents matching the filter, all documents if nil
+	Filter *Filter
+	// IncludeVectors: export the vectors and sparse vectors of the documents
+	IncludeVectors bool
+	// OnProgress: called after each page is written, with the documents and bytes written so far
+	OnProgress func(documents int, bytes int64)
+}
+
+type ExportResult struct {
+	Documents int
+	Bytes     int64
+}
+
+// Export write the documents of collection to w as JSON Lines, one document per line in the format of
+// the upsert api. The documents are queried page by page, see QueryIterator for the consistency.
+func (c *Collection) Export(ctx context.Context, w io.Writer, option ExportOption) (*ExportResult, error) {
+	it := c.QueryIterator(ctx, option.Filter, option.BatchSize, &QueryDocumentParams{RetrieveVector: option.IncludeVectors})
+	result := new(ExportResult)
+	var line bytes.Buffer
+	encoder := json.NewEncoder(&line)
+	encoder.SetEscapeHTML(false)
+	for !it.Done() {
+		docs, err := it.Next()
+		if err != nil {
+			return result, fmt.Errorf("export failed at offset %d: %w", it.Offset(), err)
+		}
+		for _, doc := range docs {
+			line.Reset()
+			if err = encoder.Encode(exportDocument(doc)); err != nil {
+				return result, fmt.Errorf("export document %s failed: %w", doc.Id, err)
+			}
+			n, err := w.Write(line.Bytes())
+			result.Bytes += int64(n)
+			if err != nil {
+				return result, err
+			}
+			result.Documents++
+		}
+		if option.OnProgress != nil {
+			option.OnProgress(result.Documents, result.Bytes)
+		}
+	}
+	return result, nil
+}
+
+func exportDocument(doc Document) *document.Document {
+	d := &document.Document{Id: doc.Id, Vector: doc.Vector}
+	if len(doc.BinaryVector) != 0 {
+		d.Vector = binaryToFloat32(doc.BinaryVector)
+	}
+	for _, sv := range doc.SparseVector {
+		d.SparseVector = append(d.SparseVector, []interface{}{sv.TermId, sv.Score})
+	}
+	if len(doc.Fields) != 0 {
+		d.Fields = make(map[string]interface{}, len(doc.Fields))
+		for k, v := range doc.Fields {
+			d.Fields[k] = v.Val
+		}
+	}
+	return d
+}
+
+type ImportOption struct {
+	// BatchSize: the documents upserted per request, default 100
+	BatchSize  int
+	BuildIndex *bool
+	// OnError: called with the line number, counted from 1, and the error of a line which is not a valid document.
+	// The line is skipped if it returns true, otherwise Import fails. Import fails on any invalid line if it is nil.
+	OnError func(line int, err error) bool
+	// OnProgress: called after each batch is upserted, with the documents and bytes imported so far
+	OnProgress func(documents int, bytes int64)
+}
+
+type ImportResult struct {
+	Documents int
+	// AffectedCount: the sum of AffectedCount of the Upserts, less than Documents if the server
+	// does not write some documents
+	AffectedCount int
+	// Skipped: the invalid lines skipped by OnError
+	Skipped int
+	Bytes   int64
+}
+
+// Import read the documents as JSON Lines written by Export from r, and upsert them in batches.
+// Only one batch of documents is kept in memory. The batches upserted before a failure are not rolled back,
+// the documents are counted in ImportResult.Documents.
+func (c *Collection) Import(ctx context.Context, r io.Reader, option ImportOption) (*ImportResult, error) {
+	batchSize := option.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	result := new(ImportResult)
+	batch := make([]Document, 0, batchSize)
+	var batchBytes int64
+	// firstLine the line number of the first document in batch
+	firstLine := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		res, err := c.Upsert(ctx, batch, &UpsertDocumentParams{BuildIndex: option.BuildIndex})
+		if err != nil {
+			return fmt.Errorf("import the documents from line %d failed: %w", firstLine, err)
+		}
+		result.Documents += len(batch)
+		result.AffectedCount += res.AffectedCount
+		result.Bytes += batchBytes
+		if option.OnProgress != nil {
+			option.OnProgress(result.Documents, result.Bytes)
+		}
+		batch, batchBytes = batch[:0], 0
+		return nil
+	}
+
+	reader := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return result, readErr
+		}
+		if len(bytes.TrimSpace(line)) != 0 {
+			doc, err := importDocument(line)
+			if err != nil {
+				if option.OnError == nil || !option.OnError(lineNum, err) {
+					return result, fmt.Errorf("import failed at line %d: %w", lineNum, err)
+				}
+				result.Skipped++
+			} else {
+				if len(batch) == 0 {
+					firstLine = lineNum
+				}
+				batch = append(batch, doc)
+				batchBytes += int64(len(line))
+			}
+		}
+		if len(batch) >= batchSize || readErr == io.EOF {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+		if readErr == io.EOF {
+			return result, nil
+		}
+	}
+}
+
+func importDocument(line []byte) (Document, error) {
+	var d document.Document
+	if err := json.Unmarshal(line, &d); err != nil {
+		return Document{}, err
+	}
+	if d.Id == "" {
+		return Document{}, fmt.Errorf("the id of document is empty")
+	}
+	doc := Document{Id: d.Id, Vector: d.Vector}
+	for _, sv := range d.SparseVector {
+		item, err := ConvSliceInterface2SparseVecItem(sv)
+		if err != nil {
+			return Document{}, fmt.Errorf("the sparse_vector of document %s is incorrect: %v", d.Id, err)
+		}
+		doc.SparseVector = append(doc.SparseVector, *item)
+	}
+	if len(d.Fields) != 0 {
+		doc.Fields = make(map[string]Field, len(d.Fields))
+		for k, v := range d.Fields {
+			doc.Fields[k] = Field{Val: v}
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,81 @@
+package tcvectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+func TestExportImport(t *testing.T) {
+	stored := []*document.Document{
+		{Id: "a", Vector: []float32{0.1, 0.2}, Fields: map[string]interface{}{"page": 1.0}},
+		{Id: "b", Vector: []float32{0.3, 0.4}, SparseVector: [][]interface{}{{1, 0.5}}, Fields: map[string]interface{}{"page": 2.0}},
+		{Id: "c", Vector: []float32{0.5, 0.6}},
+	}
+	var upserted [][]*document.Document
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/document/query":
+			req := new(document.QueryReq)
+			json.NewDecoder(r.Body).Decode(req)
+			end := req.Query.Offset + req.Query.Limit
+			if end > int64(len(stored)) {
+				end = int64(len(stored))
+			}
+			json.NewEncoder(w).Encode(document.QueryRes{Documents: stored[req.Query.Offset:end], Count: uint64(len(stored))})
+		case "/document/upsert":
+			req := new(document.UpsertReq)
+			json.NewDecoder(r.Body).Decode(req)
+			upserted = append(upserted, req.Documents)
+			w.Write([]byte(`{"code":0,"affectedCount":1}`))
+		}
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	var out bytes.Buffer
+	var progress []int
+	exported, err := coll.Export(context.Background(), &out, ExportOption{BatchSize: 2, IncludeVectors: true,
+		OnProgress: func(documents int, bytes int64) { progress = append(progress, documents) }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.Documents != 3 || exported.Bytes != int64(out.Len()) || len(progress) != 2 ||
+		strings.Count(out.String(), "\n") != 3 || !strings.Contains(out.String(), `"sparse_vector":[[1,0.5]]`) {
+		t.Errorf("unexpected export %+v, progress %v:\n%s", exported, progress, out.String())
+	}
+
+	input := out.String() + "not json\n" + `{"vector":[0.1]}`
+	var badLines []int
+	imported, err := coll.Import(context.Background(), strings.NewReader(input), ImportOption{BatchSize: 2,
+		OnError: func(line int, err error) bool {
+			badLines = append(badLines, line)
+			return true
+		}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Documents != 3 || imported.AffectedCount != 2 || imported.Skipped != 2 || len(badLines) != 2 || badLines[0] != 4 || badLines[1] != 5 {
+		t.Errorf("unexpected import %+v, bad lines %v", imported, badLines)
+	}
+	if len(upserted) != 2 || len(upserted[0]) != 2 || upserted[0][1].Id != "b" || upserted[0][1].Fields["page"] != json.Number("2") ||
+		len(upserted[0][1].SparseVector) != 1 || len(upserted[1]) != 1 || len(upserted[1][0].Vector) != 2 {
+		b, _ := json.Marshal(upserted)
+		t.Errorf("unexpected upserted documents %s", b)
+	}
+
+	_, err = coll.Import(context.Background(), strings.NewReader(input), ImportOption{})
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("expect import failed at the invalid line without OnError, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = coll.Import(ctx, strings.NewReader(input), ImportOption{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expect canceled, got %v", err)
+	}
+}
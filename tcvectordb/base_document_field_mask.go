@@ -0,0 +1,289 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// defaultFieldMaskBatchSize the documents of each query and upsert of Upsert with FieldMask,
+// which is smaller than the other batches as the vectors of existing documents are queried.
+const defaultFieldMaskBatchSize = 100
+
+type upsertFunc func(ctx context.Context, documents interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error)
+
+// upsertWithFieldMask writes the FieldMask fields of documents over the existing documents in batches,
+// by the Update of each document if the server supports FeatureUpdate, or else by querying the existing
+// documents and upserting the merged ones. The documents not existing are upserted as they are.
+func upsertWithFieldMask(ctx context.Context, cli SdkClient, flat FlatInterface, db, coll string, documents interface{},
+	param *UpsertDocumentParams, upsert upsertFunc) (*UpsertDocumentResult, error) {
+	docs, err := documentsAsMaps(documents)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		id, _ := doc["id"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("upsert failed, because the id of document is required by FieldMask")
+		}
+		ids = append(ids, id)
+	}
+	batchSize := param.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultFieldMaskBatchSize
+	}
+	batchParam := *param
+	batchParam.FieldMask = nil
+	batchParam.BatchSize = 0
+	native := featureSupported(ctx, cli, FeatureUpdate)
+
+	result := new(UpsertDocumentResult)
+	var warnings []string
+	for batch, offset := 0, 0; offset < len(docs); batch, offset = batch+1, offset+batchSize {
+		end := offset + batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		existing, err := queryExistingDocuments(ctx, flat, db, coll, ids[offset:end], !native)
+		if err != nil {
+			return result, &UpsertBatchError{Batch: batch, Offset: offset, Err: err}
+		}
+		var (
+			writes  []map[string]interface{}
+			indexes []int
+			updates []UpdateDocumentParams
+		)
+		for i, doc := range docs[offset:end] {
+			old, ok := existing[ids[offset+i]]
+			switch {
+			case !ok:
+				writes = append(writes, doc)
+				indexes = append(indexes, offset+i)
+			case native:
+				// the document without any field of mask is left as it is
+				update, ok, err := maskedUpdate(old.Id, doc, param.FieldMask)
+				if err != nil {
+					return result, &UpsertBatchError{Batch: batch, Offset: offset, Err: err}
+				}
+				if ok {
+					updates = append(updates, update)
+				}
+			default:
+				writes = append(writes, mergeMaskedFields(old, doc, param.FieldMask))
+				indexes = append(indexes, offset+i)
+			}
+		}
+		affected, updateWarnings, err := updateConcurrently(ctx, flat, db, coll, updates, param.BatchConcurrency)
+		result.AffectedCount += affected
+		warnings = append(warnings, updateWarnings...)
+		if err != nil {
+			return result, &UpsertBatchError{Batch: batch, Offset: offset, Err: err}
+		}
+		if len(writes) == 0 {
+			continue
+		}
+		res, err := upsert(ctx, writes, &batchParam)
+		if err != nil {
+			return result, &UpsertBatchError{Batch: batch, Offset: offset, Err: err}
+		}
+		result.AffectedCount += res.AffectedCount
+		result.EmbeddingExtraInfo.TokenUsed += res.EmbeddingExtraInfo.TokenUsed
+		if res.Warning != "" {
+			warnings = append(warnings, res.Warning)
+		}
+		for _, f := range res.FailedDocuments {
+			f.Index = indexes[f.Index]
+			result.FailedDocuments = append(result.FailedDocuments, f)
+		}
+	}
+	result.Warning = strings.Join(warnings, "; ")
+	return result, nil
+}
+
+// queryExistingDocuments queries the documents of ids with strong consistency, the vectors are
+// queried only if withVector.
+func queryExistingDocuments(ctx context.Context, flat FlatInterface, db, coll string, ids []string, withVector bool) (map[string]Document, error) {
+	param := &QueryDocumentParams{RetrieveVector: withVector, Limit: int64(len(ids)), ReadConsistency: StrongConsistency}
+	if !withVector {
+		param.OutputFields = []string{"id"}
+	}
+	res, err := flat.Query(ctx, db, coll, ids, param)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]Document, len(res.Documents))
+	for _, doc := range res.Documents {
+		existing[doc.Id] = doc
+	}
+	return existing, nil
+}
+
+// updateConcurrently sends the updates, at most concurrency (default 1) of them at the same time.
+// It returns the affected count and warnings of the updates succeeded, and the first error.
+func updateConcurrently(ctx context.Context, flat FlatInterface, db, coll string, updates []UpdateDocumentParams,
+	concurrency int) (affected int, warnings []string, err error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	for _, update := range updates {
+		sem <- struct{}{}
+		mu.Lock()
+		failed := err != nil
+		mu.Unlock()
+		if failed {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(update UpdateDocumentParams) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res, updateErr := flat.Update(ctx, db, coll, update)
+			mu.Lock()
+			defer mu.Unlock()
+			if updateErr != nil {
+				if err == nil {
+					err = updateErr
+				}
+				return
+			}
+			affected += res.AffectedCount
+			if res.Warning != "" {
+				warnings = append(warnings, res.Warning)
+			}
+		}(update)
+	}
+	wg.Wait()
+	return affected, warnings, err
+}
+
+// mergeMaskedFields returns the existing document with the mask fields of doc written over it.
+func mergeMaskedFields(existing Document, doc map[string]interface{}, mask []string) map[string]interface{} {
+	merged := documentAsMap(existing)
+	for _, name := range mask {
+		if val, ok := doc[name]; ok && name != "id" {
+			merged[name] = val
+		}
+	}
+	return merged
+}
+
+// maskedUpdate returns the Update of the mask fields of doc, including the vector and sparse_vector.
+// It returns false if doc has none of them to update, and error if the vector or sparse_vector has
+// the type rejected by Upsert as well.
+func maskedUpdate(id string, doc map[string]interface{}, mask []string) (UpdateDocumentParams, bool, error) {
+	param := UpdateDocumentParams{QueryIds: []string{id}}
+	fields := make(map[string]interface{})
+	for _, name := range mask {
+		val, ok := doc[name]
+		if !ok {
+			continue
+		}
+		switch name {
+		case "id":
+		case "vector":
+			switch v := val.(type) {
+			case []float32:
+				param.UpdateVector = v
+			case []byte:
+				param.UpdateVector = binaryToFloat32(v)
+			default:
+				return param, false, fmt.Errorf("upsert failed, because of incorrect vector field type of document %v, "+
+					"which must be []float32 or []byte", id)
+			}
+		case "sparse_vector":
+			sv, ok := val.([][]interface{})
+			if !ok {
+				return param, false, fmt.Errorf("upsert failed, because of incorrect sparse_vector field type of document %v, "+
+					"which must be [][]interface{}", id)
+			}
+			for _, item := range sv {
+				svItem, err := ConvSliceInterface2SparseVecItem(item)
+				if err != nil {
+					return param, false, fmt.Errorf("upsert failed. doc's sparse_vector data is incorrect. doc id is %v. err: %v", id, err)
+				}
+				param.UpdateSparseVec = append(param.UpdateSparseVec, *svItem)
+			}
+		default:
+			fields[name] = val
+		}
+	}
+	if len(fields) != 0 {
+		param.UpdateFields = fields
+	}
+	return param, len(fields) != 0 || len(param.UpdateVector) != 0 || len(param.UpdateSparseVec) != 0, nil
+}
+
+// checkMaskedDocuments runs the vector checks of Upsert on the documents with FieldMask, which are not
+// applied by the native Update. It returns the documents with NaN and Inf replaced if SanitizeVectors.
+func checkMaskedDocuments(ctx context.Context, coll *Collection, documents interface{}, param *UpsertDocumentParams) (interface{}, error) {
+	if !param.SkipDimensionCheck {
+		if err := checkDocumentsDimension(coll.schema(ctx), documents); err != nil {
+			return nil, coll.schemaError(ctx, err)
+		}
+	}
+	return checkDocumentVectors(documents, param.SanitizeVectors)
+}
+
+// documentsAsMaps converts the []Document or []map[string]interface{} to maps keyed as the upsert request.
+func documentsAsMaps(documents interface{}) ([]map[string]interface{}, error) {
+	switch docs := documents.(type) {
+	case []map[string]interface{}:
+		return docs, nil
+	case []Document:
+		maps := make([]map[string]interface{}, 0, len(docs))
+		for _, doc := range docs {
+			maps = append(maps, documentAsMap(doc))
+		}
+		return maps, nil
+	}
+	return nil, fmt.Errorf("upsert failed, because of incorrect documents type, which must be []Document or []map[string]interface{}")
+}
+
+func documentAsMap(doc Document) map[string]interface{} {
+	m := make(map[string]interface{}, len(doc.Fields)+3)
+	m["id"] = doc.Id
+	if len(doc.BinaryVector) != 0 {
+		m["vector"] = doc.BinaryVector
+	} else if len(doc.Vector) != 0 {
+		m["vector"] = doc.Vector
+	}
+	if len(doc.SparseVector) != 0 {
+		sv := make([][]interface{}, 0, len(doc.SparseVector))
+		for _, item := range doc.SparseVector {
+			sv = append(sv, []interface{}{item.TermId, item.Score})
+		}
+		m["sparse_vector"] = sv
+	}
+	for name, field := range doc.Fields {
+		m[name] = field.Val
+	}
+	return m
+}
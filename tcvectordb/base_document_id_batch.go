@@ -0,0 +1,217 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultIdBatchSize is the max number of ids sent in one Delete or Update request by default,
+// the server rejects the requests carrying too many ids.
+const defaultIdBatchSize = 1000
+
+// IdBatchError is returned by Delete and Update when the ids are sent in batches and a batch fails.
+// Offset is the index of the first id of the failed batch. Processed is the number of ids in the
+// batches succeeded, the ids before Offset are all processed if BatchConcurrency is 1.
+type IdBatchError struct {
+	Batch     int
+	Offset    int
+	Processed int
+	Err       error
+}
+
+func (e *IdBatchError) Error() string {
+	return fmt.Sprintf("id batch %d (offset %d) failed after %d ids processed: %v", e.Batch, e.Offset, e.Processed, e.Err)
+}
+
+func (e *IdBatchError) Unwrap() error {
+	return e.Err
+}
+
+// IdBatchErrors is returned by Delete and Update with ContinueOnError if some batches fail.
+// Failures are in order of the batches.
+type IdBatchErrors struct {
+	// Processed: the number of ids in the batches succeeded
+	Processed int
+	Failures  []*IdBatchError
+}
+
+func (e *IdBatchErrors) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		msgs = append(msgs, fmt.Sprintf("batch %d (offset %d): %v", f.Batch, f.Offset, f.Err))
+	}
+	return fmt.Sprintf("%d id batches failed, %d ids processed: %s", len(e.Failures), e.Processed, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed batches.
+func (e *IdBatchErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// idBatchOption is how sendIdBatches splits and sends the ids.
+type idBatchOption struct {
+	size            int
+	concurrency     int
+	continueOnError bool
+}
+
+// sendIdBatches sends the ids with send, split in order into batches of at most option.size ids
+// (defaultIdBatchSize if 0, no split if negative), at most option.concurrency batches at the same time.
+// It returns the sum of the affected count and the warnings of the succeeded batches. The ids fitting
+// in one batch are sent as they are, and the error of send is returned without wrapping.
+func sendIdBatches(ctx context.Context, ids []string, option idBatchOption,
+	send func(ctx context.Context, ids []string) (int, string, error)) (int, string, error) {
+	size := option.size
+	if size == 0 {
+		size = defaultIdBatchSize
+	}
+	if size < 0 || len(ids) <= size {
+		return send(ctx, ids)
+	}
+	concurrency := option.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		affected  int
+		processed int
+		warnings  = make(map[int]string)
+		failures  []*IdBatchError
+	)
+	stopped := func() bool {
+		return len(failures) != 0 && !option.continueOnError
+	}
+	sem := make(chan struct{}, concurrency)
+	for batch, offset := 0, 0; offset < len(ids); batch, offset = batch+1, offset+size {
+		end := offset + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		sem <- struct{}{}
+		mu.Lock()
+		if ctx.Err() != nil {
+			failures = append(failures, &IdBatchError{Batch: batch, Offset: offset, Err: ctx.Err()})
+		}
+		stop := stopped() || ctx.Err() != nil
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(batch, offset, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			n, warning, err := send(ctx, ids[offset:end])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, &IdBatchError{Batch: batch, Offset: offset, Err: err})
+				return
+			}
+			affected += n
+			processed += end - offset
+			if warning != "" {
+				warnings[batch] = warning
+			}
+		}(batch, offset, end)
+	}
+	wg.Wait()
+
+	batches := make([]int, 0, len(warnings))
+	for batch := range warnings {
+		batches = append(batches, batch)
+	}
+	sort.Ints(batches)
+	msgs := make([]string, 0, len(batches))
+	for _, batch := range batches {
+		msgs = append(msgs, warnings[batch])
+	}
+	warning := strings.Join(msgs, "; ")
+
+	if len(failures) == 0 {
+		return affected, warning, nil
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Offset < failures[j].Offset })
+	for _, f := range failures {
+		f.Processed = processed
+	}
+	if !option.continueOnError {
+		return affected, warning, failures[0]
+	}
+	return affected, warning, &IdBatchErrors{Processed: processed, Failures: failures}
+}
+
+// updateIdBatchOption is how Update sends the QueryIds of param.
+func updateIdBatchOption(param UpdateDocumentParams) idBatchOption {
+	return idBatchOption{size: param.BatchSize, concurrency: param.BatchConcurrency, continueOnError: param.ContinueOnError}
+}
+
+// deleteInBatches deletes the DocumentIds of param by batches with del. The Limit of param is shared by
+// the batches, which are sent one by one until Limit documents are deleted.
+func deleteInBatches(ctx context.Context, param DeleteDocumentParams,
+	del func(ctx context.Context, ids []string, limit int64) (int, string, error)) (*DeleteDocumentResult, error) {
+	option := idBatchOption{size: param.BatchSize, concurrency: param.BatchConcurrency, continueOnError: param.ContinueOnError}
+	if param.Limit > 0 {
+		option.concurrency = 1
+	}
+	remaining := param.Limit
+	affected, warning, err := sendIdBatches(ctx, param.DocumentIds, option, func(ctx context.Context, ids []string) (int, string, error) {
+		if param.Limit <= 0 {
+			return del(ctx, ids, 0)
+		}
+		if remaining <= 0 {
+			return 0, "", nil
+		}
+		n, warning, err := del(ctx, ids, remaining)
+		remaining -= int64(n)
+		return n, warning, err
+	})
+	result := &DeleteDocumentResult{AffectedCount: affected, Warning: warning}
+	if err != nil {
+		if isIdBatchError(err) {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// isIdBatchError reports whether err is returned by sendIdBatches for the failed batches,
+// with which the result of the succeeded batches is returned.
+func isIdBatchError(err error) bool {
+	switch err.(type) {
+	case *IdBatchError, *IdBatchErrors:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const defaultQueryIteratorBatchSize = 100
+
+// QueryIterator iterate the documents matching a filter page by page with offset and limit.
+// Documents deleted or inserted during the iteration may shift the pages,
+// so some documents could be skipped or returned twice.
+type QueryIterator struct {
+	ctx     context.Context
+	query   func(ctx context.Context, params *QueryDocumentParams) (*QueryDocumentResult, error)
+	params  QueryDocumentParams
+	total   uint64
+	started bool
+	done    bool
+}
+
+func newQueryIterator(ctx context.Context, filter *Filter, batchSize int64, params []*QueryDocumentParams,
+	query func(ctx context.Context, params *QueryDocumentParams) (*QueryDocumentResult, error)) *QueryIterator {
+	it := &QueryIterator{ctx: ctx, query: query}
+	if len(params) != 0 && params[0] != nil {
+		it.params = *params[0]
+	}
+	it.params.Filter = filter
+	it.params.Limit = batchSize
+	if it.params.Limit <= 0 {
+		it.params.Limit = defaultQueryIteratorBatchSize
+	}
+	return it
+}
+
+// Next query the next page of documents. It returns nil documents when the iteration is done.
+func (it *QueryIterator) Next() ([]Document, error) {
+	if it.done {
+		return nil, nil
+	}
+	params := it.params
+	res, err := it.query(it.ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+	if !it.started {
+		it.total = res.Total
+		it.started = true
+	}
+	it.params.Offset += int64(len(res.Documents))
+	if int64(len(res.Documents)) < it.params.Limit {
+		it.done = true
+	}
+	return res.Documents, nil
+}
+
+// Done reports whether all documents have been returned by Next.
+func (it *QueryIterator) Done() bool {
+	return it.done
+}
+
+// Total the number of documents matching the filter, returned by the first page.
+func (it *QueryIterator) Total() uint64 {
+	return it.total
+}
+
+// Offset the offset of the next page.
+func (it *QueryIterator) Offset() int64 {
+	return it.params.Offset
+}
+
+// SetOffset set the offset of the next page, used to resume a failed iteration.
+func (it *QueryIterator) SetOffset(offset int64) {
+	it.params.Offset = offset
+	it.done = false
+}
+
+// ScanByIdPrefix scans the documents matching the Filter of params page by page, and returns the ones whose ids
+// start with prefix, skipping offset of them and returning at most limit of them, limit 0 means no limit.
+// It is NOT a prefix query: the filter of server has no prefix operator, so the cost is O(documents matching
+// the Filter), which is the whole collection without Filter. Either the Filter of params or maxScanned must be set,
+// the documents found are returned with ErrScanLimitReached after maxScanned documents are scanned.
+// The Offset and Limit of params are ignored, and the Total of result is not set.
+func (c *Collection) ScanByIdPrefix(ctx context.Context, prefix string, limit, offset, maxScanned int64,
+	params ...*QueryDocumentParams) (*QueryDocumentResult, error) {
+	var filter *Filter
+	if len(params) != 0 && params[0] != nil {
+		filter = params[0].Filter
+	}
+	if filter.Cond() == "" && maxScanned <= 0 {
+		return nil, fmt.Errorf("scan by id prefix failed, because neither the Filter of params nor maxScanned is set, " +
+			"which scans the whole collection")
+	}
+	batchSize := int64(defaultQueryIteratorBatchSize)
+	if maxScanned > 0 && maxScanned < batchSize {
+		batchSize = maxScanned
+	}
+	it := c.QueryIterator(ctx, filter, batchSize, params...)
+	result := new(QueryDocumentResult)
+	var scanned int64
+	for !it.Done() {
+		if maxScanned > 0 && scanned >= maxScanned {
+			result.AffectedCount = len(result.Documents)
+			return result, ErrScanLimitReached
+		}
+		docs, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			scanned++
+			if !strings.HasPrefix(doc.Id, prefix) {
+				continue
+			}
+			if offset > 0 {
+				offset--
+				continue
+			}
+			result.Documents = append(result.Documents, doc)
+			if limit > 0 && int64(len(result.Documents)) >= limit {
+				result.AffectedCount = len(result.Documents)
+				return result, nil
+			}
+		}
+	}
+	result.AffectedCount = len(result.Documents)
+	return result, nil
+}
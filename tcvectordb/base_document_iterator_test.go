@@ -0,0 +1,109 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+func TestQueryIterator(t *testing.T) {
+	var offsets []int64
+	it := newQueryIterator(context.Background(), NewFilter("page > 1"), 4, []*QueryDocumentParams{{OutputFields: []string{"id"}}},
+		func(ctx context.Context, params *QueryDocumentParams) (*QueryDocumentResult, error) {
+			if params.Filter.Cond() != "page > 1" || params.Limit != 4 || len(params.OutputFields) != 1 {
+				t.Errorf("unexpected query params: %+v", params)
+			}
+			offsets = append(offsets, params.Offset)
+			n := 10 - params.Offset
+			if n > params.Limit {
+				n = params.Limit
+			}
+			return &QueryDocumentResult{Documents: make([]Document, n), Total: 10}, nil
+		})
+
+	count := 0
+	for !it.Done() {
+		docs, err := it.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		count += len(docs)
+	}
+	if count != 10 || it.Total() != 10 || len(offsets) != 3 || offsets[2] != 8 {
+		t.Errorf("unexpected iteration, count %d, total %d, offsets %v", count, it.Total(), offsets)
+	}
+
+	it.SetOffset(6)
+	docs, _ := it.Next()
+	if len(docs) != 4 || it.Offset() != 10 || it.Done() {
+		t.Errorf("unexpected page after SetOffset, docs %d, offset %d", len(docs), it.Offset())
+	}
+}
+
+func TestScanByIdPrefix(t *testing.T) {
+	ids := []string{`tenant:doc:chunk-0001`, `tenant:docs:chunk-0001`, `tenant:doc:chunk-0002`, `tenant:"doc":chunk-0001`,
+		`tenant:doc:chunk-0003`, `租户:doc:chunk-0001`, `tenant:doc:chunk-0004`, `租户:doc:chunk-0002`}
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req document.QueryReq
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Query.Filter != `page > 1` && req.Query.Filter != "" {
+			t.Errorf("expect the filter of params sent, got %q", req.Query.Filter)
+		}
+		end := req.Query.Offset + req.Query.Limit
+		if end > int64(len(ids)) {
+			end = int64(len(ids))
+		}
+		res := document.QueryRes{Count: uint64(len(ids))}
+		for _, id := range ids[req.Query.Offset:end] {
+			res.Documents = append(res.Documents, &document.Document{Id: id})
+		}
+		json.NewEncoder(w).Encode(res)
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	for _, c := range []struct {
+		prefix        string
+		limit, offset int64
+		expect        []string
+	}{
+		{`tenant:doc:`, 0, 0, []string{`tenant:doc:chunk-0001`, `tenant:doc:chunk-0002`, `tenant:doc:chunk-0003`, `tenant:doc:chunk-0004`}},
+		{`tenant:doc:`, 2, 1, []string{`tenant:doc:chunk-0002`, `tenant:doc:chunk-0003`}},
+		{`tenant:"doc"`, 0, 0, []string{`tenant:"doc":chunk-0001`}},
+		{`租户:`, 0, 1, []string{`租户:doc:chunk-0002`}},
+		{`none`, 0, 0, nil},
+	} {
+		res, err := coll.ScanByIdPrefix(context.Background(), c.prefix, c.limit, c.offset, 0,
+			&QueryDocumentParams{Filter: NewFilter("page > 1"), Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, doc := range res.Documents {
+			got = append(got, doc.Id)
+		}
+		if len(got) != len(c.expect) || res.AffectedCount != len(got) {
+			t.Errorf("prefix %q: expect %q, got %q", c.prefix, c.expect, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.expect[i] {
+				t.Errorf("prefix %q: expect %q, got %q", c.prefix, c.expect, got)
+			}
+		}
+	}
+
+	if _, err := coll.ScanByIdPrefix(context.Background(), `tenant:`, 0, 0, 0); err == nil {
+		t.Error("expect the scan of whole collection rejected without Filter or maxScanned")
+	}
+	res, err := coll.ScanByIdPrefix(context.Background(), `tenant:doc:`, 0, 0, 3)
+	if !errors.Is(err, ErrScanLimitReached) || res == nil || len(res.Documents) != 2 {
+		t.Errorf("expect the documents of the first 3 scanned with ErrScanLimitReached, got %+v, %v", res, err)
+	}
+	if res, err = coll.ScanByIdPrefix(context.Background(), `tenant:doc:`, 1, 0, 3); err != nil || len(res.Documents) != 1 {
+		t.Errorf("expect the limit reached before maxScanned, got %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const defaultMultiSearchConcurrency = 4
+
+// CollectionTarget a collection searched by MultiSearch.
+type CollectionTarget struct {
+	Database   string
+	Collection string
+	// MetricType: the metric type of the vector index to merge the scores, the collection is described if it is empty
+	MetricType MetricType
+}
+
+func (t CollectionTarget) String() string {
+	return t.Database + "." + t.Collection
+}
+
+type MergeOption struct {
+	// Concurrency: the max collections searched at the same time, default 4
+	Concurrency int
+	// TotalLimit: the max documents of each vector merged from all collections, default the Limit of params
+	TotalLimit int
+	// DedupByID: keep the best one of the documents with the same id from different collections
+	DedupByID bool
+	// FailFast: return the error once a collection fails, instead of the results of the other collections
+	FailFast bool
+}
+
+// MultiSearchHit a document of MultiSearch with the collection it comes from,
+// the MetricType of Target is the one used to merge the scores.
+type MultiSearchHit struct {
+	Document
+	Target CollectionTarget
+}
+
+type MultiSearchResult struct {
+	// Documents: the merged documents of each vector, the most similar first
+	Documents [][]MultiSearchHit
+	// Errors: the errors of each target, nil for the successful targets
+	Errors []error
+}
+
+// MultiSearch search the vectors in each collection of targets with bounded concurrency, and merges the documents
+// of each vector by score. The scores are compared directly if all collections have the same metric type,
+// otherwise by Document.NormalizedScore. It returns error only if all collections failed, or any with FailFast.
+func MultiSearch(ctx context.Context, cli DatabaseInterface, targets []CollectionTarget, vectors [][]float32,
+	params *SearchDocumentParams, option MergeOption) (*MultiSearchResult, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no collection to search")
+	}
+	concurrency := option.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultMultiSearchConcurrency
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		failed   int
+		results  = make([]*SearchDocumentResult, len(targets))
+		metrics  = make([]MetricType, len(targets))
+		errs     = make([]error, len(targets))
+	)
+	sem := make(chan struct{}, concurrency)
+	for n := range targets {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(n int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res, metric, err := searchTarget(ctx, cli, targets[n], vectors, params)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[n] = fmt.Errorf("search collection %s failed: %w", targets[n], err)
+				failed++
+				if firstErr == nil {
+					firstErr = errs[n]
+				}
+				if option.FailFast {
+					cancel()
+				}
+				return
+			}
+			results[n], metrics[n] = res, metric
+		}(n)
+	}
+	wg.Wait()
+	if failed == len(targets) || option.FailFast && firstErr != nil {
+		return nil, firstErr
+	}
+
+	limit := option.TotalLimit
+	if limit <= 0 && params != nil {
+		limit = int(params.Limit)
+	}
+	less := multiSearchLess(metrics, results)
+	result := &MultiSearchResult{Documents: make([][]MultiSearchHit, len(vectors))}
+	if failed != 0 {
+		result.Errors = errs
+	}
+	for v := range vectors {
+		var hits []MultiSearchHit
+		for n, res := range results {
+			if res == nil || v >= len(res.Documents) {
+				continue
+			}
+			target := targets[n]
+			target.MetricType = metrics[n]
+			for _, doc := range res.Documents[v] {
+				hits = append(hits, MultiSearchHit{Document: doc, Target: target})
+			}
+		}
+		sort.SliceStable(hits, func(i, j int) bool {
+			return less(hits[i], hits[j])
+		})
+		if option.DedupByID {
+			seen := make(map[string]bool, len(hits))
+			deduped := hits[:0]
+			for _, hit := range hits {
+				if !seen[hit.Id] {
+					seen[hit.Id] = true
+					deduped = append(deduped, hit)
+				}
+			}
+			hits = deduped
+		}
+		if limit > 0 && len(hits) > limit {
+			hits = hits[:limit]
+		}
+		result.Documents[v] = hits
+	}
+	return result, nil
+}
+
+// searchTarget search the vectors in the collection of target, and returns the metric type of its vector index.
+func searchTarget(ctx context.Context, cli DatabaseInterface, target CollectionTarget, vectors [][]float32,
+	params *SearchDocumentParams) (*SearchDocumentResult, MetricType, error) {
+	db := cli.Database(target.Database)
+	coll := db.Collection(target.Collection)
+	metric := target.MetricType
+	if metric == "" {
+		res, err := db.DescribeCollection(ctx, target.Collection)
+		if err != nil {
+			return nil, "", err
+		}
+		coll = &res.Collection
+		if len(coll.Indexes.VectorIndex) != 0 {
+			metric = coll.Indexes.VectorIndex[0].MetricType
+		}
+	}
+	res, err := coll.Search(ctx, vectors, params)
+	if err != nil {
+		return nil, "", err
+	}
+	return res, metric, nil
+}
+
+// multiSearchLess returns the order of hits, by the scores if the successful collections have the same metric type,
+// otherwise by the normalized scores.
+func multiSearchLess(metrics []MetricType, results []*SearchDocumentResult) func(a, b MultiSearchHit) bool {
+	metric := MetricType("")
+	mixed := false
+	for n, m := range metrics {
+		if results[n] == nil {
+			continue
+		}
+		if metric == "" {
+			metric = m
+		} else if m != metric {
+			mixed = true
+		}
+	}
+	if mixed {
+		return func(a, b MultiSearchHit) bool {
+			return a.NormalizedScore(a.Target.MetricType) > b.NormalizedScore(b.Target.MetricType)
+		}
+	}
+	if metric == L2 || metric == HAMMING {
+		return func(a, b MultiSearchHit) bool { return a.Score < b.Score }
+	}
+	return func(a, b MultiSearchHit) bool { return a.Score > b.Score }
+}
@@ -0,0 +1,66 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestMultiSearch(t *testing.T) {
+	scores := map[string]string{
+		"m1": `[{"id":"a","score":0.3},{"id":"b","score":0.9}]`,
+		"m2": `[{"id":"c","score":0.1},{"id":"a","score":0.5}]`,
+	}
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Collection string }
+		json.NewDecoder(r.Body).Decode(&req)
+		docs, ok := scores[req.Collection]
+		if !ok {
+			w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/collection/describe":
+			fmt.Fprintf(w, `{"code":0,"collection":{"collection":%q,"indexes":[
+				{"fieldName":"vector","fieldType":"vector","indexType":"HNSW","dimension":2,"metricType":"L2"}]}}`, req.Collection)
+		case "/document/search":
+			fmt.Fprintf(w, `{"code":0,"documents":[%s]}`, docs)
+		}
+	}, ClientOption{})
+	targets := []CollectionTarget{{Database: "db", Collection: "m1"}, {Database: "db", Collection: "m2"}}
+	vectors := [][]float32{{0.1, 0.2}}
+
+	res, err := MultiSearch(context.Background(), cli, targets, vectors, &SearchDocumentParams{Limit: 2}, MergeOption{DedupByID: true, TotalLimit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, hit := range res.Documents[0] {
+		got = append(got, fmt.Sprintf("%s@%s", hit.Id, hit.Target.Collection))
+	}
+	if fmt.Sprint(got) != "[c@m2 a@m1 b@m1]" || res.Errors != nil || res.Documents[0][0].Target.MetricType != L2 {
+		t.Errorf("expect hits merged by ascending L2 distance, got %v, %v", got, res.Errors)
+	}
+
+	// the scores of COSINE collection are compared with the L2 ones by the normalized scores
+	targets = append(targets, CollectionTarget{Database: "db", Collection: "missing"})
+	targets[1].MetricType = COSINE
+	res, err = MultiSearch(context.Background(), cli, targets, vectors, &SearchDocumentParams{Limit: 2}, MergeOption{Concurrency: 1, TotalLimit: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = got[:0]
+	for _, hit := range res.Documents[0] {
+		got = append(got, hit.Id)
+	}
+	if fmt.Sprint(got) != "[a a c b]" || len(res.Errors) != 3 || res.Errors[2] == nil || !IsCollectionNotExist(res.Errors[2]) {
+		t.Errorf("unexpected mixed metric result %v, %v", got, res.Errors)
+	}
+
+	_, err = MultiSearch(context.Background(), cli, targets, vectors, nil, MergeOption{FailFast: true})
+	if !IsCollectionNotExist(err) {
+		t.Errorf("expect the error of missing collection with FailFast, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultSessionWindow the time the reads of Session use StrongConsistency after a write.
+const defaultSessionWindow = 5 * time.Second
+
+// SessionOption the optional params of Collection.Session.
+type SessionOption struct {
+	// Window: how long the reads use StrongConsistency after the last write of session, default 5s
+	Window time.Duration
+}
+
+// Session reads its own writes: the reads made through it use StrongConsistency until Window
+// after the last write made through it, then the ReadConsistency of client again. The ReadConsistency
+// set by the params of a read is kept. A Session is cheap and safe for concurrent use, the writes
+// and reads not made through it are not affected.
+type Session struct {
+	// lastWrite: the unix nano of the last write, 0 if there is none. It is the first field
+	// to be 64-bit aligned for the atomic access on 32-bit platforms.
+	lastWrite int64
+
+	coll   *Collection
+	window time.Duration
+	now    func() time.Time
+
+	once   sync.Once
+	strong *Collection
+}
+
+// Session returns a Session of the collection, see Session.
+func (c *Collection) Session(option ...SessionOption) *Session {
+	s := &Session{coll: c, window: defaultSessionWindow, now: time.Now}
+	if len(option) != 0 && option[0].Window > 0 {
+		s.window = option[0].Window
+	}
+	return s
+}
+
+// wrote records a write, which is recorded even if it fails, as the documents could be written partly.
+func (s *Session) wrote() {
+	atomic.StoreInt64(&s.lastWrite, s.now().UnixNano())
+}
+
+// reader returns the collection with StrongConsistency within the window after a write, or else the collection.
+func (s *Session) reader() *Collection {
+	last := atomic.LoadInt64(&s.lastWrite)
+	if last == 0 || s.now().Sub(time.Unix(0, last)) >= s.window {
+		return s.coll
+	}
+	s.once.Do(func() {
+		s.strong = s.coll.WithDefaults(CallOptions{ReadConsistency: StrongConsistency})
+	})
+	return s.strong
+}
+
+// Upsert upsert the documents by Collection.Upsert, and reads the writes within the window.
+func (s *Session) Upsert(ctx context.Context, documents interface{}, params ...*UpsertDocumentParams) (*UpsertDocumentResult, error) {
+	defer s.wrote()
+	return s.coll.Upsert(ctx, documents, params...)
+}
+
+// Update update the documents by Collection.Update, and reads the writes within the window.
+func (s *Session) Update(ctx context.Context, param UpdateDocumentParams) (*UpdateDocumentResult, error) {
+	defer s.wrote()
+	return s.coll.Update(ctx, param)
+}
+
+// Delete delete the documents by Collection.Delete, and reads the writes within the window.
+func (s *Session) Delete(ctx context.Context, param DeleteDocumentParams) (*DeleteDocumentResult, error) {
+	defer s.wrote()
+	return s.coll.Delete(ctx, param)
+}
+
+// Query query the documents by Collection.Query.
+func (s *Session) Query(ctx context.Context, documentIds []string, params ...*QueryDocumentParams) (*QueryDocumentResult, error) {
+	return s.reader().Query(ctx, documentIds, params...)
+}
+
+// Get get the document by Collection.Get.
+func (s *Session) Get(ctx context.Context, id string, params ...*GetDocumentParams) (*Document, error) {
+	return s.reader().Get(ctx, id, params...)
+}
+
+// Count count the documents by Collection.Count.
+func (s *Session) Count(ctx context.Context, filter *Filter, params ...*CountDocumentParams) (*CountDocumentResult, error) {
+	return s.reader().Count(ctx, filter, params...)
+}
+
+// Search search the documents by Collection.Search.
+func (s *Session) Search(ctx context.Context, vectors [][]float32, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	return s.reader().Search(ctx, vectors, params...)
+}
+
+// SearchById search the documents by Collection.SearchById.
+func (s *Session) SearchById(ctx context.Context, documentIds []string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	return s.reader().SearchById(ctx, documentIds, params...)
+}
+
+// HybridSearch search the documents by Collection.HybridSearch.
+func (s *Session) HybridSearch(ctx context.Context, params HybridSearchDocumentParams) (*SearchDocumentResult, error) {
+	return s.reader().HybridSearch(ctx, params)
+}
@@ -0,0 +1,54 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSession(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		consistences []string
+	)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/document/upsert" {
+			mu.Lock()
+			consistency, _ := body["readConsistency"].(string)
+			consistences = append(consistences, consistency)
+			mu.Unlock()
+		}
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{ReadConsistency: EventualConsistency})
+	ctx := context.Background()
+
+	clock := time.Now()
+	sess := cli.Database("db").Collection("coll").Session(SessionOption{Window: time.Second})
+	sess.now = func() time.Time { return clock }
+
+	sess.Query(ctx, []string{"doc1"})
+	if _, err := sess.Upsert(ctx, []Document{{Id: "doc1", Vector: []float32{1}}}); err != nil {
+		t.Fatal(err)
+	}
+	sess.Query(ctx, []string{"doc1"})
+	sess.Search(ctx, [][]float32{{1}})
+	sess.Query(ctx, []string{"doc1"}, &QueryDocumentParams{ReadConsistency: EventualConsistency})
+	clock = clock.Add(time.Second)
+	sess.Query(ctx, []string{"doc1"})
+
+	expected := []string{string(EventualConsistency), string(StrongConsistency), string(StrongConsistency),
+		string(EventualConsistency), string(EventualConsistency)}
+	if len(consistences) != len(expected) {
+		t.Fatalf("expect %d reads, got %v", len(expected), consistences)
+	}
+	for i := range expected {
+		if consistences[i] != expected[i] {
+			t.Errorf("expect read %d of %s consistency, got %v", i, expected[i], consistences)
+		}
+	}
+}
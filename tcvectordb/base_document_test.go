@@ -0,0 +1,1295 @@
+package tcvectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvdbtext/encoder"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+func TestUpsertInBatches(t *testing.T) {
+	docs := make([]Document, 10)
+	for i := range docs {
+		docs[i].Id = string(rune('a' + i))
+	}
+
+	var (
+		mu    sync.Mutex
+		sizes []int
+	)
+	res, err := upsertInBatches(context.Background(), docs, &UpsertDocumentParams{BatchSize: 4, BatchConcurrency: 2, SanitizeVectors: true}, 0,
+		func(ctx context.Context, documents interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+			if param.BatchSize != 0 {
+				t.Errorf("batch param should not carry BatchSize, got %d", param.BatchSize)
+			}
+			if !param.SanitizeVectors {
+				t.Error("batch param should carry the other options")
+			}
+			batch := documents.([]Document)
+			mu.Lock()
+			sizes = append(sizes, len(batch))
+			mu.Unlock()
+			return &UpsertDocumentResult{AffectedCount: len(batch)}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AffectedCount != 10 {
+		t.Errorf("expect AffectedCount 10, got %d", res.AffectedCount)
+	}
+	if len(sizes) != 3 {
+		t.Errorf("expect 3 batches, got %v", sizes)
+	}
+}
+
+func TestUpsertInBatchesFailed(t *testing.T) {
+	docs := make([]map[string]interface{}, 7)
+	failure := errors.New("server error")
+	calls := 0
+	res, err := upsertInBatches(context.Background(), docs, &UpsertDocumentParams{BatchSize: 3}, 0,
+		func(ctx context.Context, documents interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+			calls++
+			if calls == 2 {
+				return nil, failure
+			}
+			return &UpsertDocumentResult{AffectedCount: len(documents.([]map[string]interface{}))}, nil
+		})
+	var batchErr *UpsertBatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expect UpsertBatchError, got %v", err)
+	}
+	if batchErr.Batch != 1 || batchErr.Offset != 3 || !errors.Is(err, failure) {
+		t.Errorf("unexpected batch error: %v", batchErr)
+	}
+	if res.AffectedCount != 3 || calls != 2 {
+		t.Errorf("expect stop after failed batch, AffectedCount %d, calls %d", res.AffectedCount, calls)
+	}
+}
+
+func TestUpsertInBatchesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	docs := make([]Document, 5)
+	_, err := upsertInBatches(ctx, docs, &UpsertDocumentParams{BatchSize: 2}, 0,
+		func(ctx context.Context, documents interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+			cancel()
+			return &UpsertDocumentResult{AffectedCount: 2}, nil
+		})
+	var batchErr *UpsertBatchError
+	if !errors.As(err, &batchErr) || batchErr.Offset != 2 || !errors.Is(err, context.Canceled) {
+		t.Errorf("expect canceled at offset 2, got %v", err)
+	}
+}
+
+func TestUpdateEmpty(t *testing.T) {
+	flat := &implementerFlatDocument{}
+	_, err := flat.Update(context.Background(), "db", "coll", UpdateDocumentParams{QueryIds: []string{"0001"}})
+	if err != errEmptyUpdate {
+		t.Errorf("expect errEmptyUpdate, got %v", err)
+	}
+	_, err = flat.Update(context.Background(), "db", "coll", UpdateDocumentParams{
+		QueryIds:     []string{"0001"},
+		UpdateFields: map[string]interface{}{},
+	})
+	if err != errEmptyUpdate {
+		t.Errorf("expect errEmptyUpdate, got %v", err)
+	}
+}
+
+func TestDeleteEmpty(t *testing.T) {
+	flat := &implementerFlatDocument{}
+	_, err := flat.Delete(context.Background(), "db", "coll", DeleteDocumentParams{Filter: NewFilter("")})
+	if err != errEmptyDelete {
+		t.Errorf("expect errEmptyDelete, got %v", err)
+	}
+}
+
+func TestUpsertDuplicatedSparseVector(t *testing.T) {
+	flat := &implementerFlatDocument{}
+	_, err := flat.Upsert(context.Background(), "db", "coll", []Document{{
+		Id:           "0001",
+		SparseVector: []encoder.SparseVecItem{{TermId: 1, Score: 0.1}, {TermId: 1, Score: 0.2}},
+	}})
+	if err == nil {
+		t.Error("expect error of duplicated sparse_vector term id")
+	}
+}
+
+func TestUpsertUnsortedSparseVector(t *testing.T) {
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{})
+	sparse := []encoder.SparseVecItem{{TermId: 3, Score: 0.3}, {TermId: 1, Score: 0.1}}
+	_, err := cli.Database("db").Collection("coll").Upsert(context.Background(), []Document{{Id: "0001", SparseVector: sparse}})
+	if err != nil || !strings.Contains(body, `"sparse_vector":[[1,0.1],[3,0.3]]`) || sparse[0].TermId != 3 {
+		t.Errorf("expect the term ids sorted in a copy, got %s, %v", body, err)
+	}
+	_, err = cli.Database("db").Collection("coll").Upsert(context.Background(), []map[string]interface{}{
+		{"id": "0002", "sparse_vector": [][]interface{}{{uint64(2), 0.2}, {uint64(1), 0.1}, {uint64(2), 0.3}}}})
+	if err == nil || !strings.Contains(err.Error(), "duplicated term id 2") {
+		t.Errorf("expect the unsorted duplicated term id rejected, got %v", err)
+	}
+}
+
+func TestUpsertMixedArray(t *testing.T) {
+	flat := &implementerFlatDocument{}
+	_, err := flat.Upsert(context.Background(), "db", "coll", []map[string]interface{}{{
+		"id":   "0001",
+		"tags": []interface{}{"a", 1},
+	}})
+	if err == nil {
+		t.Error("expect error of mixed-type array field")
+	}
+	err = checkArrayFields([]Document{{Id: "0001", Fields: map[string]Field{"tags": {Val: []string{"a", "b"}}}}})
+	if err != nil {
+		t.Errorf("expect string array accepted, got %v", err)
+	}
+}
+
+func TestDimensionCheck(t *testing.T) {
+	coll := (&Collection{}).WithDimension(3)
+	doc := &implementerDocument{flat: &implementerFlatDocument{}, database: &Database{}, collection: coll}
+
+	_, err := doc.Upsert(context.Background(), []Document{
+		{Id: "0001", Vector: []float32{0.1, 0.2, 0.3}},
+		{Id: "0002", Vector: []float32{0.1, 0.2}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "document 0002") {
+		t.Errorf("expect dimension error of document 0002, got %v", err)
+	}
+	_, err = doc.Upsert(context.Background(), []map[string]interface{}{
+		{"id": "0003", "vector": []interface{}{0.1}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "document 0003") {
+		t.Errorf("expect dimension error of document 0003, got %v", err)
+	}
+	_, err = doc.Search(context.Background(), [][]float32{{0.1, 0.2, 0.3}, {0.1}})
+	if err == nil || !strings.Contains(err.Error(), "vectors[1]") {
+		t.Errorf("expect dimension error of vectors[1], got %v", err)
+	}
+
+	if err = checkSearchDimension(&Collection{}, [][]float32{{0.1}}); err != nil {
+		t.Errorf("expect no check without dimension, got %v", err)
+	}
+	coll.Embedding.Enabled = true
+	if err = checkDocumentsDimension(coll, []Document{{Id: "0004"}}); err != nil {
+		t.Errorf("expect no vector allowed with embedding, got %v", err)
+	}
+}
+
+func TestBinaryVector(t *testing.T) {
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0,"documents":[{"id":"0001","vector":[1,255]}]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+	coll.Indexes.BinaryVectorIndex = []BinaryVectorIndex{{FieldName: "vector", FieldType: BinaryVector,
+		IndexType: BIN_FLAT, Dimension: 16, MetricType: HAMMING}}
+
+	_, err := coll.Upsert(context.Background(), []Document{{Id: "0001", BinaryVector: []byte{1, 2, 3}}})
+	if err == nil || !strings.Contains(err.Error(), "document 0001") {
+		t.Errorf("expect binary vector length error, got %v", err)
+	}
+	_, err = coll.Upsert(context.Background(), []Document{{Id: "0001", BinaryVector: []byte{1, 255}}})
+	if err != nil || !strings.Contains(body, `"vector":[1,255]`) {
+		t.Errorf("expect binary vector sent as uint8 array, body %s, err %v", body, err)
+	}
+
+	_, err = coll.SearchBinary(context.Background(), [][]byte{{1}})
+	if err == nil || !strings.Contains(err.Error(), "vectors[0]") {
+		t.Errorf("expect binary vector length error, got %v", err)
+	}
+	res, err := coll.Query(context.Background(), []string{"0001"}, &QueryDocumentParams{RetrieveVector: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc := res.Documents[0]; !bytes.Equal(doc.BinaryVector, []byte{1, 255}) || doc.Vector != nil {
+		t.Errorf("expect vector returned as BinaryVector, got %+v", doc)
+	}
+}
+
+func TestQuerySort(t *testing.T) {
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0,"documents":[{"id":"0002"},{"id":"0001"}]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+	coll.Indexes.FilterIndex = []FilterIndex{{FieldName: "id", FieldType: String, IndexType: PRIMARY},
+		{FieldName: "timestamp", FieldType: Uint64, IndexType: FILTER}}
+
+	res, err := coll.Query(context.Background(), nil, &QueryDocumentParams{
+		Limit: 100,
+		Sort:  []SortRule{{FieldName: "timestamp", Direction: SortDesc}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, `"sort":[{"fieldName":"timestamp","direction":"desc"}]`) {
+		t.Errorf("expect sort in request, got %s", body)
+	}
+	if res.Documents[0].Id != "0002" {
+		t.Errorf("expect documents in server order, got %+v", res.Documents)
+	}
+
+	_, err = coll.Query(context.Background(), nil, &QueryDocumentParams{Sort: []SortRule{{FieldName: "page"}}})
+	if err == nil || !strings.Contains(err.Error(), "not a filter index") {
+		t.Errorf("expect error of sorting by non-indexed field, got %v", err)
+	}
+	_, err = coll.Query(context.Background(), nil, &QueryDocumentParams{Sort: []SortRule{{FieldName: "timestamp", Direction: "down"}}})
+	if err == nil {
+		t.Error("expect error of invalid sort direction")
+	}
+}
+
+func TestReadConsistencyOverride(t *testing.T) {
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{ReadConsistency: EventualConsistency})
+	coll := cli.Database("db").Collection("coll")
+
+	_, err := coll.Query(context.Background(), []string{"0001"})
+	if err != nil || !strings.Contains(body, `"readConsistency":"eventualConsistency"`) {
+		t.Errorf("expect default read consistency in query, body %s, err %v", body, err)
+	}
+	_, err = coll.Query(context.Background(), []string{"0001"}, &QueryDocumentParams{ReadConsistency: StrongConsistency})
+	if err != nil || !strings.Contains(body, `"readConsistency":"strongConsistency"`) {
+		t.Errorf("expect overridden read consistency in query, body %s, err %v", body, err)
+	}
+	_, err = coll.Search(context.Background(), [][]float32{{0.1}}, &SearchDocumentParams{})
+	if err != nil || !strings.Contains(body, `"readConsistency":"eventualConsistency"`) {
+		t.Errorf("expect default read consistency in search, body %s, err %v", body, err)
+	}
+	_, err = coll.SearchById(context.Background(), []string{"0001"}, &SearchDocumentParams{ReadConsistency: StrongConsistency})
+	if err != nil || !strings.Contains(body, `"readConsistency":"strongConsistency"`) {
+		t.Errorf("expect overridden read consistency in searchById, body %s, err %v", body, err)
+	}
+}
+
+func TestSearchPartialFailure(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := new(document.SearchReq)
+		json.NewDecoder(r.Body).Decode(req)
+		for _, v := range req.Search.Vectors {
+			if len(v) != 2 {
+				w.Write([]byte(`{"code":1,"msg":"invalid dimension"}`))
+				return
+			}
+		}
+		w.Write([]byte(`{"code":0,"warning":"w","documents":[[{"id":"0001","score":0.9}]]}`))
+	}, ClientOption{})
+
+	vectors := [][]float32{{0.1, 0.2}, {0.1}}
+	_, err := cli.Search(context.Background(), "db", "coll", vectors)
+	if err == nil {
+		t.Fatal("expect the whole search failed without PartialFailure")
+	}
+	res, err := cli.Search(context.Background(), "db", "coll", vectors, &SearchDocumentParams{PartialFailure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Documents[0]) != 1 || res.Errors[0] != nil || res.Documents[1] != nil || res.Errors[1] == nil {
+		t.Errorf("expect results of vector 0 and error of vector 1, got %+v", res)
+	}
+	if res.Warning != "w" {
+		t.Errorf("expect warning of the successful search, got %q", res.Warning)
+	}
+}
+
+func TestEmbeddingExtraInfo(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"affectedCount":1,"documents":[],"embeddingExtraInfo":{"tokenUsed":12}}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	upsert, err := coll.Upsert(context.Background(), []map[string]interface{}{{"id": "0001", "text": "a"}, {"id": "0002", "text": "b"}},
+		&UpsertDocumentParams{BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upsert.EmbeddingExtraInfo.TokenUsed != 24 {
+		t.Errorf("expect token used summed by batches, got %d", upsert.EmbeddingExtraInfo.TokenUsed)
+	}
+	search, err := coll.SearchByText(context.Background(), map[string][]string{"text": {"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if search.EmbeddingExtraInfo.TokenUsed != 12 {
+		t.Errorf("expect token used of search, got %d", search.EmbeddingExtraInfo.TokenUsed)
+	}
+}
+
+func TestSearchOutputVector(t *testing.T) {
+	var req document.SearchReq
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Search.RetrieveVector {
+			w.Write([]byte(`{"code":0,"documents":[[{"id":"0001","score":0.9,"vector":[0.1,0.2],"author":"a"}]]}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"documents":[[{"id":"0001","score":0.9,"author":"a"}]]}`))
+	}, ClientOption{})
+
+	res, err := cli.Search(context.Background(), "db", "coll", [][]float32{{0.1, 0.2}},
+		&SearchDocumentParams{OutputFields: []string{"author"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := res.Documents[0][0]
+	if doc.Vector != nil || doc.Fields["author"].String() != "a" || len(doc.Fields) != 1 {
+		t.Errorf("expect no vector allocated, got %+v", doc)
+	}
+
+	res, err = cli.Search(context.Background(), "db", "coll", [][]float32{{0.1, 0.2}},
+		&SearchDocumentParams{OutputFields: []string{"author", "vector"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc = res.Documents[0][0]
+	if !req.Search.RetrieveVector || len(doc.Vector) != 2 || len(doc.Fields) != 1 {
+		t.Errorf("expect vector retrieved by output fields, got %+v", doc)
+	}
+}
+
+func TestGetDocument(t *testing.T) {
+	var req document.QueryReq
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Query.DocumentIds[0] == "missing" {
+			w.Write([]byte(`{"code":0,"count":0,"documents":[]}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"count":1,"documents":[{"id":"0001","author":"a"}]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	doc, err := coll.Get(context.Background(), "0001", &GetDocumentParams{OutputFields: []string{"author"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Id != "0001" || doc.Fields["author"].String() != "a" || req.Query.OutputFields[0] != "author" || req.Query.RetrieveVector {
+		t.Errorf("unexpected document %+v of request %+v", doc, req.Query)
+	}
+	_, err = coll.Get(context.Background(), "missing")
+	if !errors.Is(err, ErrDocumentNotExist) {
+		t.Errorf("expect ErrDocumentNotExist, got %v", err)
+	}
+
+	docs, missing, err := coll.GetMulti(context.Background(), []string{"0001", "0002"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs["0001"].Id != "0001" || len(missing) != 1 || missing[0] != "0002" || req.Query.Limit != 2 {
+		t.Errorf("unexpected documents %v, missing %v", docs, missing)
+	}
+}
+
+func TestUpsertAutoId(t *testing.T) {
+	var calls int
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"code":0,"affectedCount":1,"ids":["auto-` + string(rune('0'+calls)) + `"]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	docs := []Document{{Vector: []float32{0.1}}, {Vector: []float32{0.2}}}
+	_, err := coll.Upsert(context.Background(), docs)
+	if err == nil || calls != 0 {
+		t.Fatalf("expect empty id rejected before sending, calls %d, err %v", calls, err)
+	}
+	res, err := coll.Upsert(context.Background(), docs, &UpsertDocumentParams{AutoId: true, BatchSize: 1, BatchConcurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Ids) != 2 || res.Ids[0] == res.Ids[1] {
+		t.Errorf("expect ids of both batches, got %v", res.Ids)
+	}
+
+	coll.Indexes.FilterIndex = []FilterIndex{{FieldName: "id", FieldType: String, IndexType: PRIMARY, AutoId: AutoIdUUID}}
+	_, err = coll.Upsert(context.Background(), []map[string]interface{}{{"vector": []float32{0.1}}})
+	if err != nil {
+		t.Errorf("expect upsert allowed by the AutoId of collection, got %v", err)
+	}
+}
+
+func TestSearchByTextWithoutEmbedding(t *testing.T) {
+	var calls int
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"code":0,"documents":[[{"id":"0001","score":0.9}]],"embeddingExtraInfo":{"tokenUsed":3}}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+	coll.Indexes.VectorIndex = []VectorIndex{{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW}, Dimension: 768}}
+
+	_, err := coll.SearchByText(context.Background(), map[string][]string{"text": {"a"}})
+	if err == nil || calls != 0 {
+		t.Fatalf("expect error without embedding, calls %d, err %v", calls, err)
+	}
+	coll.Embedding = Embedding{Field: "text", VectorField: "vector", Enabled: true}
+	res, err := coll.SearchByText(context.Background(), map[string][]string{"text": {"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Documents[0][0].Id != "0001" || res.EmbeddingExtraInfo.TokenUsed != 3 {
+		t.Errorf("unexpected search result %+v", res)
+	}
+}
+
+func TestUpsertCoerceFields(t *testing.T) {
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0,"affectedCount":1}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+	coll.Indexes.FilterIndex = []FilterIndex{{FieldName: "id", FieldType: String, IndexType: PRIMARY},
+		{FieldName: "page", FieldType: Uint64, IndexType: FILTER}, {FieldName: "author", FieldType: String, IndexType: FILTER}}
+
+	docs := []Document{{Id: "0001", Fields: map[string]Field{"page": {Val: float64(21)}, "author": {Val: "a"}, "note": {Val: 1.5}}}}
+	if _, err := coll.Upsert(context.Background(), docs); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, `"page":21`) || !strings.Contains(body, `"note":1.5`) {
+		t.Errorf("unexpected fields upserted %s", body)
+	}
+	coerced, _ := coerceDocumentFields(coll, docs)
+	if page := coerced.([]Document)[0].Fields["page"].Val; page != uint64(21) {
+		t.Errorf("expect page coerced to uint64, got %T", page)
+	}
+	if _, ok := docs[0].Fields["page"].Val.(float64); !ok {
+		t.Errorf("expect the documents of caller unchanged, got %v", docs[0].Fields)
+	}
+
+	for _, val := range []interface{}{-1, 1.5, "21", nil} {
+		body = ""
+		_, err := coll.Upsert(context.Background(), []map[string]interface{}{{"id": "0002", "page": val}})
+		if err == nil || !strings.Contains(err.Error(), "page") || !strings.Contains(err.Error(), "0002") || body != "" {
+			t.Errorf("expect page %v rejected before sending, got %v", val, err)
+		}
+	}
+	_, err := coll.Upsert(context.Background(), []Document{{Id: "0003", Fields: map[string]Field{"author": {Val: 3}}}})
+	if err == nil {
+		t.Errorf("expect number rejected for the string field")
+	}
+
+	coll.Indexes.FilterIndex = append(coll.Indexes.FilterIndex, FilterIndex{FieldName: "price", FieldType: Float64, IndexType: FILTER},
+		FilterIndex{FieldName: "delta", FieldType: Int64, IndexType: FILTER})
+	_, err = coll.Upsert(context.Background(), []Document{{Id: "0004", Fields: map[string]Field{
+		"price": {Val: json.Number("0.30000000000000004")}, "delta": {Val: -5}}}, {Id: "0005", Fields: map[string]Field{
+		"price": {Val: float32(0.1)}, "delta": {Val: uint64(7)}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, `"price":0.30000000000000004`) || !strings.Contains(body, `"price":0.1}`) ||
+		!strings.Contains(body, `"delta":-5`) || !strings.Contains(body, `"delta":7`) {
+		t.Errorf("unexpected float and int64 fields upserted %s", body)
+	}
+	for _, doc := range []map[string]interface{}{{"id": "0006", "price": "1.5"}, {"id": "0006", "delta": uint64(math.MaxUint64)}, {"id": "0006", "delta": 0.5}} {
+		if _, err = coll.Upsert(context.Background(), []map[string]interface{}{doc}); err == nil {
+			t.Errorf("expect %v rejected", doc)
+		}
+	}
+	if f := (Field{Val: "true"}); !f.Bool() || (Field{Val: json.Number("2.5")}).Float64() != 2.5 {
+		t.Errorf("unexpected Bool or Float64 of field")
+	}
+}
+
+func TestWriteWarning(t *testing.T) {
+	logger := new(recordLogger)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"affectedCount":1,"warning":"documents are not indexed"}`))
+	}
+	cli := newTestClient(t, handler, ClientOption{Logger: logger})
+	coll := cli.Database("db").Collection("coll")
+	docs := []Document{{Id: "0001"}, {Id: "0002"}}
+
+	res, err := coll.Upsert(context.Background(), docs, &UpsertDocumentParams{BatchSize: 1})
+	if err != nil || res.Warning != "documents are not indexed; documents are not indexed" {
+		t.Fatalf("expect warnings of both batches, got %+v, err %v", res, err)
+	}
+	del, err := coll.Delete(context.Background(), DeleteDocumentParams{DocumentIds: []string{"0001"}})
+	if err != nil || del.Warning != "documents are not indexed" {
+		t.Errorf("expect warning of delete, got %+v, err %v", del, err)
+	}
+	if logger.entries[0]["warning"] != "documents are not indexed" {
+		t.Errorf("expect warning logged, got %v", logger.entries[0])
+	}
+
+	strict := newTestClient(t, handler, ClientOption{StrictWarnings: true}).Database("db").Collection("coll")
+	var warnErr *WarningError
+	if _, err = strict.Upsert(context.Background(), docs); !errors.As(err, &warnErr) || warnErr.RequestPath != "/document/upsert" {
+		t.Errorf("expect WarningError with StrictWarnings, got %v", err)
+	}
+	if _, err = strict.Query(context.Background(), []string{"0001"}); err != nil {
+		t.Errorf("expect warning of read request ignored, got %v", err)
+	}
+}
+
+func TestSearchFilterOnly(t *testing.T) {
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0,"documents":[[{"id":"0001","page":3},{"id":"0002","page":5}]]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+	coll.Indexes.VectorIndex = []VectorIndex{{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW}, Dimension: 3}}
+
+	res, err := coll.Search(context.Background(), nil, &SearchDocumentParams{Filter: NewFilter(`page>2`), Limit: 2, OutputFields: []string{"page"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(body, "vectors") || !strings.Contains(body, `"filter":"page>2"`) {
+		t.Errorf("expect filter-only search without vectors, got %s", body)
+	}
+	if len(res.Documents) != 1 || len(res.Documents[0]) != 2 || res.Documents[0][1].Fields["page"].Uint64() != 5 {
+		t.Errorf("unexpected documents %+v", res.Documents)
+	}
+
+	body = ""
+	if _, err = coll.Search(context.Background(), nil, &SearchDocumentParams{Limit: 2}); err == nil || body != "" {
+		t.Errorf("expect search without vectors and filter rejected, got %v", err)
+	}
+}
+
+func TestNormalizedScore(t *testing.T) {
+	cases := []struct {
+		metric MetricType
+		score  float32
+		expect float64
+	}{
+		{L2, 0, 1},
+		{L2, 1, 0.5},
+		{HAMMING, 3, 0.25},
+		{COSINE, 1, 1},
+		{COSINE, 0, 0.5},
+		{IP, -1, 0},
+		{IP, 2, 1},
+	}
+	for _, c := range cases {
+		if got := (Document{Score: c.score}).NormalizedScore(c.metric); got != c.expect {
+			t.Errorf("expect %s score %v normalized to %v, got %v", c.metric, c.score, c.expect, got)
+		}
+	}
+	if (Document{Score: 0.2}).NormalizedScore(L2) <= (Document{Score: 0.8}).NormalizedScore(L2) {
+		t.Errorf("expect the closer L2 distance more similar")
+	}
+}
+
+func TestUpsertRequestTooLarge(t *testing.T) {
+	var sizes []int
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		sizes = append(sizes, len(b))
+		w.Write([]byte(`{"code":0,"affectedCount":1}`))
+	}, ClientOption{MaxRequestBytes: 2048})
+	coll := cli.Database("db").Collection("coll")
+	docs := make([]Document, 10)
+	for n := range docs {
+		docs[n] = Document{Id: strings.Repeat("x", 300)}
+	}
+
+	_, err := coll.Upsert(context.Background(), docs)
+	var tooLarge *RequestTooLargeError
+	if !errors.Is(err, ErrRequestTooLarge) || !errors.As(err, &tooLarge) || len(sizes) != 0 {
+		t.Fatalf("expect ErrRequestTooLarge before sending, got %v", err)
+	}
+	if tooLarge.Limit != 2048 || tooLarge.FitDocuments == 0 || tooLarge.FitDocuments >= len(docs) {
+		t.Errorf("unexpected error %+v", tooLarge)
+	}
+
+	res, err := coll.Upsert(context.Background(), docs, &UpsertDocumentParams{BatchSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes) < 2 || res.AffectedCount != len(sizes) {
+		t.Errorf("expect the batch split by bytes, got sizes %v", sizes)
+	}
+	for _, size := range sizes {
+		if size > 2048 {
+			t.Errorf("expect each request fit the limit, got sizes %v", sizes)
+		}
+	}
+}
+
+func TestBatchBounds(t *testing.T) {
+	if bounds := batchBounds(reflect.ValueOf([]Document{}), 10, 2048); len(bounds) != 2 || bounds[0] != 0 || bounds[1] != 0 {
+		t.Errorf("expect no batch of empty documents, got %v", bounds)
+	}
+	docs := []Document{{Id: "a"}, {Id: strings.Repeat("x", 4096)}, {Id: "b"}}
+	if bounds := batchBounds(reflect.ValueOf(docs), 10, 2048); fmt.Sprint(bounds) != "[0 1 2 3]" {
+		t.Errorf("expect the document larger than the limit in its own batch, got %v", bounds)
+	}
+	if bounds := batchBounds(reflect.ValueOf(docs[:1]), 10, 2048); fmt.Sprint(bounds) != "[0 1]" {
+		t.Errorf("expect a single small document in one batch, got %v", bounds)
+	}
+
+	small := []Document{{Id: "a", Vector: []float32{0.1, 0.2}, Fields: map[string]Field{"n": {Val: 1}}}}
+	if size, ok := estimateDocumentSize(small[0]); !ok || size < len(`{"id":"a","vector":[0.1,0.2],"n":1}`) {
+		t.Errorf("expect the upper bound of document size, got %d, %v", size, ok)
+	}
+	if _, ok := estimateDocumentSize(map[string]interface{}{"nested": map[string]interface{}{}}); ok {
+		t.Error("expect the nested field not estimated, which is measured by encoding")
+	}
+}
+
+func TestUpsertTtlField(t *testing.T) {
+	var upserts int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&upserts, 1)
+		w.Write([]byte(`{"code":0,"affectedCount":1}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+	coll.TtlConfig = &TtlConfig{Enable: true, TimeField: "expire_at"}
+
+	_, err := coll.Upsert(context.Background(), []Document{{Id: "0001", Fields: map[string]Field{"page": {Val: 1}}}})
+	if err == nil || !strings.Contains(err.Error(), "expire_at") || upserts != 0 {
+		t.Errorf("expect document without TTL field rejected before sending, got %v", err)
+	}
+	_, err = coll.Upsert(context.Background(), []map[string]interface{}{{"id": "0001", "expire_at": "tomorrow"}})
+	if err == nil || upserts != 0 {
+		t.Errorf("expect string TTL field rejected, got %v", err)
+	}
+	_, err = coll.Upsert(context.Background(), []Document{{Id: "0001"}}, &UpsertDocumentParams{SkipTtlCheck: true})
+	if err != nil || upserts != 1 {
+		t.Errorf("expect TTL check skipped, got %v", err)
+	}
+
+	doc := Document{Id: "0001", Fields: map[string]Field{"expire_at": {Val: json.Number("1704164645")}}}
+	if _, err = coll.Upsert(context.Background(), []Document{doc}); err != nil {
+		t.Fatal(err)
+	}
+	if expiresAt, ok := doc.ExpiresAt(coll.TtlConfig); !ok || expiresAt.Unix() != 1704164645 {
+		t.Errorf("unexpected ExpiresAt %v, %v", expiresAt, ok)
+	}
+	if _, ok := doc.ExpiresAt(nil); ok {
+		t.Error("expect no expiration without TTL")
+	}
+}
+
+func TestSearchByIdMissing(t *testing.T) {
+	existing := map[string]bool{"0001": true, "0003": true}
+	failMissing := false
+	var searches int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/document/query" {
+			req := new(document.QueryReq)
+			json.Unmarshal(body, req)
+			var docs []string
+			for _, id := range req.Query.DocumentIds {
+				if existing[id] {
+					docs = append(docs, `{"id":"`+id+`"}`)
+				}
+			}
+			w.Write([]byte(`{"code":0,"documents":[` + strings.Join(docs, ",") + `]}`))
+			return
+		}
+		atomic.AddInt32(&searches, 1)
+		req := new(document.SearchReq)
+		json.Unmarshal(body, req)
+		var groups []string
+		for _, id := range req.Search.DocumentIds {
+			if !existing[id] {
+				if failMissing {
+					w.Write([]byte(`{"code":15000,"msg":"document ` + id + ` not exist"}`))
+					return
+				}
+				continue
+			}
+			groups = append(groups, `[{"id":"`+id+`","score":1}]`)
+		}
+		w.Write([]byte(`{"code":0,"warning":"some ids not exist","documents":[` + strings.Join(groups, ",") + `]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	for _, fail := range []bool{false, true} {
+		failMissing = fail
+		res, err := coll.SearchById(context.Background(), []string{"0001", "0002", "0003"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Documents) != 3 || res.Documents[1] != nil || res.Documents[2][0].Id != "0003" ||
+			!errors.Is(res.Errors[1], ErrDocumentNotExist) || res.Errors[0] != nil ||
+			len(res.MissingIds) != 1 || res.MissingIds[0] != "0002" {
+			t.Errorf("expect results aligned with ids when server fails %v, got %+v", fail, res)
+		}
+	}
+
+	_, err := coll.SearchById(context.Background(), []string{"0001", "0002"}, &SearchDocumentParams{FailOnMissing: true})
+	if !errors.Is(err, ErrDocumentNotExist) || !strings.Contains(err.Error(), "0002") {
+		t.Errorf("expect error of the missing id, got %v", err)
+	}
+	searches = 0
+	res, err := coll.SearchById(context.Background(), []string{"0001", "0003"})
+	if err != nil || len(res.Documents) != 2 || res.Errors != nil || searches != 1 {
+		t.Errorf("expect one search without missing ids, got %+v, %v", res, err)
+	}
+}
+
+func TestUpsertDiagnoseOnFailure(t *testing.T) {
+	var requests int32
+	upserted := make(map[string]int)
+	var mu sync.Mutex
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		req := new(document.UpsertReq)
+		json.NewDecoder(r.Body).Decode(req)
+		for _, doc := range req.Documents {
+			if _, ok := doc.Fields["page"].(string); ok {
+				w.Write([]byte(`{"code":14000,"msg":"field type mismatch"}`))
+				return
+			}
+		}
+		mu.Lock()
+		for _, doc := range req.Documents {
+			upserted[doc.Id]++
+		}
+		mu.Unlock()
+		w.Write([]byte(fmt.Sprintf(`{"code":0,"affectedCount":%d}`, len(req.Documents))))
+	}, ClientOption{})
+
+	docs := make([]map[string]interface{}, 8)
+	for i := range docs {
+		docs[i] = map[string]interface{}{"id": fmt.Sprintf("%04d", i), "page": i}
+	}
+	docs[5]["page"] = "five"
+	_, err := cli.Upsert(context.Background(), "db", "coll", docs)
+	if err == nil || len(upserted) != 0 {
+		t.Fatalf("expect the whole upsert rejected without diagnosing, got %v", err)
+	}
+
+	requests = 0
+	res, err := cli.Upsert(context.Background(), "db", "coll", docs, &UpsertDocumentParams{DiagnoseOnFailure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.FailedDocuments) != 1 || res.FailedDocuments[0].Index != 5 || res.FailedDocuments[0].Id != "0005" ||
+		!strings.Contains(res.FailedDocuments[0].Reason, "mismatch") || res.AffectedCount != 7 {
+		t.Errorf("unexpected diagnosed result %+v", res)
+	}
+	if len(upserted) != 7 || requests != 7 {
+		t.Errorf("expect each accepted document upserted once in 7 requests, got %v in %d", upserted, requests)
+	}
+	for id, n := range upserted {
+		if n != 1 {
+			t.Errorf("document %s upserted %d times", id, n)
+		}
+	}
+
+	upserted = make(map[string]int)
+	res, err = cli.Upsert(context.Background(), "db", "coll", docs, &UpsertDocumentParams{DiagnoseOnFailure: true, BatchSize: 4})
+	if err != nil || len(res.FailedDocuments) != 1 || res.FailedDocuments[0].Index != 5 || len(upserted) != 7 {
+		t.Errorf("expect the failed document of batch at its index of all documents, got %+v, %v", res, err)
+	}
+}
+
+func TestUpsertDiagnoseBatchError(t *testing.T) {
+	var requests int32
+	status, body := http.StatusUnauthorized, `{"code":1,"msg":"unauthorized"}`
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}, ClientOption{})
+	docs := make([]Document, 8)
+	for i := range docs {
+		docs[i] = Document{Id: fmt.Sprintf("%04d", i), Vector: []float32{0.1}}
+	}
+
+	_, err := cli.Upsert(context.Background(), "db", "coll", docs, &UpsertDocumentParams{DiagnoseOnFailure: true})
+	if !errors.Is(err, ErrUnauthorized) || requests != 1 {
+		t.Errorf("expect the auth error returned without diagnosing, got %v in %d requests", err, requests)
+	}
+
+	requests, status, body = 0, http.StatusOK, `{"code":14000,"msg":"shard is read only"}`
+	_, err = cli.Upsert(context.Background(), "db", "coll", docs, &UpsertDocumentParams{DiagnoseOnFailure: true})
+	if err == nil || !strings.Contains(err.Error(), "read only") || requests != 3 {
+		t.Errorf("expect the error of both halves returned, got %v in %d requests", err, requests)
+	}
+}
+
+func TestRawResponse(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/document/query":
+			w.Write([]byte(`{"code":0,"count":1,"documents":[{"id":"0001","page":1,"_explain":{"shard":2}}],"timing":{"total":3}}`))
+		case "/document/search":
+			w.Write([]byte(`{"code":0,"documents":[[{"id":"0001","score":0.9,"_explain":{"shard":1}},{"id":"0002","score":0.8}]]}`))
+		}
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	res, err := coll.Query(context.Background(), []string{"0001"})
+	if err != nil || res.RawResponse != nil || res.Documents[0].Raw != nil {
+		t.Fatalf("expect no raw response by default, got %+v, %v", res, err)
+	}
+	res, err = coll.Query(context.Background(), []string{"0001"}, &QueryDocumentParams{RawResponse: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var extra struct {
+		Timing struct{ Total int }
+	}
+	if json.Unmarshal(res.RawResponse, &extra) != nil || extra.Timing.Total != 3 {
+		t.Errorf("unexpected raw response %s", res.RawResponse)
+	}
+	if !strings.Contains(string(res.Documents[0].Raw), `"_explain":{"shard":2}`) || res.Documents[0].Fields["page"].Uint64() != 1 {
+		t.Errorf("unexpected raw document %s", res.Documents[0].Raw)
+	}
+
+	search, err := coll.Search(context.Background(), [][]float32{{0.1, 0.2}}, &SearchDocumentParams{RawResponse: true, SkipDimensionCheck: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := search.Documents[0]
+	if len(search.RawResponse) == 0 || !strings.Contains(string(docs[0].Raw), `"_explain"`) || string(docs[1].Raw) != `{"id":"0002","score":0.8}` {
+		t.Errorf("unexpected raw search result %s, %s, %s", search.RawResponse, docs[0].Raw, docs[1].Raw)
+	}
+}
+
+func TestSearchGroupBy(t *testing.T) {
+	var req document.SearchReq
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"code":0,"documents":[[{"id":"1","parent":"a"},{"id":"2","parent":"a"},{"id":"3","parent":"a"},
+			{"id":"4","parent":"b"},{"id":"5"},{"id":"6","parent":"c"}]]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	res, err := coll.Search(context.Background(), [][]float32{{0.1}}, &SearchDocumentParams{Limit: 4, OutputFields: []string{"id"},
+		GroupByField: "parent", GroupSize: 2, SkipDimensionCheck: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Search.Limit != 12 || strings.Join(req.Search.OutputFields, ",") != "id,parent" {
+		t.Errorf("expect over fetched search with the group field, got %+v", req.Search)
+	}
+	var ids []string
+	for _, doc := range res.Documents[0] {
+		ids = append(ids, doc.Id)
+	}
+	groups := res.Groups[0]
+	if strings.Join(ids, ",") != "1,2,4,5" || len(groups) != 3 || groups[0].Key != "a" || len(groups[0].Documents) != 2 ||
+		groups[2].Key != "" || groups[2].Documents[0].Id != "5" {
+		t.Errorf("unexpected grouped result %v, %+v", ids, groups)
+	}
+}
+
+func TestSearchRadius(t *testing.T) {
+	var req document.SearchReq
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"code":0,"documents":[[{"id":"1","score":0.9},{"id":"2","score":0.5},{"id":"3","score":0.1}]]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+	vectors := [][]float32{{0.1, 0.2}}
+
+	res, err := coll.Search(context.Background(), vectors, &SearchDocumentParams{Radius: 0.5, SkipDimensionCheck: true})
+	if err != nil || req.Search.Params == nil || req.Search.Params.Radius != 0.5 || len(res.Documents[0]) != 3 || res.RadiusFilteredByClient {
+		t.Fatalf("expect radius sent without client filtering for the unknown metric, got %+v, %+v, %v", req.Search.Params, res, err)
+	}
+
+	for metric, expect := range map[MetricType]string{COSINE: "1,2", L2: "2,3"} {
+		coll.Indexes.VectorIndex = []VectorIndex{{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW},
+			Dimension: 2, MetricType: metric}}
+		res, err = coll.Search(context.Background(), vectors, &SearchDocumentParams{Radius: 0.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, doc := range res.Documents[0] {
+			ids = append(ids, doc.Id)
+		}
+		if strings.Join(ids, ",") != expect || !res.RadiusFilteredByClient {
+			t.Errorf("expect %s within radius of %s, got %v", expect, metric, ids)
+		}
+	}
+
+	req = document.SearchReq{}
+	res, err = coll.Search(context.Background(), vectors)
+	if err != nil || req.Search.Params != nil || len(res.Documents[0]) != 3 {
+		t.Errorf("expect no threshold by default, got %+v, %v", req.Search.Params, err)
+	}
+}
+
+func TestVectorValues(t *testing.T) {
+	var bodies []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"code":0,"documents":[[]]}`))
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+	nan := float32(math.NaN())
+	inf := float32(math.Inf(1))
+	ctx := context.Background()
+
+	docs := []Document{{Id: "a", Vector: []float32{0.1, 0.2}}, {Id: "b", Vector: []float32{0.1, nan}}}
+	_, err := coll.Upsert(ctx, docs)
+	if err == nil || !strings.Contains(err.Error(), "document b: vector component 1 is NaN") {
+		t.Errorf("expect NaN rejected, got %v", err)
+	}
+	_, err = coll.Upsert(ctx, []map[string]interface{}{{"id": "c", "vector": []float32{inf, 0.2}}})
+	if err == nil || !strings.Contains(err.Error(), "document c: vector component 0 is +Inf") {
+		t.Errorf("expect Inf rejected, got %v", err)
+	}
+	_, err = coll.Upsert(ctx, []Document{{Id: "d", Vector: []float32{}}})
+	if err == nil || !strings.Contains(err.Error(), "document d: vector is empty") {
+		t.Errorf("expect empty vector rejected, got %v", err)
+	}
+	vectors := [][]float32{{0.1, 0.2}, {0.3, inf}}
+	_, err = coll.Search(ctx, vectors)
+	if err == nil || !strings.Contains(err.Error(), "vectors[1]: vector component 1 is +Inf") {
+		t.Errorf("expect Inf rejected, got %v", err)
+	}
+	if len(bodies) != 0 {
+		t.Fatalf("expect no request sent, got %v", bodies)
+	}
+
+	_, err = coll.Upsert(ctx, docs, &UpsertDocumentParams{SanitizeVectors: true})
+	if err != nil || !strings.Contains(bodies[0], `"id":"b","vector":[0.1,0]`) || !math.IsNaN(float64(docs[1].Vector[1])) {
+		t.Errorf("expect NaN sanitized in a copy, got %v, %v", bodies, err)
+	}
+	_, err = coll.Search(ctx, vectors, &SearchDocumentParams{SanitizeVectors: true})
+	if err != nil || !strings.Contains(bodies[1], `"vectors":[[0.1,0.2],[0.3,0]]`) || !math.IsInf(float64(vectors[1][1]), 1) {
+		t.Errorf("expect Inf sanitized in a copy, got %v, %v", bodies, err)
+	}
+}
+
+func TestAutoNormalize(t *testing.T) {
+	var bodies []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"code":0,"documents":[[]]}`))
+	}, ClientOption{AutoNormalize: true})
+	ctx := context.Background()
+
+	for _, metric := range []MetricType{COSINE, L2, IP} {
+		bodies = nil
+		coll := cli.Database("db").Collection("coll")
+		coll.Indexes.VectorIndex = []VectorIndex{{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW},
+			Dimension: 2, MetricType: metric}}
+		docs := []Document{{Id: "a", Vector: []float32{3, 4}}, {Id: "b", Vector: []float32{0, 0}}}
+		_, upsertErr := coll.Upsert(ctx, docs)
+		vectors := [][]float32{{0, 0}, {0, 2}}
+		_, searchErr := coll.Search(ctx, vectors)
+		if docs[0].Vector[0] != 3 || vectors[1][1] != 2 {
+			t.Errorf("%s: expect the vectors passed in not modified", metric)
+		}
+		if metric != COSINE {
+			if upsertErr != nil || searchErr != nil || !strings.Contains(bodies[0], `"vector":[3,4]`) ||
+				!strings.Contains(bodies[1], `"vectors":[[0,0],[0,2]]`) {
+				t.Errorf("%s: expect the vectors sent unchanged, got %v, %v, %v", metric, bodies, upsertErr, searchErr)
+			}
+			continue
+		}
+		var zeros *ZeroVectorError
+		if !errors.As(upsertErr, &zeros) || len(zeros.DocumentIds) != 1 || zeros.DocumentIds[0] != "b" {
+			t.Errorf("expect the zero vector of document b reported, got %v", upsertErr)
+		}
+		if !errors.As(searchErr, &zeros) || len(zeros.Vectors) != 1 || zeros.Vectors[0] != 0 {
+			t.Errorf("expect the zero search vector reported, got %v", searchErr)
+		}
+		if !strings.Contains(bodies[0], `"id":"a","vector":[0.6,0.8]`) || !strings.Contains(bodies[0], `"id":"b","vector":[0,0]`) ||
+			!strings.Contains(bodies[1], `"vectors":[[0,0],[0,1]]`) {
+			t.Errorf("expect the vectors normalized, got %v", bodies)
+		}
+	}
+
+	bodies = nil
+	coll := cli.Database("db").Collection("coll")
+	coll.Indexes.VectorIndex = []VectorIndex{{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW},
+		Dimension: 2, MetricType: COSINE}}
+	nan := float32(math.NaN())
+	if _, err := coll.Upsert(ctx, []Document{{Id: "a", Vector: []float32{nan, 4}}}); err == nil || !strings.Contains(err.Error(), "NaN") {
+		t.Errorf("expect the NaN rejected before normalizing, got %v", err)
+	}
+	_, err := coll.Upsert(ctx, []Document{{Id: "a", Vector: []float32{nan, 4}}}, &UpsertDocumentParams{SanitizeVectors: true})
+	if err != nil || !strings.Contains(bodies[0], `"vector":[0,1]`) {
+		t.Errorf("expect the NaN sanitized before normalizing, got %v, %v", bodies, err)
+	}
+	if _, err = coll.Search(ctx, [][]float32{{3, nan}}); err == nil || !strings.Contains(err.Error(), "NaN") {
+		t.Errorf("expect the NaN search vector rejected before normalizing, got %v", err)
+	}
+}
+
+func TestDocumentExists(t *testing.T) {
+	var queries []document.QueryReq
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req document.QueryReq
+		json.NewDecoder(r.Body).Decode(&req)
+		queries = append(queries, req)
+		res := document.QueryRes{}
+		for _, id := range req.Query.DocumentIds {
+			if id != "missing" {
+				res.Documents = append(res.Documents, &document.Document{Id: id})
+			}
+		}
+		json.NewEncoder(w).Encode(res)
+	}, ClientOption{})
+	coll := cli.Database("db").Collection("coll")
+
+	exists, err := coll.Exists(context.Background(), []string{"a", "missing", "b", "a", "c"},
+		&ExistsDocumentParams{BatchSize: 2, ReadConsistency: StrongConsistency})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exists) != 4 || !exists["a"] || !exists["b"] || !exists["c"] || exists["missing"] {
+		t.Errorf("unexpected exists %v", exists)
+	}
+	if len(queries) != 2 || len(queries[0].Query.DocumentIds) != 2 || queries[1].Query.DocumentIds[1] != "c" {
+		t.Fatalf("expect the unique ids queried in 2 batches, got %+v", queries)
+	}
+	for _, q := range queries {
+		if q.ReadConsistency != string(StrongConsistency) || len(q.Query.OutputFields) != 1 || q.Query.OutputFields[0] != "id" ||
+			q.Query.RetrieveVector {
+			t.Errorf("expect only the ids queried with strong consistency, got %+v", q.Query)
+		}
+	}
+}
+
+func TestUpsertFieldMask(t *testing.T) {
+	for _, version := range []string{"v1.1.0", "v1.4.0"} {
+		var (
+			mu     sync.Mutex
+			paths  []string
+			bodies = make(map[string]map[string]interface{})
+		)
+		cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			paths = append(paths, r.URL.Path)
+			bodies[r.URL.Path] = body
+			mu.Unlock()
+			switch r.URL.Path {
+			case "/server/info":
+				fmt.Fprintf(w, `{"code":0,"info":{"version":%q}}`, version)
+			case "/document/query":
+				w.Write([]byte(`{"code":0,"documents":[{"id":"doc1","vector":[0.6,0.8],"owner":"a","price":1}]}`))
+			case "/document/upsert":
+				fmt.Fprintf(w, `{"code":0,"affectedCount":%d}`, len(body["documents"].([]interface{})))
+			default:
+				w.Write([]byte(`{"code":0,"affectedCount":1}`))
+			}
+		}, ClientOption{})
+		coll := cli.Database("db").Collection("coll")
+
+		docs := []Document{
+			{Id: "doc1", Fields: map[string]Field{"price": {Val: 2}, "owner": {Val: "b"}}},
+			{Id: "doc2", Vector: []float32{1, 0}, Fields: map[string]Field{"price": {Val: 3}}},
+		}
+		res, err := coll.Upsert(context.Background(), docs, &UpsertDocumentParams{FieldMask: []string{"price"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.AffectedCount != 2 {
+			t.Errorf("%s: expect 2 affected, got %d", version, res.AffectedCount)
+		}
+		query := bodies["/document/query"]["query"].(map[string]interface{})
+		if bodies["/document/query"]["readConsistency"] != string(StrongConsistency) || len(query["documentIds"].([]interface{})) != 2 {
+			t.Errorf("%s: expect the documents queried with strong consistency, got %v", version, bodies["/document/query"])
+		}
+		upserted := bodies["/document/upsert"]["documents"].([]interface{})
+		if version == "v1.4.0" {
+			if paths[len(paths)-2] != "/document/update" || len(upserted) != 1 || upserted[0].(map[string]interface{})["id"] != "doc2" {
+				t.Fatalf("%s: expect doc1 updated and doc2 upserted, got %v %v", version, paths, upserted)
+			}
+			update := bodies["/document/update"]["update"].(map[string]interface{})
+			if update["price"] != float64(2) || update["owner"] != nil {
+				t.Errorf("%s: expect only the price updated, got %v", version, update)
+			}
+			continue
+		}
+		if len(upserted) != 2 {
+			t.Fatalf("%s: expect the merged documents upserted, got %v", version, upserted)
+		}
+		merged := upserted[0].(map[string]interface{})
+		if merged["price"] != float64(2) || merged["owner"] != "a" || len(merged["vector"].([]interface{})) != 2 {
+			t.Errorf("%s: expect the price written over doc1, got %v", version, merged)
+		}
+		if upserted[1].(map[string]interface{})["id"] != "doc2" {
+			t.Errorf("%s: expect doc2 inserted as it is, got %v", version, upserted[1])
+		}
+	}
+
+	var updates int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/server/info":
+			w.Write([]byte(`{"code":0,"info":{"version":"v1.4.0"}}`))
+		case "/document/query":
+			w.Write([]byte(`{"code":0,"documents":[{"id":"doc1"},{"id":"doc2"},{"id":"doc3"}]}`))
+		case "/document/update":
+			atomic.AddInt32(&updates, 1)
+			w.Write([]byte(`{"code":0,"affectedCount":1}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}, ClientOption{})
+	docs := []Document{
+		{Id: "doc1", Fields: map[string]Field{"price": {Val: 2}}},
+		{Id: "doc2", Fields: map[string]Field{"owner": {Val: "b"}}},
+		{Id: "doc3", Fields: map[string]Field{"price": {Val: 3}}},
+	}
+	res, err := cli.Database("db").Collection("coll").Upsert(context.Background(), docs,
+		&UpsertDocumentParams{FieldMask: []string{"price"}, BatchConcurrency: 2})
+	if err != nil || res.AffectedCount != 2 || atomic.LoadInt32(&updates) != 2 {
+		t.Errorf("expect doc2 without the masked field skipped, got %+v, %v, %d updates", res, err, updates)
+	}
+
+	coll := cli.Database("db").Collection("coll")
+	mask := &UpsertDocumentParams{FieldMask: []string{"vector", "sparse_vector", "price"}}
+	_, err = coll.Upsert(context.Background(), []map[string]interface{}{{"id": "doc1", "vector": []float64{0.1, 0.2}, "price": 1}}, mask)
+	if err == nil || !strings.Contains(err.Error(), "incorrect vector field type") {
+		t.Errorf("expect the []float64 vector rejected instead of dropped, got %v", err)
+	}
+	_, err = coll.Upsert(context.Background(), []map[string]interface{}{{"id": "doc1", "sparse_vector": [][]interface{}{{"a", 0.1}}, "price": 1}}, mask)
+	if err == nil || !strings.Contains(err.Error(), "sparse_vector") {
+		t.Errorf("expect the incorrect sparse_vector rejected instead of dropped, got %v", err)
+	}
+	_, err = coll.Upsert(context.Background(), []Document{{Id: "doc1", Vector: []float32{float32(math.NaN()), 1}}}, mask)
+	if err == nil || !strings.Contains(err.Error(), "NaN") {
+		t.Errorf("expect the NaN vector rejected before the update, got %v", err)
+	}
+	if n := atomic.LoadInt32(&updates); n != 2 {
+		t.Errorf("expect no update sent of the rejected documents, got %d updates", n)
+	}
+}
+
+func TestStrictOutputFields(t *testing.T) {
+	var bodies []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{StrictOutputFields: true})
+	ctx := context.Background()
+
+	coll := cli.Database("db").Collection("coll")
+	coll.Indexes.VectorIndex = []VectorIndex{{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW},
+		Dimension: 2, MetricType: COSINE}}
+	coll.Indexes.FilterIndex = []FilterIndex{{FieldName: "id", FieldType: String, IndexType: PRIMARY},
+		{FieldName: "author", FieldType: String, IndexType: FILTER}}
+
+	_, err := coll.Query(ctx, []string{"a"}, &QueryDocumentParams{OutputFields: []string{"id", "auther"}})
+	var unknown *UnknownOutputFieldError
+	if !errors.Is(err, ErrUnknownOutputField) || !errors.As(err, &unknown) || unknown.Field != "auther" ||
+		len(unknown.Suggestions) != 1 || unknown.Suggestions[0] != "author" || len(unknown.Recognized) != 1 {
+		t.Fatalf("expect the misspelled field rejected, got %v", err)
+	}
+	if _, err = coll.Search(ctx, [][]float32{{1, 0}}, &SearchDocumentParams{OutputFields: []string{"price"}}); !errors.Is(err, ErrUnknownOutputField) {
+		t.Errorf("expect the unknown field rejected, got %v", err)
+	}
+	if len(bodies) != 0 {
+		t.Fatalf("expect no request sent, got %v", bodies)
+	}
+
+	if _, err = coll.Query(ctx, []string{"a"}, &QueryDocumentParams{OutputFields: []string{"id", "author", "id"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(bodies[0], `"outputFields":["id","author"]`) {
+		t.Errorf("expect the output fields de-duplicated, got %s", bodies[0])
+	}
+	// the fields are not checked without the schema
+	if _, err = cli.Database("db").Collection("coll").Query(ctx, []string{"a"}, &QueryDocumentParams{OutputFields: []string{"price"}}); err != nil {
+		t.Errorf("expect the fields not checked without schema, got %v", err)
+	}
+}
+
+func TestDeleteInIdBatches(t *testing.T) {
+	var (
+		reqs   []document.DeleteReq
+		failAt int
+	)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req document.DeleteReq
+		json.NewDecoder(r.Body).Decode(&req)
+		reqs = append(reqs, req)
+		if len(reqs) == failAt {
+			w.Write([]byte(`{"code":1,"msg":"server error"}`))
+			return
+		}
+		n := len(req.Query.DocumentIds)
+		if req.Query.Limit > 0 && int64(n) > req.Query.Limit {
+			n = int(req.Query.Limit)
+		}
+		fmt.Fprintf(w, `{"code":0,"affectedCount":%d}`, n)
+	}, ClientOption{})
+	ctx := context.Background()
+	coll := cli.Database("db").Collection("coll")
+
+	ids := make([]string, 2500)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%04d", i)
+	}
+	res, err := coll.Delete(ctx, DeleteDocumentParams{DocumentIds: ids})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AffectedCount != 2500 || len(reqs) != 3 || len(reqs[2].Query.DocumentIds) != 500 ||
+		reqs[1].Query.DocumentIds[0] != "1000" || reqs[2].Query.DocumentIds[0] != "2000" {
+		t.Fatalf("expect 3 batches in order, got AffectedCount %d, %d requests", res.AffectedCount, len(reqs))
+	}
+
+	reqs, failAt = nil, 2
+	res, err = coll.Delete(ctx, DeleteDocumentParams{DocumentIds: ids})
+	var batchErr *IdBatchError
+	if !errors.As(err, &batchErr) || batchErr.Batch != 1 || batchErr.Offset != 1000 || batchErr.Processed != 1000 {
+		t.Fatalf("expect the second batch failed after 1000 ids, got %v", err)
+	}
+	if res.AffectedCount != 1000 || len(reqs) != 2 {
+		t.Errorf("expect stop after the failed batch, AffectedCount %d, %d requests", res.AffectedCount, len(reqs))
+	}
+
+	reqs, failAt = nil, 2
+	res, err = coll.Delete(ctx, DeleteDocumentParams{DocumentIds: ids, ContinueOnError: true})
+	var batchErrs *IdBatchErrors
+	if !errors.As(err, &batchErrs) || batchErrs.Processed != 1500 || len(batchErrs.Failures) != 1 || batchErrs.Failures[0].Offset != 1000 {
+		t.Fatalf("expect the second batch listed as failed, got %v", err)
+	}
+	if res.AffectedCount != 1500 || len(reqs) != 3 {
+		t.Errorf("expect the remaining batches sent, AffectedCount %d, %d requests", res.AffectedCount, len(reqs))
+	}
+
+	reqs, failAt = nil, 0
+	res, err = coll.Delete(ctx, DeleteDocumentParams{DocumentIds: ids, Limit: 1200, BatchConcurrency: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AffectedCount != 1200 || len(reqs) != 2 || reqs[1].Query.Limit != 200 {
+		t.Errorf("expect Limit shared by the batches, AffectedCount %d, %d requests", res.AffectedCount, len(reqs))
+	}
+
+	reqs = nil
+	if _, err = coll.Update(ctx, UpdateDocumentParams{QueryIds: ids, BatchSize: 2000,
+		UpdateFields: map[string]interface{}{"author": "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 2 {
+		t.Errorf("expect Update sent in 2 batches, got %d requests", len(reqs))
+	}
+}
@@ -0,0 +1,486 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBufferedWriterMaxDocs       = 500
+	defaultBufferedWriterMaxBytes      = 4 << 20
+	defaultBufferedWriterFlushInterval = time.Second
+)
+
+var errBufferedWriterClosed = errors.New("buffered writer is closed")
+
+type BufferedWriterOption struct {
+	// MaxDocs: flush the buffer when it holds MaxDocs documents, default 500
+	MaxDocs int
+	// MaxBytes: flush the buffer when the json size of its documents reaches MaxBytes, default 4MB
+	MaxBytes int
+	// FlushInterval: flush the buffer periodically, default 1s
+	FlushInterval time.Duration
+	// Concurrency: the number of batches upserted at the same time, default 1
+	Concurrency int
+	// UpsertParams: the params of each Upsert
+	UpsertParams *UpsertDocumentParams
+	// OnError: called with the failed batch when an Upsert fails, so the documents could be dead-lettered.
+	// It is called by the background goroutines, and errors are dropped if it is nil.
+	OnError func(err *BufferedWriteError)
+}
+
+// BufferedWriteError is passed to BufferedWriterOption.OnError when a batch fails to upsert.
+type BufferedWriteError struct {
+	Documents []Document
+	Err       error
+}
+
+func (e *BufferedWriteError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BufferedWriteError) Unwrap() error {
+	return e.Err
+}
+
+// BufferedWriterResult the documents upserted by BufferedWriter so far.
+type BufferedWriterResult struct {
+	// Upserted: the number of documents of the succeeded batches
+	Upserted int
+	// AffectedCount: the sum of AffectedCount of the succeeded batches, less than Upserted
+	// if the server does not write some documents
+	AffectedCount int
+	// Failed: the number of documents of the failed batches
+	Failed int
+	// Warnings: the warnings of server
+	Warnings []string
+}
+
+// BufferedWriter buffers the added documents and upserts them in batches by background goroutines.
+// The order of documents within a batch is preserved, but batches could be upserted in any order.
+type BufferedWriter struct {
+	option BufferedWriterOption
+	upsert upsertBatchFunc
+
+	resultMu sync.Mutex
+	result   BufferedWriterResult
+
+	mu       sync.Mutex
+	buf      []Document
+	bufBytes int
+	closed   bool
+
+	batches chan []Document
+	workers sync.WaitGroup
+	stop    chan struct{}
+
+	// pending counts the batches sent to the workers and not done yet
+	pendingMu sync.Mutex
+	pending   int
+	idle      *sync.Cond
+}
+
+// NewBufferedWriter returns a BufferedWriter upserting documents into the collection.
+// Close must be called to flush the buffered documents and stop the background goroutines.
+func (c *Collection) NewBufferedWriter(option BufferedWriterOption) *BufferedWriter {
+	return newBufferedWriter(option, func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error) {
+		var params []*UpsertDocumentParams
+		if option.UpsertParams != nil {
+			params = append(params, option.UpsertParams)
+		}
+		return c.Upsert(ctx, documents, params...)
+	})
+}
+
+// upsertBatchFunc upserts a batch of BufferedWriter and UpsertStream.
+type upsertBatchFunc func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error)
+
+func newBufferedWriter(option BufferedWriterOption, upsert upsertBatchFunc) *BufferedWriter {
+	if option.MaxDocs <= 0 {
+		option.MaxDocs = defaultBufferedWriterMaxDocs
+	}
+	if option.MaxBytes <= 0 {
+		option.MaxBytes = defaultBufferedWriterMaxBytes
+	}
+	if option.FlushInterval <= 0 {
+		option.FlushInterval = defaultBufferedWriterFlushInterval
+	}
+	if option.Concurrency <= 0 {
+		option.Concurrency = 1
+	}
+	w := &BufferedWriter{
+		option:  option,
+		upsert:  upsert,
+		batches: make(chan []Document, option.Concurrency),
+		stop:    make(chan struct{}),
+	}
+	w.idle = sync.NewCond(&w.pendingMu)
+	w.workers.Add(option.Concurrency + 1)
+	for i := 0; i < option.Concurrency; i++ {
+		go w.work()
+	}
+	go w.tick()
+	return w
+}
+
+// Add add a document into the buffer, and flush the buffer if it is full.
+// It blocks when all the background goroutines are busy, until the ctx is done.
+func (w *BufferedWriter) Add(ctx context.Context, doc Document) error {
+	size := 0
+	if data, err := json.Marshal(doc); err == nil {
+		size = len(data)
+	}
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return errBufferedWriterClosed
+	}
+	var batches []writerBatch
+	if len(w.buf) != 0 && w.bufBytes+size > w.option.MaxBytes {
+		batches = append(batches, w.takeLocked())
+	}
+	w.buf = append(w.buf, doc)
+	w.bufBytes += size
+	if len(w.buf) >= w.option.MaxDocs || w.bufBytes >= w.option.MaxBytes {
+		batches = append(batches, w.takeLocked())
+	}
+	w.mu.Unlock()
+	return w.send(ctx, batches...)
+}
+
+// Flush upsert the buffered documents and wait for all the pending batches to be done.
+// The failed batches are reported by OnError rather than the returned error.
+func (w *BufferedWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return errBufferedWriterClosed
+	}
+	batch := w.takeLocked()
+	w.mu.Unlock()
+	if err := w.send(ctx, batch); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.waitPending()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flush the buffered documents, wait for them to be upserted and stop the background goroutines.
+func (w *BufferedWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.stop)
+	batch := w.takeLocked()
+	w.mu.Unlock()
+	err := w.send(context.Background(), batch)
+
+	w.waitPending()
+	close(w.batches)
+	w.workers.Wait()
+	return err
+}
+
+// writerBatch is the buffered documents taken to be sent to the workers.
+type writerBatch struct {
+	docs  []Document
+	bytes int
+}
+
+// takeLocked takes the buffered documents as a batch, which is pending until the workers upsert it.
+func (w *BufferedWriter) takeLocked() writerBatch {
+	batch := writerBatch{docs: w.buf, bytes: w.bufBytes}
+	if len(batch.docs) != 0 {
+		w.addPending(1)
+	}
+	w.buf = nil
+	w.bufBytes = 0
+	return batch
+}
+
+// send passes the batches taken by takeLocked to the workers in order, without holding w.mu so the slow
+// workers don't block the other calls. If the ctx is done, the batches not sent are put back in front of
+// the buffer, or still sent if the writer is closed meanwhile, since nothing would flush the buffer.
+func (w *BufferedWriter) send(ctx context.Context, batches ...writerBatch) error {
+	for i, batch := range batches {
+		if len(batch.docs) == 0 {
+			continue
+		}
+		select {
+		case w.batches <- batch.docs:
+			continue
+		case <-ctx.Done():
+		}
+
+		w.mu.Lock()
+		if w.closed {
+			w.mu.Unlock()
+			for _, b := range batches[i:] {
+				if len(b.docs) != 0 {
+					w.batches <- b.docs
+				}
+			}
+			return nil
+		}
+		var buf []Document
+		for _, b := range batches[i:] {
+			if len(b.docs) != 0 {
+				buf = append(buf, b.docs...)
+				w.bufBytes += b.bytes
+				w.addPending(-1)
+			}
+		}
+		w.buf = append(buf, w.buf...)
+		w.mu.Unlock()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (w *BufferedWriter) work() {
+	defer w.workers.Done()
+	for batch := range w.batches {
+		res, err := w.upsert(context.Background(), batch)
+		w.resultMu.Lock()
+		if err != nil {
+			w.result.Failed += len(batch)
+		} else {
+			w.result.Upserted += len(batch)
+			if res != nil {
+				w.result.AffectedCount += res.AffectedCount
+				if res.Warning != "" {
+					w.result.Warnings = append(w.result.Warnings, res.Warning)
+				}
+			}
+		}
+		w.resultMu.Unlock()
+		if err != nil && w.option.OnError != nil {
+			w.option.OnError(&BufferedWriteError{Documents: batch, Err: err})
+		}
+		w.addPending(-1)
+	}
+}
+
+// Result returns the documents upserted so far, call it after Flush or Close to count all the added documents.
+func (w *BufferedWriter) Result() BufferedWriterResult {
+	w.resultMu.Lock()
+	defer w.resultMu.Unlock()
+	result := w.result
+	result.Warnings = append([]string(nil), w.result.Warnings...)
+	return result
+}
+
+func (w *BufferedWriter) addPending(delta int) {
+	w.pendingMu.Lock()
+	w.pending += delta
+	if w.pending == 0 {
+		w.idle.Broadcast()
+	}
+	w.pendingMu.Unlock()
+}
+
+func (w *BufferedWriter) waitPending() {
+	w.pendingMu.Lock()
+	for w.pending > 0 {
+		w.idle.Wait()
+	}
+	w.pendingMu.Unlock()
+}
+
+func (w *BufferedWriter) tick() {
+	defer w.workers.Done()
+	ticker := time.NewTicker(w.option.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			var batch writerBatch
+			if !w.closed {
+				batch = w.takeLocked()
+			}
+			w.mu.Unlock()
+			w.send(context.Background(), batch)
+		}
+	}
+}
+
+const defaultStreamBatchSize = 500
+
+type StreamOption struct {
+	// BatchSize: the max number of documents of each Upsert, default 500
+	BatchSize int
+	// MaxInFlight: the max number of Upserts at the same time, default 1. The channel is not read
+	// while MaxInFlight Upserts are pending, so the slow server blocks the producer.
+	MaxInFlight int
+	BuildIndex  *bool
+	// FlushInterval: upsert the partial batch if no document is received in the interval, default 1s
+	FlushInterval time.Duration
+	// OnError: called with the failed batch when an Upsert fails, one at a time. The failed batches are
+	// kept in UpsertStreamResult.Failures only if it is nil.
+	OnError func(err *BufferedWriteError)
+}
+
+type UpsertStreamResult struct {
+	// Upserted: the number of documents upserted
+	Upserted int
+	// AffectedCount: the sum of AffectedCount of the Upserts, less than Upserted if the server
+	// does not write some documents
+	AffectedCount int
+	// Warnings: the warnings of server
+	Warnings []string
+	// Failed: the number of documents failed to upsert, including the ones received but not sent
+	// when the ctx is done
+	Failed int
+	// Failures: the failed batches, only set if StreamOption.OnError is nil
+	Failures []*BufferedWriteError
+}
+
+// UpsertStream reads documents from the channel and upserts them in batches, until the channel is
+// closed or the ctx is done. It returns after all the pending Upserts are done, the error is the one
+// of ctx, the failures of Upserts are reported in the result or by StreamOption.OnError.
+func (c *Collection) UpsertStream(ctx context.Context, docs <-chan Document, option StreamOption) (*UpsertStreamResult, error) {
+	params := &UpsertDocumentParams{BuildIndex: option.BuildIndex}
+	return upsertStream(ctx, docs, option, func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error) {
+		return c.Upsert(ctx, documents, params)
+	})
+}
+
+func upsertStream(ctx context.Context, docs <-chan Document, option StreamOption, upsert upsertBatchFunc) (*UpsertStreamResult, error) {
+	if option.BatchSize <= 0 {
+		option.BatchSize = defaultStreamBatchSize
+	}
+	if option.MaxInFlight <= 0 {
+		option.MaxInFlight = 1
+	}
+	if option.FlushInterval <= 0 {
+		option.FlushInterval = defaultBufferedWriterFlushInterval
+	}
+
+	var (
+		mu      sync.Mutex
+		result  = new(UpsertStreamResult)
+		pending sync.WaitGroup
+		slots   = make(chan struct{}, option.MaxInFlight)
+	)
+	fail := func(batch []Document, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		result.Failed += len(batch)
+		writeErr := &BufferedWriteError{Documents: batch, Err: err}
+		if option.OnError != nil {
+			option.OnError(writeErr)
+		} else {
+			result.Failures = append(result.Failures, writeErr)
+		}
+	}
+	// send blocks until a slot is free, so that the channel is not read meanwhile
+	send := func(batch []Document) {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			fail(batch, ctx.Err())
+			return
+		}
+		pending.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				pending.Done()
+			}()
+			res, err := upsert(ctx, batch)
+			if err != nil {
+				fail(batch, err)
+				return
+			}
+			mu.Lock()
+			result.Upserted += len(batch)
+			if res != nil {
+				result.AffectedCount += res.AffectedCount
+				if res.Warning != "" {
+					result.Warnings = append(result.Warnings, res.Warning)
+				}
+			}
+			mu.Unlock()
+		}()
+	}
+
+	timer := time.NewTimer(option.FlushInterval)
+	defer timer.Stop()
+	var (
+		batch []Document
+		err   error
+	)
+loop:
+	for {
+		select {
+		case doc, ok := <-docs:
+			if !ok {
+				break loop
+			}
+			batch = append(batch, doc)
+			if len(batch) >= option.BatchSize {
+				send(batch)
+				batch = nil
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(option.FlushInterval)
+		case <-timer.C:
+			if len(batch) != 0 {
+				send(batch)
+				batch = nil
+			}
+			timer.Reset(option.FlushInterval)
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	if err = ctx.Err(); err != nil {
+		if len(batch) != 0 {
+			fail(batch, err)
+		}
+	} else if len(batch) != 0 {
+		send(batch)
+	}
+	pending.Wait()
+	return result, err
+}
@@ -0,0 +1,188 @@
+package tcvectordb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBufferedWriter(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]Document
+		failed  []*BufferedWriteError
+	)
+	failure := errors.New("server error")
+	w := newBufferedWriter(BufferedWriterOption{
+		MaxDocs:       3,
+		FlushInterval: time.Hour,
+		Concurrency:   2,
+		OnError: func(err *BufferedWriteError) {
+			mu.Lock()
+			failed = append(failed, err)
+			mu.Unlock()
+		},
+	}, func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, documents)
+		if documents[0].Id == "d" {
+			return nil, failure
+		}
+		if documents[0].Id == "a" {
+			// the server drops a document
+			return &UpsertDocumentResult{AffectedCount: len(documents) - 1, Warning: "document b is dropped"}, nil
+		}
+		return &UpsertDocumentResult{AffectedCount: len(documents)}, nil
+	})
+
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		if err := w.Add(ctx, Document{Id: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if len(batches) != 2 || len(failed) != 1 || len(failed[0].Documents) != 2 || failed[0].Documents[1].Id != "e" ||
+		!errors.Is(failed[0], failure) {
+		t.Errorf("unexpected batches %v, failed %v", batches, failed)
+	}
+	mu.Unlock()
+
+	w.Add(ctx, Document{Id: "f"})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 3 || batches[2][0].Id != "f" {
+		t.Errorf("expect the buffer flushed by Close, got %v", batches)
+	}
+	if res := w.Result(); res.Upserted != 4 || res.AffectedCount != 3 || res.Failed != 2 || len(res.Warnings) != 1 {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if err := w.Add(ctx, Document{Id: "g"}); err == nil {
+		t.Error("expect error of adding into closed writer")
+	}
+}
+
+func TestBufferedWriterSlowWorker(t *testing.T) {
+	release := make(chan struct{})
+	w := newBufferedWriter(BufferedWriterOption{MaxDocs: 1, FlushInterval: time.Hour},
+		func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error) {
+			<-release
+			return &UpsertDocumentResult{AffectedCount: len(documents)}, nil
+		})
+
+	// the worker blocks on a, b waits in the channel, and c is being sent
+	ctx := context.Background()
+	w.Add(ctx, Document{Id: "a"})
+	w.Add(ctx, Document{Id: "b"})
+	go w.Add(ctx, Document{Id: "c"})
+	time.Sleep(20 * time.Millisecond)
+
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.Add(timeout, Document{Id: "d"}) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expect the ctx error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expect Add not blocked behind the pending send")
+	}
+
+	close(release)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if res := w.Result(); res.Upserted != 4 {
+		t.Errorf("expect the document put back upserted by Close, got %+v", res)
+	}
+}
+
+func TestBufferedWriterInterval(t *testing.T) {
+	flushed := make(chan []Document, 1)
+	w := newBufferedWriter(BufferedWriterOption{FlushInterval: 10 * time.Millisecond, MaxBytes: 1 << 20},
+		func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error) {
+			flushed <- documents
+			return &UpsertDocumentResult{AffectedCount: len(documents)}, nil
+		})
+	defer w.Close()
+	w.Add(context.Background(), Document{Id: "a"})
+	select {
+	case docs := <-flushed:
+		if len(docs) != 1 {
+			t.Errorf("unexpected batch %v", docs)
+		}
+	case <-time.After(time.Second):
+		t.Error("expect the buffer flushed by interval")
+	}
+}
+
+func TestUpsertStream(t *testing.T) {
+	failure := errors.New("server error")
+	docs := make(chan Document, 10)
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		docs <- Document{Id: id}
+	}
+	close(docs)
+	res, err := upsertStream(context.Background(), docs, StreamOption{BatchSize: 3, MaxInFlight: 2},
+		func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error) {
+			if documents[0].Id == "d" {
+				return nil, failure
+			}
+			return &UpsertDocumentResult{AffectedCount: len(documents)}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Upserted != 4 || res.AffectedCount != 4 || res.Failed != 3 || len(res.Failures) != 1 || res.Failures[0].Documents[2].Id != "f" ||
+		!errors.Is(res.Failures[0], failure) {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	// the channel is not read while the upsert is pending
+	docs = make(chan Document)
+	release := make(chan struct{})
+	done := make(chan *UpsertStreamResult)
+	go func() {
+		res, _ := upsertStream(context.Background(), docs, StreamOption{BatchSize: 1, MaxInFlight: 1},
+			func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error) {
+				<-release
+				return &UpsertDocumentResult{AffectedCount: len(documents)}, nil
+			})
+		done <- res
+	}()
+	docs <- Document{Id: "a"}
+	docs <- Document{Id: "b"}
+	select {
+	case docs <- Document{Id: "c"}:
+		t.Fatal("expect the producer blocked by the pending upsert")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	docs <- Document{Id: "c"}
+	close(docs)
+	if res := <-done; res.Upserted != 3 {
+		t.Errorf("expect 3 documents upserted, got %+v", res)
+	}
+
+	var failed []*BufferedWriteError
+	ctx, cancel := context.WithCancel(context.Background())
+	docs = make(chan Document, 1)
+	docs <- Document{Id: "a"}
+	time.AfterFunc(20*time.Millisecond, cancel)
+	res, err = upsertStream(ctx, docs, StreamOption{BatchSize: 10, FlushInterval: time.Hour,
+		OnError: func(err *BufferedWriteError) { failed = append(failed, err) }},
+		func(ctx context.Context, documents []Document) (*UpsertDocumentResult, error) { return nil, nil })
+	if !errors.Is(err, context.Canceled) || res.Failed != 1 || res.Failures != nil || len(failed) != 1 ||
+		failed[0].Documents[0].Id != "a" {
+		t.Errorf("expect the received documents reported on cancel, got %+v, %v, %v", res, failed, err)
+	}
+}
@@ -20,6 +20,7 @@ package tcvectordb
 
 import (
 	"context"
+	"fmt"
 )
 
 var _ IndexInterface = &implementerIndex{}
@@ -28,6 +29,8 @@ type IndexInterface interface {
 	SdkClient
 	RebuildIndex(ctx context.Context, params ...*RebuildIndexParams) (result *RebuildIndexResult, err error)
 	AddIndex(ctx context.Context, params ...*AddIndexParams) (err error)
+	ModifyVectorIndex(ctx context.Context, param ModifyVectorIndexParams) (result *ModifyVectorIndexResult, err error)
+	SyncSchema(ctx context.Context, desired Indexes, option ...*SyncOption) (result *SyncSchemaResult, err error)
 }
 
 type implementerIndex struct {
@@ -38,13 +41,33 @@ type implementerIndex struct {
 }
 
 type RebuildIndexResult struct {
+	// TaskIds: the ids of rebuilding tasks, which is all the server returns
 	TaskIds []string
 }
 
+// errIndexCollectionRequired is returned by the index api of Database, which has no collection.
+func errIndexCollectionRequired(database *Database) error {
+	return fmt.Errorf("the index api of database %s requires a collection, use Database.Collection(name) instead", database.DatabaseName)
+}
+
+// RebuildIndex rebuild all the indexes of the collection, the TaskIds could be followed by WaitIndexRebuilt.
+// The IndexInterface of Database has no collection, use Database.Collection(name).RebuildIndex
+// or the RebuildIndex of client instead.
 func (i *implementerIndex) RebuildIndex(ctx context.Context, params ...*RebuildIndexParams) (*RebuildIndexResult, error) {
+	if i.collection == nil {
+		return nil, errIndexCollectionRequired(i.database)
+	}
 	return i.flat.RebuildIndex(ctx, i.database.DatabaseName, i.collection.CollectionName, params...)
 }
 
 func (i *implementerIndex) AddIndex(ctx context.Context, params ...*AddIndexParams) error {
 	return i.flat.AddIndex(ctx, i.database.DatabaseName, i.collection.CollectionName, params...)
 }
+
+func (i *implementerIndex) ModifyVectorIndex(ctx context.Context, param ModifyVectorIndexParams) (*ModifyVectorIndexResult, error) {
+	return i.flat.ModifyVectorIndex(ctx, i.database.DatabaseName, i.collection.CollectionName, param)
+}
+
+func (i *implementerIndex) SyncSchema(ctx context.Context, desired Indexes, option ...*SyncOption) (*SyncSchemaResult, error) {
+	return i.flat.SyncSchema(ctx, i.database.DatabaseName, i.collection.CollectionName, desired, option...)
+}
@@ -2,6 +2,7 @@ package tcvectordb
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/index"
@@ -13,6 +14,8 @@ type FlatIndexInterface interface {
 	SdkClient
 	RebuildIndex(ctx context.Context, databaseName, collectionName string, params ...*RebuildIndexParams) (result *RebuildIndexResult, err error)
 	AddIndex(ctx context.Context, databaseName, collectionName string, params ...*AddIndexParams) (err error)
+	ModifyVectorIndex(ctx context.Context, databaseName, collectionName string, param ModifyVectorIndexParams) (result *ModifyVectorIndexResult, err error)
+	SyncSchema(ctx context.Context, databaseName, collectionName string, desired Indexes, option ...*SyncOption) (result *SyncSchemaResult, err error)
 }
 
 type implementerFlatIndex struct {
@@ -20,8 +23,29 @@ type implementerFlatIndex struct {
 }
 
 type RebuildIndexParams struct {
+	// DropBeforeRebuild: drop the current index before rebuilding, the searches fail until the rebuilding is done
 	DropBeforeRebuild bool
-	Throttle          int
+	// Throttle: limit the cpu cores used by the rebuilding, default ThrottleUnlimited
+	Throttle RebuildThrottle
+}
+
+// RebuildThrottle limits the cpu cores of each node used by the index rebuilding,
+// the server accepts only ThrottleUnlimited and ThrottleLow.
+type RebuildThrottle int
+
+const (
+	// ThrottleUnlimited the rebuilding uses all the cpu cores, it is done fastest
+	ThrottleUnlimited RebuildThrottle = 0
+	// ThrottleLow the rebuilding uses 1 cpu core of each node, so the searches are least affected
+	ThrottleLow RebuildThrottle = 1
+)
+
+// check validates the throttle before sending, as the server rejects the other values without a clear message.
+func (t RebuildThrottle) check() error {
+	if t != ThrottleUnlimited && t != ThrottleLow {
+		return fmt.Errorf("invalid rebuild index throttle %d, which must be ThrottleUnlimited(0) or ThrottleLow(1)", t)
+	}
+	return nil
 }
 
 type AddIndexParams struct {
@@ -29,6 +53,17 @@ type AddIndexParams struct {
 	BuildExistedData *bool
 }
 
+type ModifyVectorIndexParams struct {
+	// VectorIndexes: the vector indexes with new index type and params, the Dimension and MetricType
+	// could be left empty, otherwise they must be as same as the current index
+	VectorIndexes []VectorIndex
+	RebuildRules  *RebuildIndexParams
+}
+
+type ModifyVectorIndexResult struct {
+	TaskIds []string
+}
+
 func (i *implementerFlatIndex) RebuildIndex(ctx context.Context, databaseName, collectionName string, params ...*RebuildIndexParams) (*RebuildIndexResult, error) {
 	req := new(index.RebuildReq)
 	req.Database = databaseName
@@ -36,6 +71,9 @@ func (i *implementerFlatIndex) RebuildIndex(ctx context.Context, databaseName, c
 
 	if len(params) != 0 && params[0] != nil {
 		param := params[0]
+		if err := param.Throttle.check(); err != nil {
+			return nil, err
+		}
 		req.DropBeforeRebuild = param.DropBeforeRebuild
 		req.Throttle = int32(param.Throttle)
 	}
@@ -58,9 +96,10 @@ func (i *implementerFlatIndex) AddIndex(ctx context.Context, databaseName, colle
 		param := params[0]
 		for _, index := range param.FilterIndexs {
 			req.Indexes = append(req.Indexes, &api.IndexColumn{
-				FieldName: index.FieldName,
-				FieldType: string(index.FieldType),
-				IndexType: string(index.IndexType),
+				FieldName:        index.FieldName,
+				FieldType:        string(index.FieldType),
+				FieldElementType: string(index.ElemType),
+				IndexType:        string(index.IndexType),
 			})
 		}
 		req.BuildExistedData = param.BuildExistedData
@@ -73,3 +112,94 @@ func (i *implementerFlatIndex) AddIndex(ctx context.Context, databaseName, colle
 	}
 	return nil
 }
+
+// ModifyVectorIndex modify the params of vector indexes, and rebuild the indexes with the rebuild rules.
+// The dimension and metric type can not be modified, it returns an error without requesting the server
+// if they are different from the current index.
+func (i *implementerFlatIndex) ModifyVectorIndex(ctx context.Context, databaseName, collectionName string, param ModifyVectorIndexParams) (*ModifyVectorIndexResult, error) {
+	if len(param.VectorIndexes) == 0 {
+		return nil, fmt.Errorf("VectorIndexes is empty")
+	}
+	err := checkIndexParams(param.VectorIndexes)
+	if err != nil {
+		return nil, err
+	}
+	err = checkModifyVectorIndex(ctx, i.SdkClient, databaseName, collectionName, param.VectorIndexes)
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(index.ModifyVectorIndexReq)
+	req.Database = databaseName
+	req.Collection = collectionName
+	for _, v := range param.VectorIndexes {
+		var column api.IndexColumn
+		column.FieldName = v.FieldName
+		column.FieldType = string(v.FieldType)
+		column.IndexType = string(v.IndexType)
+		column.MetricType = string(v.MetricType)
+		column.Dimension = v.Dimension
+
+		optionParams(&column, v)
+
+		req.VectorIndexes = append(req.VectorIndexes, &column)
+	}
+	if param.RebuildRules != nil {
+		if err := param.RebuildRules.Throttle.check(); err != nil {
+			return nil, err
+		}
+		req.RebuildRules = &index.RebuildRules{
+			DropBeforeRebuild: param.RebuildRules.DropBeforeRebuild,
+			Throttle:          int32(param.RebuildRules.Throttle),
+		}
+	}
+
+	res := new(index.ModifyVectorIndexRes)
+	err = i.Request(ctx, req, res)
+	if err != nil {
+		return nil, err
+	}
+	return &ModifyVectorIndexResult{TaskIds: res.TaskIds}, nil
+}
+
+// checkModifyVectorIndex describe the collection only if the dimension or metric type is set,
+// and reject the modification of them.
+func checkModifyVectorIndex(ctx context.Context, cli SdkClient, databaseName, collectionName string, vectorIndexes []VectorIndex) error {
+	check := false
+	for _, v := range vectorIndexes {
+		if v.Dimension != 0 || v.MetricType != "" {
+			check = true
+			break
+		}
+	}
+	if !check {
+		return nil
+	}
+	db := (&implementerDatabase{SdkClient: cli}).Database(databaseName)
+	coll, err := db.DescribeCollection(ctx, collectionName)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]VectorIndex, len(coll.Indexes.VectorIndex))
+	for _, v := range coll.Indexes.VectorIndex {
+		current[v.FieldName] = v
+	}
+	for _, v := range vectorIndexes {
+		cur, ok := current[v.FieldName]
+		if !ok {
+			return fmt.Errorf("vector index %s not exist in collection %s", v.FieldName, collectionName)
+		}
+		if v.Dimension != 0 && v.Dimension != cur.Dimension {
+			return fmt.Errorf("can not modify the dimension of vector index %s from %d to %d", v.FieldName, cur.Dimension, v.Dimension)
+		}
+		if v.MetricType != "" && v.MetricType != cur.MetricType {
+			return fmt.Errorf("can not modify the metric type of vector index %s from %s to %s", v.FieldName, cur.MetricType, v.MetricType)
+		}
+	}
+	return nil
+}
+
+// SyncSchema adds the filter indexes of desired schema missing in the collection, see [syncSchema].
+func (i *implementerFlatIndex) SyncSchema(ctx context.Context, databaseName, collectionName string, desired Indexes, option ...*SyncOption) (*SyncSchemaResult, error) {
+	return syncSchema(ctx, i, databaseName, collectionName, desired, option...)
+}
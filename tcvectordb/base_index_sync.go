@@ -0,0 +1,164 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type SyncOption struct {
+	// AllowAddOnly: apply the additive changes even if there are differences refused to make,
+	// otherwise nothing is applied and an error is returned with the report
+	AllowAddOnly bool
+	// BuildExistedData: whether to build the new indexes for the existed documents, default true
+	BuildExistedData *bool
+	// DryRun: only compute the report without applying any change
+	DryRun bool
+}
+
+// SchemaChange is a difference between the desired schema and the collection.
+type SchemaChange struct {
+	FieldName string
+	// Description: the change in words, e.g. "add filter index: string filter"
+	Description string
+}
+
+type SyncSchemaResult struct {
+	// Applied: the additive changes, they are applied to the collection unless it is a dry run or an error is returned
+	Applied []SchemaChange
+	// Refused: the changes refused to make, such as type changes, removed fields and vector index changes
+	Refused []SchemaChange
+	// FilterIndexes: the filter indexes added or to be added
+	FilterIndexes []FilterIndex
+}
+
+// syncSchema describes the collection, and adds the filter indexes of the desired schema which
+// don't exist in the collection. Destructive differences are never applied, they are reported in
+// [SyncSchemaResult.Refused].
+func syncSchema(ctx context.Context, flat FlatIndexInterface, databaseName, collectionName string,
+	desired Indexes, option ...*SyncOption) (*SyncSchemaResult, error) {
+	opt := SyncOption{}
+	if len(option) != 0 && option[0] != nil {
+		opt = *option[0]
+	}
+	db := (&implementerDatabase{SdkClient: flat}).Database(databaseName)
+	coll, err := db.DescribeCollection(ctx, collectionName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := diffSchema(desired, coll.Indexes)
+	if len(result.Applied) == 0 || opt.DryRun {
+		return result, nil
+	}
+	if len(result.Refused) != 0 && !opt.AllowAddOnly {
+		refused := make([]string, 0, len(result.Refused))
+		for _, change := range result.Refused {
+			refused = append(refused, change.FieldName+": "+change.Description)
+		}
+		return result, fmt.Errorf("schema of collection %s has changes refused to make: %s",
+			collectionName, strings.Join(refused, "; "))
+	}
+	err = flat.AddIndex(ctx, databaseName, collectionName, &AddIndexParams{
+		FilterIndexs:     result.FilterIndexes,
+		BuildExistedData: opt.BuildExistedData,
+	})
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// diffSchema computes the changes from the existing indexes to the desired ones, only new filter
+// indexes are applicable.
+func diffSchema(desired, existing Indexes) *SyncSchemaResult {
+	result := new(SyncSchemaResult)
+	describeFilter := func(v FilterIndex) string {
+		if v.FieldType == Array && v.ElemType != "" {
+			return fmt.Sprintf("%s<%s> %s", v.FieldType, v.ElemType, v.IndexType)
+		}
+		return fmt.Sprintf("%s %s", v.FieldType, v.IndexType)
+	}
+
+	vectors := func(indexes Indexes) map[string]string {
+		res := make(map[string]string)
+		for _, v := range indexes.VectorIndex {
+			res[v.FieldName] = fmt.Sprintf("%s %s dimension %d metric %s", v.FieldType, v.IndexType, v.Dimension, v.MetricType)
+		}
+		for _, v := range indexes.SparseVectorIndex {
+			res[v.FieldName] = fmt.Sprintf("%s %s metric %s", v.FieldType, v.IndexType, v.MetricType)
+		}
+		for _, v := range indexes.BinaryVectorIndex {
+			res[v.FieldName] = fmt.Sprintf("%s %s dimension %d metric %s", v.FieldType, v.IndexType, v.Dimension, v.MetricType)
+		}
+		return res
+	}
+	wantVectors, gotVectors := vectors(desired), vectors(existing)
+	for name, w := range wantVectors {
+		if g, ok := gotVectors[name]; !ok {
+			result.Refused = append(result.Refused, SchemaChange{name, "add vector index: " + w})
+		} else if g != w {
+			result.Refused = append(result.Refused, SchemaChange{name, fmt.Sprintf("change vector index: %s to %s", g, w)})
+		}
+	}
+	for name, g := range gotVectors {
+		if _, ok := wantVectors[name]; !ok {
+			result.Refused = append(result.Refused, SchemaChange{name, "remove vector index: " + g})
+		}
+	}
+
+	gotFilters := make(map[string]FilterIndex, len(existing.FilterIndex))
+	for _, v := range existing.FilterIndex {
+		gotFilters[v.FieldName] = v
+	}
+	wantFilters := make(map[string]bool, len(desired.FilterIndex))
+	for _, v := range desired.FilterIndex {
+		wantFilters[v.FieldName] = true
+		g, ok := gotFilters[v.FieldName]
+		switch {
+		case !ok && v.IndexType == PRIMARY:
+			result.Refused = append(result.Refused, SchemaChange{v.FieldName, "add primary key: " + describeFilter(v)})
+		case !ok:
+			result.Applied = append(result.Applied, SchemaChange{v.FieldName, "add filter index: " + describeFilter(v)})
+			result.FilterIndexes = append(result.FilterIndexes, v)
+		default:
+			// the collection may not return the element type of array field
+			if v.ElemType == "" || g.ElemType == "" {
+				v.ElemType, g.ElemType = "", ""
+			}
+			if describeFilter(g) != describeFilter(v) {
+				result.Refused = append(result.Refused, SchemaChange{v.FieldName,
+					fmt.Sprintf("change filter index: %s to %s", describeFilter(g), describeFilter(v))})
+			}
+		}
+	}
+	for _, g := range existing.FilterIndex {
+		if !wantFilters[g.FieldName] {
+			result.Refused = append(result.Refused, SchemaChange{g.FieldName, "remove filter index: " + describeFilter(g)})
+		}
+	}
+
+	sort.Slice(result.Refused, func(a, b int) bool {
+		return result.Refused[a].FieldName < result.Refused[b].FieldName
+	})
+	return result
+}
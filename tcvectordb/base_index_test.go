@@ -0,0 +1,141 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/index"
+)
+
+func TestModifyVectorIndex(t *testing.T) {
+	var modifyReq *index.ModifyVectorIndexReq
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collection/describe":
+			w.Write([]byte(`{"code":0,"collection":{"collection":"coll","indexes":[` +
+				`{"fieldName":"vector","fieldType":"vector","indexType":"HNSW","dimension":768,"metricType":"COSINE"}]}}`))
+		case "/index/modifyVectorIndex":
+			modifyReq = new(index.ModifyVectorIndexReq)
+			json.NewDecoder(r.Body).Decode(modifyReq)
+			w.Write([]byte(`{"code":0,"task_ids":["task-1"]}`))
+		}
+	}, ClientOption{})
+
+	vectorIndex := VectorIndex{
+		FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW},
+		Params:      &HNSWParam{M: 32, EfConstruction: 400},
+	}
+	res, err := cli.ModifyVectorIndex(context.Background(), "db", "coll", ModifyVectorIndexParams{
+		VectorIndexes: []VectorIndex{vectorIndex},
+		RebuildRules:  &RebuildIndexParams{Throttle: ThrottleLow},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.TaskIds) != 1 || modifyReq == nil || modifyReq.VectorIndexes[0].Params.EfConstruction != 400 ||
+		modifyReq.RebuildRules.Throttle != 1 {
+		t.Errorf("unexpected modify request %+v, result %+v", modifyReq, res)
+	}
+
+	modifyReq = nil
+	vectorIndex.Dimension = 1024
+	_, err = cli.ModifyVectorIndex(context.Background(), "db", "coll", ModifyVectorIndexParams{VectorIndexes: []VectorIndex{vectorIndex}})
+	if err == nil || !strings.Contains(err.Error(), "dimension") || modifyReq != nil {
+		t.Errorf("expect dimension modification rejected, got %v", err)
+	}
+
+	vectorIndex.Dimension = 768
+	vectorIndex.MetricType = IP
+	_, err = cli.ModifyVectorIndex(context.Background(), "db", "coll", ModifyVectorIndexParams{VectorIndexes: []VectorIndex{vectorIndex}})
+	if err == nil || !strings.Contains(err.Error(), "metric type") || modifyReq != nil {
+		t.Errorf("expect metric type modification rejected, got %v", err)
+	}
+}
+
+func TestSyncSchema(t *testing.T) {
+	var addReq *index.AddReq
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collection/describe":
+			w.Write([]byte(`{"code":0,"collection":{"collection":"coll","indexes":[` +
+				`{"fieldName":"id","fieldType":"string","indexType":"primaryKey"},` +
+				`{"fieldName":"vector","fieldType":"vector","indexType":"HNSW","dimension":768,"metricType":"COSINE"},` +
+				`{"fieldName":"author","fieldType":"string","indexType":"filter"},` +
+				`{"fieldName":"page","fieldType":"uint64","indexType":"filter"}]}}`))
+		case "/index/add":
+			addReq = new(index.AddReq)
+			json.NewDecoder(r.Body).Decode(addReq)
+			w.Write([]byte(`{"code":0}`))
+		}
+	}, ClientOption{})
+
+	desired := Indexes{
+		VectorIndex: []VectorIndex{{FilterIndex: FilterIndex{FieldName: "vector", FieldType: Vector, IndexType: HNSW},
+			Dimension: 768, MetricType: COSINE}},
+		FilterIndex: []FilterIndex{
+			{FieldName: "id", FieldType: String, IndexType: PRIMARY},
+			{FieldName: "author", FieldType: String, IndexType: FILTER},
+			{FieldName: "page", FieldType: Uint64, IndexType: FILTER},
+			{FieldName: "tags", FieldType: Array, ElemType: String, IndexType: FILTER},
+		},
+	}
+	res, err := cli.SyncSchema(context.Background(), "db", "coll", desired)
+	if err != nil || len(res.Refused) != 0 || len(res.Applied) != 1 || addReq == nil || len(addReq.Indexes) != 1 ||
+		addReq.Indexes[0].FieldName != "tags" || addReq.Indexes[0].FieldElementType != "string" {
+		t.Fatalf("expect tags index added, got %+v, %+v, %v", res, addReq, err)
+	}
+
+	addReq = nil
+	desired.VectorIndex[0].Dimension = 1024
+	desired.FilterIndex[2].FieldType = String
+	desired.FilterIndex = append(desired.FilterIndex[:1], desired.FilterIndex[2:]...)
+	res, err = cli.SyncSchema(context.Background(), "db", "coll", desired)
+	if err == nil || addReq != nil || len(res.Applied) != 1 {
+		t.Fatalf("expect nothing applied with refused changes, got %+v, %v", res, err)
+	}
+	var refused []string
+	for _, change := range res.Refused {
+		refused = append(refused, change.FieldName)
+	}
+	if strings.Join(refused, ",") != "author,page,vector" {
+		t.Errorf("unexpected refused changes %+v", res.Refused)
+	}
+
+	res, err = cli.SyncSchema(context.Background(), "db", "coll", desired, &SyncOption{AllowAddOnly: true, DryRun: true})
+	if err != nil || addReq != nil || len(res.Applied) != 1 {
+		t.Errorf("expect nothing applied in dry run, got %+v, %v", res, err)
+	}
+	_, err = cli.SyncSchema(context.Background(), "db", "coll", desired, &SyncOption{AllowAddOnly: true})
+	if err != nil || addReq == nil || addReq.Indexes[0].FieldName != "tags" {
+		t.Errorf("expect additive changes applied, got %+v, %v", addReq, err)
+	}
+}
+
+func TestRebuildIndexThrottle(t *testing.T) {
+	var rebuildReq *index.RebuildReq
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rebuildReq = new(index.RebuildReq)
+		json.NewDecoder(r.Body).Decode(rebuildReq)
+		w.Write([]byte(`{"code":0,"task_ids":["task-1"]}`))
+	}, ClientOption{})
+	ctx := context.Background()
+
+	res, err := cli.Database("db").Collection("coll").RebuildIndex(ctx, &RebuildIndexParams{DropBeforeRebuild: true, Throttle: ThrottleLow})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.TaskIds) != 1 || !rebuildReq.DropBeforeRebuild || rebuildReq.Throttle != 1 {
+		t.Errorf("unexpected rebuild request %+v, result %+v", rebuildReq, res)
+	}
+
+	rebuildReq = nil
+	if _, err = cli.RebuildIndex(ctx, "db", "coll", &RebuildIndexParams{Throttle: 4}); err == nil || rebuildReq != nil {
+		t.Errorf("expect the invalid throttle rejected before sending, got %v", err)
+	}
+	if _, err = cli.Database("db").RebuildIndex(ctx); err == nil || rebuildReq != nil {
+		t.Errorf("expect the rebuild of database rejected, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/collection"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/server"
+)
+
+// CollectionStats the statistics of collection.
+type CollectionStats struct {
+	DocumentCount uint64 `json:"documentCount"`
+	// IndexMemoryUsage: the memory used by the indexes, in bytes
+	IndexMemoryUsage uint64 `json:"indexMemoryUsage"`
+	SegmentCount     uint64 `json:"segmentCount"`
+	// PendingIndexBuild: the number of documents waiting for the index building, used to watch the index lag
+	PendingIndexBuild uint64       `json:"pendingIndexBuild"`
+	Shards            []ShardStats `json:"shards"`
+	// RawExtra: the fields unknown by the sdk, returned by the newer server
+	RawExtra map[string]interface{} `json:"-"`
+}
+
+type ShardStats struct {
+	ShardId       uint32 `json:"shardId"`
+	DocumentCount uint64 `json:"documentCount"`
+}
+
+// ServerInfo the information of the vectordb server.
+type ServerInfo struct {
+	Version string        `json:"version"`
+	Uptime  time.Duration `json:"-"`
+	Nodes   []NodeInfo    `json:"nodes"`
+	// RawExtra: the fields unknown by the sdk, returned by the newer server
+	RawExtra map[string]interface{} `json:"-"`
+}
+
+type NodeInfo struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Role    string `json:"role"`
+	Status  string `json:"status"`
+}
+
+// Stats get the statistics of collection, such as the index memory usage and the pending index building.
+func (c *Collection) Stats(ctx context.Context) (*CollectionStats, error) {
+	req := new(collection.StatsReq)
+	req.Database = c.DatabaseName
+	req.Collection = c.CollectionName
+	res := new(collection.StatsRes)
+	err := c.DocumentInterface.Request(ctx, req, res)
+	if err != nil {
+		return nil, err
+	}
+	stats := new(CollectionStats)
+	stats.RawExtra, err = unmarshalWithExtra(res.Stats, stats)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ServerInfo get the version, uptime and nodes of the server.
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	return serverInfo(ctx, c)
+}
+
+// ServerInfo get the version, uptime and nodes of the server.
+func (r *RpcClient) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	return serverInfo(ctx, r)
+}
+
+// ServerInfo get the version, uptime and nodes of the server.
+func (c *VDBCLient) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	return serverInfo(ctx, c.cli)
+}
+
+func serverInfo(ctx context.Context, cli SdkClient) (*ServerInfo, error) {
+	res := new(server.InfoRes)
+	err := cli.Request(ctx, new(server.InfoReq), res)
+	if err != nil {
+		return nil, err
+	}
+	info := new(ServerInfo)
+	info.RawExtra, err = unmarshalWithExtra(res.Info, info, "uptime")
+	if err != nil {
+		return nil, err
+	}
+	// the uptime is returned in seconds
+	var uptime struct {
+		Uptime int64 `json:"uptime"`
+	}
+	if len(res.Info) != 0 {
+		if err = json.Unmarshal(res.Info, &uptime); err != nil {
+			return nil, err
+		}
+	}
+	info.Uptime = time.Duration(uptime.Uptime) * time.Second
+	return info, nil
+}
+
+// unmarshalWithExtra unmarshal data into the struct v, and returns the fields which are neither in v nor the known keys.
+func unmarshalWithExtra(data []byte, v interface{}, known ...string) (map[string]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	err := json.Unmarshal(data, v)
+	if err != nil {
+		return nil, err
+	}
+	var extra map[string]interface{}
+	ds := json.NewDecoder(bytes.NewReader(data))
+	ds.UseNumber()
+	err = ds.Decode(&extra)
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(v).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		tags := strings.Split(t.Field(i).Tag.Get("json"), ",")
+		delete(extra, tags[0])
+	}
+	for _, k := range known {
+		delete(extra, k)
+	}
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
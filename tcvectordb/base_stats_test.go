@@ -0,0 +1,38 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCollectionStats(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collection/stats":
+			w.Write([]byte(`{"code":0,"stats":{"documentCount":10,"indexMemoryUsage":2048,"segmentCount":3,` +
+				`"pendingIndexBuild":5,"shards":[{"shardId":1,"documentCount":10}],"compactionQueue":2}}`))
+		case "/server/info":
+			w.Write([]byte(`{"code":0,"info":{"version":"1.5.0","uptime":90,"nodes":[{"name":"n1","role":"master"}]}}`))
+		}
+	}, ClientOption{})
+
+	stats, err := cli.Database("db").Collection("coll").Stats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.PendingIndexBuild != 5 || stats.IndexMemoryUsage != 2048 || len(stats.Shards) != 1 ||
+		len(stats.RawExtra) != 1 || stats.RawExtra["compactionQueue"] != json.Number("2") {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	info, err := cli.ServerInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "1.5.0" || info.Uptime != 90*time.Second || info.Nodes[0].Role != "master" || info.RawExtra != nil {
+		t.Errorf("unexpected server info %+v", info)
+	}
+}
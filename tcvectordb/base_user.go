@@ -0,0 +1,120 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/user"
+)
+
+var _ UserInterface = &implementerUser{}
+
+// UserInterface user api, the errors could be checked by IsAlreadyExist and IsPermissionDenied,
+// so that the user management is idempotent.
+type UserInterface interface {
+	CreateUser(ctx context.Context, name, password string) error
+	DropUser(ctx context.Context, name string) error
+	ChangePassword(ctx context.Context, name, newPassword string) error
+	GrantPrivilege(ctx context.Context, name, databaseName, collectionName string, privileges []string) error
+	RevokePrivilege(ctx context.Context, name, databaseName, collectionName string, privileges []string) error
+	DescribeUser(ctx context.Context, name string) (result *DescribeUserResult, err error)
+}
+
+type implementerUser struct {
+	SdkClient
+}
+
+// Privilege the actions granted on the resource, which is database.collection, * matches all
+type Privilege struct {
+	Resource string
+	Actions  []string
+}
+
+type DescribeUserResult struct {
+	User       string
+	CreateTime string
+	Roles      []string
+	Privileges []Privilege
+}
+
+// CreateUser create a user, it returns the error of IsAlreadyExist if the user exists.
+func (i *implementerUser) CreateUser(ctx context.Context, name, password string) error {
+	req := &user.CreateReq{User: name, Password: password}
+	return i.Request(ctx, req, new(user.CreateRes))
+}
+
+// DropUser drop the user.
+func (i *implementerUser) DropUser(ctx context.Context, name string) error {
+	req := &user.DropReq{User: name}
+	return i.Request(ctx, req, new(user.DropRes))
+}
+
+// ChangePassword change the password of the user.
+func (i *implementerUser) ChangePassword(ctx context.Context, name, newPassword string) error {
+	req := &user.ChangePasswordReq{User: name, Password: newPassword}
+	return i.Request(ctx, req, new(user.ChangePasswordRes))
+}
+
+// GrantPrivilege grant the privileges, such as read and readWrite, on the collection of database to the user.
+// The empty databaseName or collectionName means all databases or collections.
+func (i *implementerUser) GrantPrivilege(ctx context.Context, name, databaseName, collectionName string, privileges []string) error {
+	req := &user.GrantReq{User: name, Privileges: []*user.Privilege{{
+		Resource: privilegeResource(databaseName, collectionName),
+		Actions:  privileges,
+	}}}
+	return i.Request(ctx, req, new(user.GrantRes))
+}
+
+// RevokePrivilege revoke the privileges on the collection of database from the user.
+// The empty databaseName or collectionName means all databases or collections.
+func (i *implementerUser) RevokePrivilege(ctx context.Context, name, databaseName, collectionName string, privileges []string) error {
+	req := &user.RevokeReq{User: name, Privileges: []*user.Privilege{{
+		Resource: privilegeResource(databaseName, collectionName),
+		Actions:  privileges,
+	}}}
+	return i.Request(ctx, req, new(user.RevokeRes))
+}
+
+// DescribeUser get the roles and privileges of the user.
+func (i *implementerUser) DescribeUser(ctx context.Context, name string) (*DescribeUserResult, error) {
+	req := &user.DescribeReq{User: name}
+	res := new(user.DescribeRes)
+	if err := i.Request(ctx, req, res); err != nil {
+		return nil, err
+	}
+	result := &DescribeUserResult{User: res.User, CreateTime: res.CreateTime, Roles: res.Roles}
+	for _, p := range res.Privileges {
+		if p != nil {
+			result.Privileges = append(result.Privileges, Privilege{Resource: p.Resource, Actions: p.Actions})
+		}
+	}
+	return result, nil
+}
+
+func privilegeResource(databaseName, collectionName string) string {
+	if databaseName == "" {
+		databaseName = "*"
+	}
+	if collectionName == "" {
+		collectionName = "*"
+	}
+	return strings.Join([]string{databaseName, collectionName}, ".")
+}
@@ -0,0 +1,66 @@
+package tcvectordb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUserManagement(t *testing.T) {
+	var requests []string
+	logger := new(recordLogger)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.URL.Path+" "+strings.TrimSpace(string(body)))
+		switch r.URL.Path {
+		case "/user/create":
+			w.Write([]byte(`{"code":1,"msg":"user ops already exist"}`))
+		case "/user/drop":
+			w.Write([]byte(`{"code":1,"msg":"permission denied"}`))
+		case "/user/describe":
+			w.Write([]byte(`{"code":0,"user":"ops","roles":["readWrite"],"privileges":[{"resource":"db.*","actions":["read","write"]}]}`))
+		default:
+			w.Write([]byte(`{"code":0}`))
+		}
+	}, ClientOption{Logger: logger, LogBodies: true})
+	ctx := context.Background()
+
+	if err := cli.CreateUser(ctx, "ops", "pwd"); !IsAlreadyExist(err) {
+		t.Errorf("expect user already exist, got %v", err)
+	}
+	if err := cli.DropUser(ctx, "ops"); !IsPermissionDenied(err) {
+		t.Errorf("expect permission denied, got %v", err)
+	}
+	if err := cli.GrantPrivilege(ctx, "ops", "db", "", []string{"read"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.RevokePrivilege(ctx, "ops", "db", "coll", []string{"write"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.ChangePassword(ctx, "ops", "new"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := cli.DescribeUser(ctx, "ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.User != "ops" || len(res.Roles) != 1 || len(res.Privileges) != 1 || res.Privileges[0].Resource != "db.*" {
+		t.Errorf("unexpected user %+v", res)
+	}
+	expected := []string{
+		`/user/create {"user":"ops","password":"pwd"}`,
+		`/user/drop {"user":"ops"}`,
+		`/user/grant {"user":"ops","privileges":[{"resource":"db.*","actions":["read"]}]}`,
+		`/user/revoke {"user":"ops","privileges":[{"resource":"db.coll","actions":["write"]}]}`,
+		`/user/changePassword {"user":"ops","password":"new"}`,
+		`/user/describe {"user":"ops"}`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected requests %v", requests)
+	}
+	if logger.entries[0]["requestBody"] != "<redacted>" {
+		t.Errorf("expect the password not logged, got %v", logger.entries[0])
+	}
+}
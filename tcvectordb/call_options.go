@@ -0,0 +1,198 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
+	"google.golang.org/grpc"
+)
+
+// CallOptions the defaults of the operations made through a Database or Collection handle, see WithDefaults.
+// Each option is resolved in the order: call > collection > database > client, that is the params and ctx
+// of the call override the defaults of the collection, which override the ones of its database,
+// and the ClientOption is used if none of them sets it.
+type CallOptions struct {
+	// Timeout: the timeout of the operation if its ctx has no deadline
+	Timeout time.Duration
+	// ReadConsistency: the ReadConsistency of query and search if their params do not set it
+	ReadConsistency ReadConsistency
+	// Retry: the retry count of the http requests if the ctx has no WithRetryCount, nil means not set
+	Retry *int
+	// Headers: the headers added to the http requests, the headers of WithHeader override them
+	Headers map[string]string
+}
+
+// inherit returns the options whose unset ones are got from parent.
+func (o CallOptions) inherit(parent CallOptions) CallOptions {
+	if o.Timeout == 0 {
+		o.Timeout = parent.Timeout
+	}
+	if o.ReadConsistency == "" {
+		o.ReadConsistency = parent.ReadConsistency
+	}
+	if o.Retry == nil {
+		o.Retry = parent.Retry
+	}
+	if len(parent.Headers) != 0 {
+		headers := make(map[string]string, len(parent.Headers)+len(o.Headers))
+		for k, v := range parent.Headers {
+			headers[k] = v
+		}
+		for k, v := range o.Headers {
+			headers[k] = v
+		}
+		o.Headers = headers
+	}
+	return o
+}
+
+// apply returns ctx with the options which are not set by it.
+func (o CallOptions) apply(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Value(retryCountKey{}).(int); !ok && o.Retry != nil {
+		ctx = WithRetryCount(ctx, *o.Retry)
+	}
+	if len(o.Headers) != 0 {
+		header := make(http.Header)
+		for k, v := range o.Headers {
+			header.Set(k, v)
+		}
+		if parent, ok := ctx.Value(headerKey{}).(http.Header); ok {
+			for k, v := range parent {
+				header[k] = v
+			}
+		}
+		ctx = context.WithValue(ctx, headerKey{}, header)
+	}
+	if _, ok := ctx.Deadline(); !ok && o.Timeout > 0 {
+		return context.WithTimeout(ctx, o.Timeout)
+	}
+	return ctx, func() {}
+}
+
+// defaultsClient applies the CallOptions of handles to the requests of client.
+type defaultsClient struct {
+	SdkClient
+	defaults CallOptions
+}
+
+func (c *defaultsClient) Request(ctx context.Context, req, res interface{}) error {
+	ctx, cancel := c.defaults.apply(ctx)
+	defer cancel()
+	return c.SdkClient.Request(ctx, req, res)
+}
+
+func (c *defaultsClient) Options() ClientOption {
+	option := c.SdkClient.Options()
+	if c.defaults.Timeout > 0 {
+		option.Timeout = c.defaults.Timeout
+	}
+	if c.defaults.ReadConsistency != "" {
+		option.ReadConsistency = c.defaults.ReadConsistency
+	}
+	if c.defaults.Retry != nil {
+		option.RetryCount = *c.defaults.Retry
+	}
+	return option
+}
+
+func (c *defaultsClient) schemaCache() (*schemaCache, string) {
+	if provider, ok := c.SdkClient.(interface {
+		schemaCache() (*schemaCache, string)
+	}); ok {
+		return provider.schemaCache()
+	}
+	return nil, ""
+}
+
+// defaultsConn applies the CallOptions of handles to the rpc of RpcClient.
+type defaultsConn struct {
+	grpc.ClientConnInterface
+	defaults CallOptions
+}
+
+func (c *defaultsConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	ctx, cancel := c.defaults.apply(ctx)
+	defer cancel()
+	return c.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+}
+
+// withCallDefaults returns the client applying defaults, which inherits the defaults applied by cli.
+func withCallDefaults(cli SdkClient, defaults CallOptions) *defaultsClient {
+	if parent, ok := cli.(*defaultsClient); ok {
+		return &defaultsClient{SdkClient: parent.SdkClient, defaults: defaults.inherit(parent.defaults)}
+	}
+	return &defaultsClient{SdkClient: cli, defaults: defaults}
+}
+
+// databaseInterfaceOf returns the DatabaseInterface binding the handles to cli.
+func databaseInterfaceOf(cli *defaultsClient) DatabaseInterface {
+	if rpc, ok := cli.SdkClient.(*RpcClient); ok {
+		conn := &defaultsConn{ClientConnInterface: rpc.cc, defaults: cli.defaults}
+		return &rpcImplementerDatabase{cli, &implementerDatabase{SdkClient: cli}, olama.NewSearchEngineClient(conn)}
+	}
+	return &implementerDatabase{SdkClient: cli}
+}
+
+// WithDefaults returns a handle of the database applying defaults to every operation made through it,
+// including the collections got from it. The defaults set by the handle d are inherited.
+func (d *Database) WithDefaults(defaults CallOptions) *Database {
+	var cli SdkClient
+	switch impl := d.CollectionInterface.(type) {
+	case *implementerCollection:
+		cli = impl.SdkClient
+	case *rpcImplementerCollection:
+		cli = impl.SdkClient
+	default:
+		return d
+	}
+	database := databaseInterfaceOf(withCallDefaults(cli, defaults)).Database(d.DatabaseName)
+	database.Info = d.Info
+	return database
+}
+
+// WithDefaults returns a handle of the collection applying defaults to every operation made through it.
+// The defaults set by the handle c or its database are inherited.
+func (c *Collection) WithDefaults(defaults CallOptions) *Collection {
+	var cli SdkClient
+	documents := c.DocumentInterface
+	codecs, hasCodecs := documents.(*fieldCodecDocument)
+	if hasCodecs {
+		documents = codecs.DocumentInterface
+	}
+	switch impl := documents.(type) {
+	case *implementerDocument:
+		cli = impl.SdkClient
+	case *rpcImplementerDocument:
+		cli = impl.SdkClient
+	default:
+		return c
+	}
+	coll := databaseInterfaceOf(withCallDefaults(cli, defaults)).Database(c.DatabaseName).Collection(c.CollectionName)
+	documentImpl, indexImpl := coll.DocumentInterface, coll.IndexInterface
+	*coll = *c
+	coll.DocumentInterface, coll.IndexInterface = documentImpl, indexImpl
+	if hasCodecs {
+		coll.DocumentInterface = &fieldCodecDocument{DocumentInterface: documentImpl, codecs: codecs.codecs}
+	}
+	return coll
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCallOptionsInheritance(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		timeout  time.Duration
+		layer    string
+		attempts int
+		body     struct {
+			ReadConsistency string `json:"readConsistency"`
+		}
+	)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if r.Header.Get("X-Fail") != "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"code":0,"documents":[]}`))
+	}, ClientOption{
+		Timeout:      4 * time.Second,
+		RetryCount:   2,
+		RetryBackoff: time.Millisecond,
+		RequestInterceptors: []RequestInterceptor{func(req *http.Request) error {
+			deadline, _ := req.Context().Deadline()
+			timeout, layer = time.Until(deadline), req.Header.Get("X-Layer")
+			return nil
+		}},
+	})
+
+	retry := 0
+	db := cli.Database("db")
+	dbDefaults := db.WithDefaults(CallOptions{Timeout: 3 * time.Second, ReadConsistency: StrongConsistency,
+		Retry: &retry, Headers: map[string]string{"X-Layer": "database"}})
+	collDefaults := dbDefaults.Collection("coll").WithDefaults(CallOptions{Timeout: 2 * time.Second,
+		Headers: map[string]string{"X-Layer": "collection"}})
+	callCtx, cancel := context.WithTimeout(WithHeader(context.Background(), "X-Layer", "call"), time.Second)
+	defer cancel()
+
+	for _, c := range []struct {
+		name            string
+		coll            *Collection
+		ctx             context.Context
+		params          *QueryDocumentParams
+		timeout         time.Duration
+		readConsistency ReadConsistency
+		layer           string
+	}{
+		{"client", db.Collection("coll"), context.Background(), nil, 4 * time.Second, EventualConsistency, ""},
+		{"database", dbDefaults.Collection("coll"), context.Background(), nil, 3 * time.Second, StrongConsistency, "database"},
+		{"collection", collDefaults, context.Background(), nil, 2 * time.Second, StrongConsistency, "collection"},
+		{"call", collDefaults, callCtx, &QueryDocumentParams{ReadConsistency: EventualConsistency}, time.Second, EventualConsistency, "call"},
+	} {
+		params := []*QueryDocumentParams{}
+		if c.params != nil {
+			params = append(params, c.params)
+		}
+		if _, err := c.coll.Query(c.ctx, []string{"id"}, params...); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if timeout > c.timeout || timeout < c.timeout-time.Second/2 {
+			t.Errorf("%s: expect the timeout %v, got %v", c.name, c.timeout, timeout)
+		}
+		if body.ReadConsistency != string(c.readConsistency) || layer != c.layer {
+			t.Errorf("%s: expect %s and header %q, got %s and %q", c.name, c.readConsistency, c.layer, body.ReadConsistency, layer)
+		}
+	}
+
+	for _, c := range []struct {
+		name     string
+		coll     *Collection
+		attempts int
+	}{
+		{"client", db.Collection("coll"), 3},
+		{"collection", collDefaults, 1},
+	} {
+		attempts = 0
+		if _, err := c.coll.Query(WithHeader(context.Background(), "X-Fail", "1"), []string{"id"}); err == nil {
+			t.Fatalf("%s: expect the request failed", c.name)
+		}
+		if attempts != c.attempts {
+			t.Errorf("%s: expect %d attempts, got %d", c.name, c.attempts, attempts)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
+)
+
+// The features gated by the version of server, see ClientOption.ServerCapabilities.
+const (
+	FeatureHybridSearch   = "hybridSearch"
+	FeatureSparseVector   = "sparseVector"
+	FeatureDeleteByFilter = "deleteByFilter"
+	// FeatureUpdate: the Update keeping the fields not updated, used by UpsertDocumentParams.FieldMask
+	FeatureUpdate = "update"
+)
+
+// DefaultServerCapabilities the min versions of server supporting the features, overridden by
+// ClientOption.ServerCapabilities.
+var DefaultServerCapabilities = map[string]string{
+	FeatureHybridSearch:   "1.4.0",
+	FeatureSparseVector:   "1.4.0",
+	FeatureDeleteByFilter: "1.2.0",
+	FeatureUpdate:         "1.2.0",
+}
+
+// NotSupportedByServerError is returned without sending the request if it uses a feature
+// which is not supported by the version of server.
+type NotSupportedByServerError struct {
+	Feature         string
+	ServerVersion   string
+	RequiredVersion string
+}
+
+func (e *NotSupportedByServerError) Error() string {
+	return fmt.Sprintf("%s is not supported by the server of version %s, which requires %s",
+		e.Feature, e.ServerVersion, e.RequiredVersion)
+}
+
+// Is reports whether the error is ErrNotSupportedByServer.
+func (e *NotSupportedByServerError) Is(target error) bool {
+	return target == ErrNotSupportedByServer
+}
+
+// ErrNotSupportedByServer matches the NotSupportedByServerError.
+var ErrNotSupportedByServer = errors.New("not supported by server")
+
+// versionProbeInterval the interval to probe the version of server again after the probe fails.
+const versionProbeInterval = time.Minute
+
+// serverVersion caches the version of server, probed on the first use. The empty version probed
+// successfully is cached as well, which is unknown and allows all features.
+type serverVersion struct {
+	mu      sync.Mutex
+	version string
+	err     error
+	// probed: the time of the last probe, zero if it is not probed yet
+	probed time.Time
+	// probing: closed when the probe in flight is done, nil if no probe is in flight
+	probing chan struct{}
+}
+
+// get returns the cached version, or probes it without holding the lock. The concurrent gets wait for
+// one probe, the failed probe is cached for versionProbeInterval except that the ctx of probe is done.
+func (v *serverVersion) get(ctx context.Context, probe func(ctx context.Context) (string, error)) (string, error) {
+	for {
+		v.mu.Lock()
+		if !v.probed.IsZero() && (v.err == nil || time.Since(v.probed) < versionProbeInterval) {
+			version, err := v.version, v.err
+			v.mu.Unlock()
+			return version, err
+		}
+		if probing := v.probing; probing != nil {
+			v.mu.Unlock()
+			select {
+			case <-probing:
+				continue
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+		probing := make(chan struct{})
+		v.probing = probing
+		v.mu.Unlock()
+
+		version, err := probe(ctx)
+		v.mu.Lock()
+		if ctx.Err() == nil {
+			v.version, v.err, v.probed = version, err, time.Now()
+		}
+		v.probing = nil
+		close(probing)
+		v.mu.Unlock()
+		return version, err
+	}
+}
+
+// ServerVersion returns the version of server, which is got by ServerInfo on the first call and cached.
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	return c.version.get(ctx, func(ctx context.Context) (string, error) {
+		info, err := serverInfo(ctx, c)
+		if err != nil {
+			return "", errors.Wrap(err, "get server version failed")
+		}
+		return info.Version, nil
+	})
+}
+
+// ServerVersion returns the version of server, which is got by ServerInfo on the first call and cached.
+func (r *RpcClient) ServerVersion(ctx context.Context) (string, error) {
+	return r.httpImplementer.(*Client).ServerVersion(ctx)
+}
+
+// checkFeature returns NotSupportedByServerError if the feature is not supported by the version of server.
+// The version is probed lazily by the first gated request, the request is sent if the version is unknown.
+func (c *Client) checkFeature(ctx context.Context, feature string) error {
+	if feature == "" || c.dryRun != nil {
+		return nil
+	}
+	required, ok := c.option.ServerCapabilities[feature]
+	if !ok {
+		required = DefaultServerCapabilities[feature]
+	}
+	if required == "" {
+		return nil
+	}
+	version, err := c.ServerVersion(ctx)
+	if err != nil || version == "" || compareVersions(version, required) >= 0 {
+		return nil
+	}
+	return &NotSupportedByServerError{Feature: feature, ServerVersion: version, RequiredVersion: required}
+}
+
+// featureSupported reports whether the server of cli supports the feature, which is true if the version
+// is unknown, and false for the SdkClient other than Client and RpcClient.
+func featureSupported(ctx context.Context, cli SdkClient, feature string) bool {
+	for {
+		switch c := cli.(type) {
+		case *Client:
+			return c.checkFeature(ctx, feature) == nil
+		case *RpcClient:
+			return c.httpImplementer.(*Client).checkFeature(ctx, feature) == nil
+		case *defaultsClient:
+			cli = c.SdkClient
+		default:
+			return false
+		}
+	}
+}
+
+// requestFeature returns the gated feature used by the http request.
+func requestFeature(req interface{}) string {
+	switch r := req.(type) {
+	case *document.HybridSearchReq:
+		return FeatureHybridSearch
+	case *document.DeleteReq:
+		if r.Query != nil && r.Query.Filter != "" {
+			return FeatureDeleteByFilter
+		}
+	case *document.UpsertReq:
+		for _, doc := range r.Documents {
+			if len(doc.SparseVector) != 0 {
+				return FeatureSparseVector
+			}
+		}
+	}
+	return ""
+}
+
+// rpcFeature returns the gated feature used by the rpc.
+func rpcFeature(method string, req interface{}) string {
+	switch r := req.(type) {
+	case *olama.SearchRequest:
+		if strings.HasSuffix(method, "/hybrid_search") {
+			return FeatureHybridSearch
+		}
+	case *olama.DeleteRequest:
+		if r.Query != nil && r.Query.Filter != "" {
+			return FeatureDeleteByFilter
+		}
+	case *olama.UpsertRequest:
+		for _, doc := range r.Documents {
+			if len(doc.SparseVector) != 0 {
+				return FeatureSparseVector
+			}
+		}
+	}
+	return ""
+}
+
+// compareVersions compares the dotted numeric versions such as v1.4.2, the suffix after - or + is ignored.
+// The unknown version is treated as the latest one.
+func compareVersions(a, b string) int {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return 1
+	case !okB:
+		return -1
+	}
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, s := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/collection"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
+)
+
+func TestServerCapabilities(t *testing.T) {
+	var paths []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/server/info" {
+			w.Write([]byte(`{"code":0,"info":{"version":"v1.3.5-rc1","uptime":10}}`))
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}
+	cli := newTestClient(t, handler, ClientOption{})
+	ctx := context.Background()
+
+	if err := cli.Request(ctx, &collection.DescribeReq{Database: "db", Collection: "coll"}, new(collection.DescribeRes)); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("expect the version not probed by the requests not gated, got %q", paths)
+	}
+
+	for i := 0; i < 2; i++ {
+		err := cli.Request(ctx, &document.HybridSearchReq{Database: "db", Collection: "coll"}, new(document.SearchRes))
+		var notSupported *NotSupportedByServerError
+		if !errors.Is(err, ErrNotSupportedByServer) || !errors.As(err, &notSupported) ||
+			notSupported.Feature != FeatureHybridSearch || notSupported.ServerVersion != "v1.3.5-rc1" || notSupported.RequiredVersion != "1.4.0" {
+			t.Errorf("expect hybrid search not supported, got %v", err)
+		}
+	}
+	err := cli.Request(ctx, &document.DeleteReq{Database: "db", Collection: "coll", Query: &document.QueryCond{Filter: `a = 1`}}, new(document.DeleteRes))
+	if err != nil {
+		t.Errorf("expect delete by filter supported, got %v", err)
+	}
+	if len(paths) != 3 || paths[1] != "/server/info" || paths[2] != "/document/delete" {
+		t.Errorf("expect the version probed once, got %q", paths)
+	}
+	if version, err := cli.ServerVersion(ctx); err != nil || version != "v1.3.5-rc1" {
+		t.Errorf("unexpected server version %s, %v", version, err)
+	}
+
+	fork := newTestClient(t, handler, ClientOption{ServerCapabilities: map[string]string{FeatureHybridSearch: "1.3", FeatureDeleteByFilter: "2.0"}})
+	if err = fork.Request(ctx, &document.HybridSearchReq{Database: "db", Collection: "coll"}, new(document.SearchRes)); err != nil {
+		t.Errorf("expect hybrid search supported by the overridden version, got %v", err)
+	}
+	err = fork.Request(ctx, &document.DeleteReq{Database: "db", Collection: "coll", Query: &document.QueryCond{Filter: `a = 1`}}, new(document.DeleteRes))
+	if !errors.Is(err, ErrNotSupportedByServer) {
+		t.Errorf("expect delete by filter not supported by the overridden version, got %v", err)
+	}
+}
+
+func TestServerVersionEmpty(t *testing.T) {
+	var probes int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/server/info" {
+			atomic.AddInt32(&probes, 1)
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte(`{"code":0,"info":{}}`))
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := cli.Request(context.Background(), &document.HybridSearchReq{Database: "db", Collection: "coll"}, new(document.SearchRes))
+			if err != nil {
+				t.Errorf("expect hybrid search allowed by the unknown version, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&probes); n != 1 {
+		t.Errorf("expect the empty version probed once and cached, got %d probes", n)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, c := range []struct {
+		a, b   string
+		expect int
+	}{
+		{"1.4.0", "1.4", 0},
+		{"v1.4.2", "1.4.10", -1},
+		{"1.10.0", "1.9.9", 1},
+		{"1.4.0-beta", "1.4.0", 0},
+		{"", "1.4.0", 1},
+		{"custom", "1.4.0", 1},
+	} {
+		if got := compareVersions(c.a, c.b); got != c.expect {
+			t.Errorf("compare %q with %q: expect %d, got %d", c.a, c.b, c.expect, got)
+		}
+	}
+}
+
+func TestRpcFeature(t *testing.T) {
+	if feature := rpcFeature("/olama.SearchEngine/hybrid_search", new(olama.SearchRequest)); feature != FeatureHybridSearch {
+		t.Errorf("expect hybrid search gated, got %q", feature)
+	}
+	if feature := rpcFeature("/olama.SearchEngine/search", new(olama.SearchRequest)); feature != "" {
+		t.Errorf("expect search not gated, got %q", feature)
+	}
+}
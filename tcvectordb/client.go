@@ -20,18 +20,29 @@ package tcvectordb
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	crand "crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"math/rand"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 
 	"github.com/pkg/errors"
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
 )
 
 // SdkClient the http client interface
@@ -44,28 +55,165 @@ type SdkClient interface {
 }
 
 type ClientOption struct {
-	// Timeout: default 5s
+	// Timeout: default 5s, only used when the ctx of request has no deadline
 	Timeout time.Duration
 	// MaxIdldConnPerHost: default 2
 	MaxIdldConnPerHost int
 	// IdleConnTimeout: default 0 means no limit
 	IdleConnTimeout time.Duration
+	// MaxIdleConns: the max idle connections of all hosts, default 0 means no limit
+	MaxIdleConns int
+	// MaxConnsPerHost: the max connections of a host including the active ones, default 0 means no limit.
+	// The requests wait for a connection when it is exceeded.
+	MaxConnsPerHost int
+	// ForceAttemptHTTP2: try HTTP/2 for the https urls, which is disabled by the custom TLS config otherwise
+	ForceAttemptHTTP2 bool
+	// DialTimeout: the timeout of establishing a connection, default 30s
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout: the timeout of the TLS handshake, default 10s
+	TLSHandshakeTimeout time.Duration
 	// ReadConsistency: default: EventualConsistency
 	ReadConsistency ReadConsistency
-	// Transport: default: http.Transport
+	// Transport: default: http.Transport, the TLS options below are ignored if it is set
 	Transport http.RoundTripper
+	// TLSConfig: the tls config of https urls, CAFile, ClientCertFile and InsecureSkipVerify are applied to a clone of it.
+	// The server certificate is verified by default.
+	TLSConfig *tls.Config
+	// CAFile: the PEM file of the CA certificates to verify the server certificate, default the system CAs
+	CAFile string
+	// ClientCertFile, ClientKeyFile: the PEM files of the client certificate and key for mutual TLS,
+	// ClientKeyFile defaults to ClientCertFile when the key is in the same file
+	ClientCertFile string
+	ClientKeyFile  string
+	// InsecureSkipVerify: skip verifying the server certificate, only for testing
+	InsecureSkipVerify bool
+	// RetryCount: max retry times of a failed request, default 0 means no retry.
+	// Query, search, describe and list requests are retried on network errors and 5xx responses,
+	// the others are only retried when the connection to server could not be established.
+	RetryCount int
+	// RetryBackoff: backoff before the first retry, doubled for every next retry, default 100ms
+	RetryBackoff time.Duration
+	// RetryMaxBackoff: max backoff between two retries, default 5s
+	RetryMaxBackoff time.Duration
+	// EndpointFailureThreshold: an endpoint is skipped after failing so many times in a row, default 3.
+	// Only used by the client with multiple endpoints.
+	EndpointFailureThreshold int
+	// EndpointProbeInterval: the skipped endpoint is probed again after the interval, default 30s
+	EndpointProbeInterval time.Duration
+	// DefaultHeaders: the headers added to every request, the headers of WithHeader override them
+	DefaultHeaders map[string]string
+	// RequestInterceptors: called in order after the request is built and before it is sent,
+	// the request is not sent if any of them returns an error.
+	RequestInterceptors []RequestInterceptor
+	// EnableCompression: gzip the request bodies not smaller than CompressionThreshold, and accept gzip responses.
+	// The client falls back to uncompressed requests if the server rejects a compressed one with 400 or 415.
+	EnableCompression bool
+	// CompressionThreshold: the min size of request body to compress, default 1KB
+	CompressionThreshold int
+	// Logger: log every request with its method, path, duration, status and size, the headers are never logged.
+	// Debug(true) uses a logger writing to the std log if it is not set.
+	Logger Logger
+	// LogBodies: log the request and response bodies too, always true in debug mode
+	LogBodies bool
+	// RateLimit: limit the requests sent by client, the request waits for the limit until its ctx is done.
+	// Default no limit.
+	RateLimit *RateLimit
+	// ReadRateLimit, WriteRateLimit: the separate limits of the read (query, search, describe, list and count)
+	// and the other requests, RateLimit is used if they are not set
+	ReadRateLimit  *RateLimit
+	WriteRateLimit *RateLimit
+	// EagerConnect: NewClient warms up MaxIdldConnPerHost connections and verifies the credentials,
+	// it fails if the server is unreachable or the credentials are invalid
+	EagerConnect bool
+	// Tracer: traces each request, such as the OpenTelemetry one of module tcvdbotel, no tracing if nil
+	Tracer Tracer
+	// StrictWarnings: return WarningError if the server responds a write request with warning,
+	// such as the documents upserted but not indexed
+	StrictWarnings bool
+	// StrictOutputFields: check the OutputFields of query and search with the described or cached schema of
+	// collection, an UnknownOutputFieldError is returned if a field is neither an index nor the embedding field.
+	// It is not enabled by default, as the fields without index are valid outputs unknown by the schema.
+	StrictOutputFields bool
+	// AutoNormalize: normalize the vectors of Upsert and Search to the unit L2 norm if the metric of collection
+	// is COSINE, known from its described or cached schema. The zero vectors are sent unchanged with ZeroVectorError.
+	AutoNormalize bool
+	// StreamRequests: encode the bodies of the requests implementing StreamMarshaler, such as upsert, while sending
+	// them in chunks, instead of buffering the whole bodies. Each retry encodes the request again. The streamed
+	// bodies are not compressed nor checked by MaxRequestBytes, and only their first 4KB is logged.
+	// It is ignored with a Codec other than JSONCodec.
+	StreamRequests bool
+	// ServerCapabilities: the min versions of server supporting the features, such as FeatureHybridSearch,
+	// override DefaultServerCapabilities for the forks of server. The empty version disables the check of feature.
+	// The version of server is probed by the first request using a gated feature.
+	ServerCapabilities map[string]string
+	// MaxRequestBytes: the max size of request body, the larger request is rejected with ErrRequestTooLarge
+	// before sending, and the batches of upsert are split to fit it. Default 100MB, -1 means no limit.
+	MaxRequestBytes int
+	// CredentialTTL: the time to cache the credentials of NewClientWithCredentials, default 1 minute
+	CredentialTTL time.Duration
+	// MaxShardNum, MaxReplicaNum: the upper bounds of the shardNum and replicasNum of CreateCollection,
+	// which are checked before sending, default 0 means no limit
+	MaxShardNum   uint32
+	MaxReplicaNum uint32
+	// CollectTimings: collect the DNS, connect, TLS and first byte timings of requests by httptrace,
+	// which are set in the Timing of results and RequestInfo
+	CollectTimings bool
+	// OnRequestDone: called after every request with its operation, duration, sizes, status and error,
+	// such as to observe the latency histograms. It is called synchronously, so it should be fast.
+	OnRequestDone func(info RequestInfo)
+	// CacheCollectionSchema: the handles of Database.Collection describe the collection on their first request,
+	// and share the described schema to check the requests, until CollectionSchemaTTL or the check fails.
+	CacheCollectionSchema bool
+	// CollectionSchemaTTL: the time to cache the described schema, default 1 minute
+	CollectionSchemaTTL time.Duration
+	// Codec: encode the request bodies and decode the response bodies, default JSONCodec.
+	// The client falls back to json if the server rejects the content type with 415 or 406.
+	Codec Codec
+	// DryRun: record the requests instead of sending them, see DryRunOption. It is only supported by NewClient.
+	DryRun *DryRunOption
 }
+
+// RequestInterceptor modify or veto the http request before it is sent.
+type RequestInterceptor func(req *http.Request) error
 type Client struct {
+	// timeout is the first field to be 64-bit aligned for the atomic access on 32-bit platforms,
+	// it is accessed atomically since it may be changed by WithTimeout when the client is shared by goroutines
+	timeout int64
+
 	DatabaseInterface
 	FlatInterface
 	FlatIndexInterface
+	UserInterface
+	BackupInterface
+
+	cli       *http.Client
+	url       string
+	endpoints *endpointPool
+	// credentials: the username and api key of requests
+	credentials *credentialCache
+	option      ClientOption
+	// namespace: the prefix of database names set by WithNamespace
+	namespace string
+	stats     *clientStats
+	lifecycle *clientLifecycle
+	// schemas: the described collections cached by CacheCollectionSchema, nil if it is not enabled
+	schemas *schemaCache
+	// version: the version of server probed by the requests of gated features, shared by the clones of client
+	version *serverVersion
+	// debug is accessed atomically, the same as timeout
+	debug int32
+	// tlsIgnored is set when the TLS options are ignored because of the custom Transport
+	tlsIgnored bool
 
-	cli      *http.Client
-	url      string
-	username string
-	key      string
-	option   ClientOption
-	debug    bool
+	// compressionRejected is set once the server rejects a compressed request
+	compressionRejected int32
+	// codecRejected is set once the server rejects the content type of ClientOption.Codec
+	codecRejected int32
+	// dryRun: records the requests if ClientOption.DryRun is set, shared by the clones of client
+	dryRun *dryRunRecorder
+
+	readLimiter  *rateLimiter
+	writeLimiter *rateLimiter
 }
 
 type CommmonResponse struct {
@@ -73,150 +221,996 @@ type CommmonResponse struct {
 	Code int32 `json:"code,omitempty"`
 	// Msg: response msg
 	Msg string `json:"msg,omitempty"`
+	// Warning: the warning of a succeeded request
+	Warning string `json:"warning,omitempty"`
+	// RequestId: the request id of server, used for the support tickets
+	RequestId string `json:"requestId,omitempty"`
 }
 
 var defaultOption = ClientOption{
-	Timeout:            time.Second * 5,
-	MaxIdldConnPerHost: 2,
-	IdleConnTimeout:    time.Minute,
-	ReadConsistency:    api.EventualConsistency,
+	Timeout:             time.Second * 5,
+	MaxIdldConnPerHost:  2,
+	IdleConnTimeout:     time.Minute,
+	DialTimeout:         time.Second * 30,
+	TLSHandshakeTimeout: time.Second * 10,
+	ReadConsistency:     api.EventualConsistency,
+	RetryBackoff:        time.Millisecond * 100,
+	RetryMaxBackoff:     time.Second * 5,
+
+	EndpointFailureThreshold: 3,
+	EndpointProbeInterval:    time.Second * 30,
+	CompressionThreshold:     1024,
+	MaxRequestBytes:          100 << 20,
 }
 
 func NewClient(url, username, key string, option *ClientOption) (*Client, error) {
 	if option == nil {
 		option = &defaultOption
 	}
-	return newClient(url, username, key, optionMerge(*option))
+	return newClient([]string{url}, StaticCredentials{Username: username, Key: key}, optionMerge(*option))
+}
+
+// NewClientWithCredentials new http client whose username and api key are got from provider,
+// they are cached for ClientOption.CredentialTTL, and fetched again once if the server rejects them.
+func NewClientWithCredentials(url string, provider CredentialProvider, option *ClientOption) (*Client, error) {
+	if provider == nil {
+		return nil, errors.New("credential provider is nil")
+	}
+	if option == nil {
+		option = &defaultOption
+	}
+	return newClient([]string{url}, provider, optionMerge(*option))
+}
+
+// NewClientWithEndpoints new http client with multiple urls of the same vectordb instance.
+// The requests are sent to the urls by round-robin, the url failing EndpointFailureThreshold times
+// in a row is skipped for EndpointProbeInterval.
+func NewClientWithEndpoints(urls []string, username, key string, option *ClientOption) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("urls is empty")
+	}
+	if option == nil {
+		option = &defaultOption
+	}
+	return newClient(urls, StaticCredentials{Username: username, Key: key}, optionMerge(*option))
 }
 
-// newClient new http client with url, username and api key
-func newClient(url, username, key string, option ClientOption) (*Client, error) {
-	if !strings.HasPrefix(url, "http") {
-		return nil, errors.Errorf("invalid url param with: %s", url)
+// newClient new http client with urls and the provider of username and api key
+func newClient(urls []string, provider CredentialProvider, option ClientOption) (*Client, error) {
+	for _, url := range urls {
+		if !strings.HasPrefix(url, "http") {
+			return nil, errors.Errorf("invalid url param with: %s", url)
+		}
 	}
-	if username == "" || key == "" {
+	if static, ok := provider.(StaticCredentials); ok && (static.Username == "" || static.Key == "") {
 		return nil, errors.New("username or key is empty")
 	}
 
 	cli := new(Client)
-	cli.url = url
-	cli.username = username
-	cli.key = key
-	cli.debug = false
-
+	cli.url = urls[0]
+	cli.endpoints = newEndpointPool(urls)
 	cli.option = optionMerge(option)
+	cli.credentials = newCredentialCache(provider, cli.option.CredentialTTL)
+	cli.stats = newClientStats()
+	cli.lifecycle = new(clientLifecycle)
+	if cli.option.CacheCollectionSchema {
+		cli.schemas = newSchemaCache(cli.option.CollectionSchemaTTL)
+	}
+	cli.dryRun = newDryRunRecorder(cli.option.DryRun)
+	cli.version = new(serverVersion)
+	cli.timeout = int64(cli.option.Timeout)
+	cli.readLimiter = newRateLimiter(option.RateLimit)
+	cli.writeLimiter = cli.readLimiter
+	if option.ReadRateLimit != nil {
+		cli.readLimiter = newRateLimiter(option.ReadRateLimit)
+	}
+	if option.WriteRateLimit != nil {
+		cli.writeLimiter = newRateLimiter(option.WriteRateLimit)
+	}
 
 	cli.cli = new(http.Client)
 	if option.Transport != nil {
 		cli.cli.Transport = option.Transport
+		cli.tlsIgnored = option.TLSConfig != nil || option.CAFile != "" || option.ClientCertFile != "" || option.InsecureSkipVerify
+		if cli.tlsIgnored {
+			cli.warnTLSIgnored()
+		}
 	} else {
+		tlsConfig, err := newTLSConfig(option)
+		if err != nil {
+			return nil, err
+		}
 		cli.cli.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
+			DialContext: (&net.Dialer{
+				Timeout:   cli.option.DialTimeout,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSClientConfig:     tlsConfig,
+			TLSHandshakeTimeout: cli.option.TLSHandshakeTimeout,
+			ForceAttemptHTTP2:   cli.option.ForceAttemptHTTP2,
+			MaxIdleConns:        cli.option.MaxIdleConns,
 			MaxIdleConnsPerHost: cli.option.MaxIdldConnPerHost,
+			MaxConnsPerHost:     cli.option.MaxConnsPerHost,
 			IdleConnTimeout:     cli.option.IdleConnTimeout,
 		}
 	}
-	cli.cli.Timeout = cli.option.Timeout
 
+	cli.bindInterfaces()
+
+	if option.EagerConnect && option.DryRun == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		if timeout := cli.requestTimeout(); timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		defer cancel()
+		if err := cli.WarmUp(ctx, cli.option.MaxIdldConnPerHost); err != nil {
+			cli.Close()
+			return nil, errors.Wrap(err, "connect to vectordb failed")
+		}
+	}
+	return cli, nil
+}
+
+func (c *Client) bindInterfaces() {
 	databaseImpl := new(implementerDatabase)
-	databaseImpl.SdkClient = cli
+	databaseImpl.SdkClient = c
 	flatImpl := new(implementerFlatDocument)
-	flatImpl.SdkClient = cli
+	flatImpl.SdkClient = c
 	flatIndexImpl := new(implementerFlatIndex)
-	flatIndexImpl.SdkClient = cli
+	flatIndexImpl.SdkClient = c
 
-	cli.DatabaseInterface = databaseImpl
-	cli.FlatInterface = flatImpl
-	cli.FlatIndexInterface = flatIndexImpl
-	return cli, nil
+	c.DatabaseInterface = databaseImpl
+	c.FlatInterface = flatImpl
+	c.FlatIndexInterface = flatIndexImpl
+	c.UserInterface = &implementerUser{SdkClient: c}
+	c.BackupInterface = &implementerBackup{SdkClient: c}
+}
+
+// ScopedOption the options of the client returned by Client.WithOptions,
+// the zero value of each field keeps the option of the origin client.
+type ScopedOption struct {
+	// Timeout: the timeout of the requests whose ctx has no deadline
+	Timeout time.Duration
+	// Debug: show the request and response info, nil keeps the debug mode of the origin client
+	Debug *bool
+	// ReadConsistency: the default ReadConsistency of query and search
+	ReadConsistency ReadConsistency
+}
+
+// WithOptions return a shallow copy of the client with the scoped options. The copy shares the connections,
+// endpoints and rate limiters with the origin client, and changing its options does not affect the origin one.
+func (c *Client) WithOptions(option ScopedOption) *Client {
+	clone := &Client{
+		cli:                 c.cli,
+		url:                 c.url,
+		endpoints:           c.endpoints,
+		credentials:         c.credentials,
+		namespace:           c.namespace,
+		stats:               c.stats,
+		lifecycle:           c.lifecycle,
+		schemas:             c.schemas,
+		version:             c.version,
+		option:              c.option,
+		timeout:             atomic.LoadInt64(&c.timeout),
+		debug:               atomic.LoadInt32(&c.debug),
+		tlsIgnored:          c.tlsIgnored,
+		compressionRejected: atomic.LoadInt32(&c.compressionRejected),
+		codecRejected:       atomic.LoadInt32(&c.codecRejected),
+		dryRun:              c.dryRun,
+		readLimiter:         c.readLimiter,
+		writeLimiter:        c.writeLimiter,
+	}
+	if option.Timeout > 0 {
+		clone.timeout = int64(option.Timeout)
+	}
+	if option.Debug != nil {
+		clone.Debug(*option.Debug)
+	}
+	if option.ReadConsistency != "" {
+		clone.option.ReadConsistency = option.ReadConsistency
+	}
+	clone.bindInterfaces()
+	return clone
+}
+
+// Ping list the databases to verify the server is reachable and the credentials are valid.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.ListDatabase(ctx)
+	return err
+}
+
+// WarmUp send conns pings at the same time, so that conns connections are established and kept idle
+// for the following requests, up to MaxIdldConnPerHost.
+func (c *Client) WarmUp(ctx context.Context, conns int) error {
+	if conns < 1 {
+		conns = 1
+	}
+	errs := make(chan error, conns)
+	for n := 0; n < conns; n++ {
+		go func() {
+			errs <- c.Ping(ctx)
+		}()
+	}
+	var err error
+	for n := 0; n < conns; n++ {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Do sends the request of method and path with reqBody encoded as json, and decodes the response into
+// respOut, which could be nil. It is used for the api without typed requests, the auth, retry, timeout,
+// logging and errors are as same as the typed requests, but the database of reqBody is not namespaced.
+func (c *Client) Do(ctx context.Context, method, path string, reqBody interface{}, respOut interface{}) error {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.Request(ctx, &rawRequest{method: method, path: path, body: reqBody}, respOut)
+}
+
+// rawRequest the request of Do, whose method and path are given instead of the tags of api.Meta.
+type rawRequest struct {
+	method string
+	path   string
+	body   interface{}
+}
+
+func (r *rawRequest) MarshalJSON() ([]byte, error) {
+	if r.body == nil {
+		return []byte("{}"), nil
+	}
+	buf := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(r.body); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
 }
 
 // Request do request for client
 func (c *Client) Request(ctx context.Context, req, res interface{}) error {
+	if err := c.lifecycle.begin(); err != nil {
+		return err
+	}
+	defer c.lifecycle.end()
 	var (
 		method = api.Method(req)
 		path   = api.Path(req)
 	)
-	reqBody := bytes.NewBuffer(nil)
-	encoder := json.NewEncoder(reqBody)
-	encoder.SetEscapeHTML(false)
-	err := encoder.Encode(req)
-	if err != nil {
+	raw, isRaw := req.(*rawRequest)
+	if isRaw {
+		method, path = raw.method, raw.path
+	}
+	if c.namespace != "" {
+		var err error
+		if req, err = c.namespaceRequest(req); err != nil {
+			return err
+		}
+		defer c.namespaceResponse(res)
+	}
+	if err := c.checkFeature(ctx, requestFeature(req)); err != nil {
+		return err
+	}
+	if c.dryRun.intercepts(path) {
+		return c.dryRun.record(method, path, req)
+	}
+	codec := c.codec()
+	if isRaw {
+		// the body of raw request could be encoded only as json
+		codec = JSONCodec{}
+	}
+	var (
+		reqBody []byte
+		err     error
+	)
+	_, isJSON := codec.(JSONCodec)
+	if stream, ok := req.(StreamMarshaler); ok && isJSON && c.option.StreamRequests {
+		ctx = context.WithValue(ctx, streamBodyKey{}, stream)
+	} else if reqBody, err = codec.Marshal(req); err != nil {
 		return fmt.Errorf("%w, %#v", err, req)
 	}
+	if !isJSON {
+		ctx = context.WithValue(ctx, codecKey{}, codec)
+	}
 
-	request, err := http.NewRequest(strings.ToUpper(method), c.url+path, reqBody)
-	if err != nil {
+	if _, ok := ctx.Value(requestIDKey{}).(string); !ok {
+		ctx = WithRequestID(ctx, newRequestID())
+	}
+
+	documents := 0
+	if upsert, ok := req.(*document.UpsertReq); ok {
+		documents = len(upsert.Documents)
+	}
+	if err = checkRequestSize(c.option, path, len(reqBody), documents); err != nil {
 		return err
 	}
+	send := func(ctx context.Context, span *RequestSpan) error {
+		err := c.retry(ctx, method, path, reqBody, res, span)
+		if _, ok := codec.(JSONCodec); ok || !codecRejected(err) {
+			return err
+		}
+		// the server does not support the codec, fallback to json for this and later requests
+		atomic.StoreInt32(&c.codecRejected, 1)
+		if logger, _ := clientLogger(c.option, c.isDebug()); logger != nil {
+			logger.Warn("codec is rejected, fallback to json", "path", path, "contentType", codec.ContentType())
+		}
+		if reqBody, err = (JSONCodec{}).Marshal(req); err != nil {
+			return fmt.Errorf("%w, %#v", err, req)
+		}
+		if err = checkRequestSize(c.option, path, len(reqBody), documents); err != nil {
+			return err
+		}
+		return c.retry(context.WithValue(ctx, codecKey{}, JSONCodec{}), method, path, reqBody, res, span)
+	}
 
-	if c.debug {
-		log.Printf("[DEBUG] REQUEST, Method: %s, Path: %s, Body: %s", method, path, strings.TrimSpace(reqBody.String()))
+	timing, _ := ctx.Value(timingKey{}).(*RequestTiming)
+	if c.option.OnRequestDone != nil && timing == nil {
+		ctx, timing = withRequestTiming(ctx)
+	}
+	if c.option.Tracer == nil && c.option.OnRequestDone == nil && timing == nil {
+		return send(ctx, nil)
 	}
+	var (
+		span *RequestSpan
+		end  func(span *RequestSpan)
+	)
+	if c.option.Tracer != nil || c.option.OnRequestDone != nil {
+		span = newRequestSpan(method, path, req, len(reqBody))
+	}
+	if c.option.Tracer != nil {
+		ctx, end = c.option.Tracer.Start(ctx, span)
+	}
+	start := time.Now()
+	err = send(ctx, span)
+	duration := time.Since(start)
+	if timing != nil {
+		timing.Total = duration
+	}
+	if span != nil {
+		span.finish(err)
+	}
+	if end != nil {
+		end(span)
+	}
+	if c.option.OnRequestDone != nil {
+		c.option.OnRequestDone(RequestInfo{RequestSpan: *span, Duration: duration, Timing: *timing})
+	}
+	return err
+}
 
-	auth := fmt.Sprintf("Bearer account=%s&api_key=%s", c.username, c.key)
+// retry sends the request, and resends it on the retryable errors.
+func (c *Client) retry(ctx context.Context, method, path string, body []byte, res interface{}, span *RequestSpan) error {
+	var err error
+	retryCount := c.option.RetryCount
+	if n, ok := ctx.Value(retryCountKey{}).(int); ok {
+		retryCount = n
+	}
+	limiter := c.writeLimiter
+	if idempotentActions[path[strings.LastIndex(path, "/")+1:]] {
+		limiter = c.readLimiter
+	}
+	attempt := 0
+	refreshed := false
+	for {
+		if err = limiter.wait(ctx); err != nil {
+			return errors.Wrap(err, "wait for rate limit failed")
+		}
+		atomic.AddInt64(&c.stats.inFlight, 1)
+		err = c.do(httptrace.WithClientTrace(ctx, c.stats.trace), method, path, body, res, span)
+		atomic.AddInt64(&c.stats.inFlight, -1)
+		atomic.AddInt64(&c.stats.requests, 1)
+		if err != nil {
+			atomic.AddInt64(&c.stats.errors, 1)
+		}
+		if !refreshed && isCredentialError(err) && c.credentials.invalidate() {
+			// the key may be rotated, resend once with the fresh credentials
+			refreshed = true
+			continue
+		}
+		if err == nil || attempt >= retryCount || !retryable(ctx, path, err) {
+			break
+		}
+		timer := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(err, "request failed after %d attempts, %v", attempt+1, ctx.Err())
+		case <-timer.C:
+		}
+		attempt++
+		atomic.AddInt64(&c.stats.retries, 1)
+	}
+	if err != nil && attempt > 0 {
+		return errors.Wrapf(err, "request failed after %d attempts", attempt+1)
+	}
+	return err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body []byte, res interface{}, span *RequestSpan) error {
+	// the client Timeout is only the default when the ctx has no deadline, and 0 means no timeout
+	if _, ok := ctx.Deadline(); !ok && c.requestTimeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout())
+		defer cancel()
+	}
+	ep := c.endpoints.pick()
+	var reqBody io.Reader = bytes.NewReader(body)
+	stream, _ := ctx.Value(streamBodyKey{}).(StreamMarshaler)
+	compressed := c.option.EnableCompression && stream == nil && len(body) >= c.option.CompressionThreshold &&
+		atomic.LoadInt32(&c.compressionRejected) == 0 && ctx.Value(uncompressedKey{}) == nil
+	if compressed {
+		data, err := gzipBytes(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	var (
+		streamReader *io.PipeReader
+		streamWriter *io.PipeWriter
+		streamed     *streamLog
+	)
+	if stream != nil {
+		// the body is encoded while it is sent in chunks, the length is unknown
+		streamReader, streamWriter = io.Pipe()
+		streamed = new(streamLog)
+		reqBody = streamReader
+	}
+	request, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), ep.url+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	username, key, err := c.credentials.get(ctx)
+	if err != nil {
+		return errors.Wrap(err, "get credentials failed")
+	}
+	auth := fmt.Sprintf("Bearer account=%s&api_key=%s", username, key)
 	request.Header.Add("Authorization", auth)
-	request.Header.Add("Content-Type", "application/json")
+	codec := requestCodec(ctx)
+	request.Header.Add("Content-Type", codec.ContentType())
+	if _, ok := codec.(JSONCodec); !ok {
+		request.Header.Set("Accept", codec.ContentType()+", application/json;q=0.9")
+	}
 	request.Header.Add("Sdk-Version", SDKVersion)
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	if requestID != "" {
+		request.Header.Set(RequestIDHeader, requestID)
+	}
+	if c.option.EnableCompression {
+		request.Header.Set("Accept-Encoding", "gzip")
+	}
+	if compressed {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range c.option.DefaultHeaders {
+		request.Header.Set(k, v)
+	}
+	if header, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		for k, v := range header {
+			request.Header[k] = v
+		}
+	}
+	for _, interceptor := range c.option.RequestInterceptors {
+		if err = interceptor(request); err != nil {
+			return fmt.Errorf("request intercepted: %w", err)
+		}
+	}
+	timing, _ := ctx.Value(timingKey{}).(*RequestTiming)
+	var attempt *attemptTiming
+	if timing != nil {
+		timing.Attempts++
+		if c.option.CollectTimings {
+			attempt = newAttemptTiming()
+			request = request.WithContext(httptrace.WithClientTrace(request.Context(), attempt.trace()))
+		}
+	}
+	start := time.Now()
+	var encoded chan struct{}
+	if stream != nil {
+		encoded = make(chan struct{})
+		go func() {
+			defer close(encoded)
+			streamWriter.CloseWithError(stream.MarshalStream(io.MultiWriter(streamWriter, streamed)))
+		}()
+	}
 	response, err := c.cli.Do(request)
+	if stream != nil {
+		// stop the encoding if the body is not read through, such as the request failed
+		streamReader.Close()
+		<-encoded
+		body = streamed.head
+	}
+	if attempt != nil {
+		attempt.finish(timing)
+	}
+	if timing != nil {
+		timing.Server = 0
+		if err == nil {
+			timing.Server = serverTiming(response.Header)
+		}
+	}
+	if err == nil && compressed {
+		if rejected, permanent := encodingRejected(response, codec); rejected {
+			response.Body.Close()
+			if permanent {
+				atomic.StoreInt32(&c.compressionRejected, 1)
+			}
+			if logger, _ := clientLogger(c.option, c.isDebug()); logger != nil {
+				logger.Warn("compressed request is rejected, fallback to uncompressed", "path", path, "status", response.StatusCode)
+			}
+			return c.do(context.WithValue(ctx, uncompressedKey{}, true), method, path, body, res, span)
+		}
+	}
+	var (
+		status       int
+		responseBody []byte
+	)
+	var serverRequestID string
+	if err == nil {
+		status = response.StatusCode
+		responseBody, serverRequestID, err = c.handleResponse(ctx, response, res)
+	}
+	if apiErr, ok := asAPIError(err); ok {
+		apiErr.RequestID, apiErr.ServerRequestID = requestID, serverRequestID
+	}
+	if span != nil {
+		span.Status, span.ResponseSize = status, len(responseBody)
+	}
+	requestSize := len(body)
+	if streamed != nil {
+		requestSize = streamed.size
+	}
+	c.logRequest(ep.url, method, path, [2]string{requestID, serverRequestID}, body, requestSize, responseBody, status, time.Since(start), err)
+	if len(c.endpoints.endpoints) > 1 {
+		ep.report(endpointFailed(err), c.option.EndpointFailureThreshold, c.option.EndpointProbeInterval)
+	}
+	return err
+}
+
+// logRequest logs the request, requestIDs are the ids of client and server.
+// The requestBody could be the head of a streamed body of requestSize.
+func (c *Client) logRequest(endpoint, method, path string, requestIDs [2]string, requestBody []byte, requestSize int,
+	responseBody []byte, status int, duration time.Duration, err error) {
+	logger, verbose := clientLogger(c.option, c.isDebug())
+	if logger == nil {
+		return
+	}
+	kvs := []interface{}{"endpoint", endpoint, "method", method, "path", path, "status", status,
+		"duration", duration, "requestSize", requestSize}
+	if requestIDs[0] != "" {
+		kvs = append(kvs, "requestId", requestIDs[0])
+	}
+	if requestIDs[1] != "" {
+		kvs = append(kvs, "serverRequestId", requestIDs[1])
+	}
+	if apiErr, ok := asAPIError(err); ok {
+		kvs = append(kvs, "code", apiErr.Code, "msg", apiErr.Message)
+	}
+	var commenRes CommmonResponse
+	if json.Unmarshal(responseBody, &commenRes) == nil && commenRes.Warning != "" {
+		kvs = append(kvs, "warning", commenRes.Warning)
+	}
+	if verbose {
+		body := strings.TrimSpace(string(requestBody))
+		if len(requestBody) < requestSize {
+			body += "..."
+		}
+		if strings.HasPrefix(path, "/user/") {
+			// the user api carries the passwords
+			body = "<redacted>"
+		}
+		kvs = append(kvs, "requestBody", body, "responseBody", string(responseBody))
+	}
 	if err != nil {
-		return err
+		logger.Warn("vectordb request failed", append(kvs, "error", err.Error())...)
+		return
 	}
-	return c.handleResponse(ctx, response, res)
+	logger.Debug("vectordb request", kvs...)
 }
 
-// WithTimeout set client timeout
+// gzipBytes returns the gzip compressed data.
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// endpointFailed reports whether err means the endpoint is unavailable.
+func endpointFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apiErr, ok := asAPIError(err); ok {
+		return apiErr.HTTPStatus/100 == 5
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
+
+// backoff returns the exponential backoff with jitter before the retry after attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	backoff := c.option.RetryBackoff << uint(attempt)
+	if backoff <= 0 || backoff > c.option.RetryMaxBackoff {
+		backoff = c.option.RetryMaxBackoff
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+type retryCountKey struct{}
+
+// WithRetryCount returns a context overriding ClientOption.RetryCount for the requests using it.
+func WithRetryCount(ctx context.Context, retryCount int) context.Context {
+	return context.WithValue(ctx, retryCountKey{}, retryCount)
+}
+
+type headerKey struct{}
+
+type requestIDKey struct{}
+
+// RequestIDHeader the header carrying the request id of client
+const RequestIDHeader = "X-Request-Id"
+
+// WithRequestID returns a context sending the requests using it with the id in RequestIDHeader,
+// instead of the uuid generated for each request, used to correlate the requests end to end.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// newRequestID returns a random uuid of version 4.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// responseRequestID returns the request id of server in the requestId field of body, or the RequestIDHeader
+// of response if the field is not returned.
+func responseRequestID(response *http.Response, commenRes *CommmonResponse) string {
+	if commenRes != nil && commenRes.RequestId != "" {
+		return commenRes.RequestId
+	}
+	return response.Header.Get(RequestIDHeader)
+}
+
+// WithHeader returns a context adding the header to the requests using it.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	header := make(http.Header)
+	if parent, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		header = parent.Clone()
+	}
+	header.Set(key, value)
+	return context.WithValue(ctx, headerKey{}, header)
+}
+
+// checkRequestSize returns RequestTooLargeError if the size of request exceeds MaxRequestBytes,
+// documents is the number of documents in the request, used to suggest the number fitting the limit.
+func checkRequestSize(option ClientOption, path string, size, documents int) error {
+	limit := option.MaxRequestBytes
+	if limit <= 0 || size <= limit {
+		return nil
+	}
+	err := &RequestTooLargeError{RequestPath: path, Size: size, Limit: limit}
+	if documents > 0 {
+		err.FitDocuments = int(int64(documents) * int64(limit) / int64(size))
+	}
+	return err
+}
+
+// strictWarning returns WarningError for the warning of write request if StrictWarnings is set.
+func strictWarning(option ClientOption, path, warning string) error {
+	if warning == "" || !option.StrictWarnings || idempotentActions[path[strings.LastIndex(path, "/")+1:]] {
+		return nil
+	}
+	return &WarningError{Warning: warning, RequestPath: path}
+}
+
+// idempotentActions are the last path segments of the apis which could be safely resent.
+var idempotentActions = map[string]bool{
+	"query":        true,
+	"search":       true,
+	"hybridSearch": true,
+	"describe":     true,
+	"list":         true,
+	"count":        true,
+	"get":          true,
+	"getChunks":    true,
+}
+
+// retryable reports whether the request failed with err could be retried.
+func retryable(ctx context.Context, path string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		// the request never reached the server
+		return true
+	}
+	if !idempotentActions[path[strings.LastIndex(path, "/")+1:]] {
+		return false
+	}
+	if apiErr, ok := asAPIError(err); ok {
+		return apiErr.HTTPStatus/100 == 5
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// WithTimeout set client timeout, which is used by the requests whose ctx has no deadline, 0 means no timeout
 func (c *Client) WithTimeout(d time.Duration) {
-	c.option.Timeout = d
-	c.cli.Timeout = d
+	atomic.StoreInt64(&c.timeout, int64(d))
+}
+
+func (c *Client) requestTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.timeout))
 }
 
 // Debug set debug mode to show the request and response info
 func (c *Client) Debug(v bool) {
-	c.debug = v
+	var debug int32
+	if v {
+		debug = 1
+	}
+	atomic.StoreInt32(&c.debug, debug)
+	if v && c.tlsIgnored {
+		c.warnTLSIgnored()
+	}
 }
 
-func (c *Client) handleResponse(ctx context.Context, res *http.Response, out interface{}) error {
-	responseBytes, err := io.ReadAll(res.Body)
-	if err != nil {
-		return err
+func (c *Client) isDebug() bool {
+	return atomic.LoadInt32(&c.debug) == 1
+}
+
+func (c *Client) warnTLSIgnored() {
+	if logger, _ := clientLogger(c.option, c.isDebug()); logger != nil {
+		logger.Warn("the TLS options are ignored because of the custom Transport")
 	}
+}
+
+// newTLSConfig build the tls config of the default Transport by the TLS options.
+func newTLSConfig(option ClientOption) (*tls.Config, error) {
+	config := new(tls.Config)
+	if option.TLSConfig != nil {
+		config = option.TLSConfig.Clone()
+	}
+	if option.InsecureSkipVerify {
+		config.InsecureSkipVerify = true
+	}
+	if option.CAFile != "" {
+		pem, err := os.ReadFile(option.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "read CAFile failed")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificate found in CAFile %s", option.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if option.ClientCertFile != "" {
+		keyFile := option.ClientKeyFile
+		if keyFile == "" {
+			keyFile = option.ClientCertFile
+		}
+		cert, err := tls.LoadX509KeyPair(option.ClientCertFile, keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load client certificate failed")
+		}
+		config.Certificates = append(config.Certificates, cert)
+	}
+	return config, nil
+}
+
+// handleResponse unmarshal the response body into out, the body and the request id of server are returned for logging.
+func (c *Client) handleResponse(ctx context.Context, res *http.Response, out interface{}) ([]byte, string, error) {
 	defer res.Body.Close()
-	if c.debug {
-		log.Printf("[DEBUG] RESPONSE: %d %s", res.StatusCode, string(responseBytes))
+	var reader io.Reader = res.Body
+	if res.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return nil, responseRequestID(res, nil), errors.Wrap(err, "invalid gzip response")
+		}
+		defer gr.Close()
+		reader = gr
 	}
+	responseBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, responseRequestID(res, nil), err
+	}
+	codec := responseCodec(requestCodec(ctx), res.Header.Get("Content-Type"))
+	serverRequestID, err := c.unmarshalResponse(res, responseBytes, out, codec)
+	return responseBytes, serverRequestID, err
+}
+
+// unmarshalResponse unmarshal the response body into out, and returns the request id of server read from the
+// common fields of body.
+func (c *Client) unmarshalResponse(res *http.Response, responseBytes []byte, out interface{}, codec Codec) (string, error) {
 	if res.StatusCode/100 != 2 {
-		return errors.Errorf("response code is %d, %s", res.StatusCode, string(responseBytes))
+		apiErr := &APIError{HTTPStatus: res.StatusCode, Message: string(responseBytes), RequestPath: res.Request.URL.Path}
+		var commenRes CommmonResponse
+		if codec.Unmarshal(responseBytes, &commenRes) == nil {
+			apiErr.Code = commenRes.Code
+			return responseRequestID(res, &commenRes), apiErr
+		}
+		// the body is not a response of vectordb, such as the html page of a proxy
+		apiErr.Message = bodySnippet(responseBytes, maxErrorBodyBytes)
+		return responseRequestID(res, nil), &HTTPError{StatusCode: res.StatusCode, ContentType: res.Header.Get("Content-Type"),
+			Body: apiErr.Message, RequestPath: apiErr.RequestPath, apiErr: apiErr}
 	}
 
-	if !json.Valid(responseBytes) {
-		return errors.Errorf(`invalid response content: %s`, responseBytes)
+	_, isJSON := codec.(JSONCodec)
+	if isJSON && !json.Valid(responseBytes) {
+		return responseRequestID(res, nil), errors.Errorf(`invalid response content of %s: %s`, res.Request.URL.Path,
+			bodySnippet(responseBytes, maxInvalidBodyBytes))
 	}
 	var commenRes CommmonResponse
 
-	if err := json.Unmarshal(responseBytes, &commenRes); err != nil {
-		return errors.Wrapf(err, `json.Unmarshal failed with content:%s`, responseBytes)
+	if err := codec.Unmarshal(responseBytes, &commenRes); err != nil {
+		return responseRequestID(res, nil), errors.Wrapf(err, `unmarshal failed with content of %s: %s`, res.Request.URL.Path,
+			bodySnippet(responseBytes, maxInvalidBodyBytes))
 	}
+	serverRequestID := responseRequestID(res, &commenRes)
 
 	if commenRes.Code != 0 {
-		return errors.Errorf("code: %d, message: %s", commenRes.Code, commenRes.Msg)
+		return serverRequestID, &APIError{Code: commenRes.Code, Message: commenRes.Msg, HTTPStatus: res.StatusCode, RequestPath: res.Request.URL.Path}
+	}
+	if err := strictWarning(c.option, res.Request.URL.Path, commenRes.Warning); err != nil {
+		return serverRequestID, err
 	}
 
-	if err := json.Unmarshal(responseBytes, &out); err != nil {
-		return errors.Wrapf(err, `json.Unmarshal failed with content:%s`, responseBytes)
+	target := out
+	if isJSON {
+		target = &out
 	}
-	return nil
+	if err := codec.Unmarshal(responseBytes, target); err != nil {
+		return serverRequestID, errors.Wrapf(err, `unmarshal failed with content of %s: %s`, res.Request.URL.Path,
+			bodySnippet(responseBytes, maxInvalidBodyBytes))
+	}
+	return serverRequestID, nil
+}
+
+// codec returns the Codec of ClientOption, or JSONCodec if it is not set or rejected by the server.
+func (c *Client) codec() Codec {
+	if c.option.Codec == nil || atomic.LoadInt32(&c.codecRejected) != 0 {
+		return JSONCodec{}
+	}
+	return c.option.Codec
+}
+
+// uncompressedKey marks the request resent uncompressed after the server rejects its Content-Encoding.
+type uncompressedKey struct{}
+
+// encodingRejected reports whether the response rejects the gzip Content-Encoding of the request, and
+// whether the compression should be disabled for the client. A 415 is also returned for the Content-Type
+// of a Codec, so it rejects the encoding only with JSONCodec or if its body names the encoding. A 400 rejects
+// the encoding only if its body names it, and then only the request is resent uncompressed.
+// The body of response is restored after being read.
+func encodingRejected(response *http.Response, codec Codec) (rejected, permanent bool) {
+	if response.StatusCode != http.StatusUnsupportedMediaType && response.StatusCode != http.StatusBadRequest {
+		return false, false
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false, false
+	}
+	lower := bytes.ToLower(body)
+	namesEncoding := bytes.Contains(lower, []byte("encoding")) || bytes.Contains(lower, []byte("gzip"))
+	if response.StatusCode == http.StatusBadRequest {
+		return namesEncoding, false
+	}
+	_, isJSON := codec.(JSONCodec)
+	return namesEncoding || isJSON, namesEncoding || isJSON
+}
+
+// codecRejected returns true if the server does not accept the request or response content type.
+func codecRejected(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.HTTPStatus == http.StatusUnsupportedMediaType || apiErr.HTTPStatus == http.StatusNotAcceptable)
+}
+
+const (
+	// maxErrorBodyBytes the max bytes of the body kept in HTTPError
+	maxErrorBodyBytes = 512
+	// maxInvalidBodyBytes the max bytes of the invalid body of 2xx response kept in the error
+	maxInvalidBodyBytes = 128
+)
+
+// bodySnippet returns the first limit bytes of body, with the invalid utf-8, control characters
+// and whitespaces collapsed into single spaces, so it fits in a line of log.
+func bodySnippet(body []byte, limit int) string {
+	truncated := len(body) > limit
+	if truncated {
+		body = body[:limit]
+	}
+	s := strings.ToValidUTF8(string(body), "")
+	s = strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}), " ")
+	if truncated {
+		s += "..."
+	}
+	return s
 }
 
-// Close wrap http.Client.CloseIdleConnections
+// Close closes the client and its idle connections immediately, without waiting for the requests in flight.
+// The client and the clients returned by WithOptions share the connections, they are all closed,
+// and the requests after closed return ErrClientClosed. Use Shutdown to wait for the requests in flight.
 func (c *Client) Close() {
+	c.lifecycle.close()
 	c.cli.CloseIdleConnections()
 }
 
+// Shutdown closes the client as Close, but waits for the requests in flight to finish before closing the idle connections.
+// It returns the error of ctx if ctx is done before the requests finish, the idle connections are closed anyway.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.lifecycle.close()
+	defer c.cli.CloseIdleConnections()
+	done := make(chan struct{})
+	go func() {
+		c.lifecycle.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// clientLifecycle tracks the requests in flight to shutdown, shared by the clients returned by WithOptions.
+type clientLifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	inFlight sync.WaitGroup
+}
+
+// begin counts a request in flight, it returns ErrClientClosed if the client is closed.
+func (l *clientLifecycle) begin() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClientClosed
+	}
+	l.inFlight.Add(1)
+	return nil
+}
+
+func (l *clientLifecycle) end() {
+	l.inFlight.Done()
+}
+
+func (l *clientLifecycle) close() {
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+}
+
+// schemaCache returns the schema cache and the namespace of client.
+func (c *Client) schemaCache() (*schemaCache, string) {
+	return c.schemas, c.namespace
+}
+
 func (c *Client) Options() ClientOption {
-	return c.option
+	option := c.option
+	option.Timeout = c.requestTimeout()
+	return option
 }
 
 func optionMerge(option ClientOption) ClientOption {
@@ -226,11 +1220,35 @@ func optionMerge(option ClientOption) ClientOption {
 	if option.IdleConnTimeout == 0 {
 		option.IdleConnTimeout = defaultOption.IdleConnTimeout
 	}
+	if option.DialTimeout == 0 {
+		option.DialTimeout = defaultOption.DialTimeout
+	}
+	if option.TLSHandshakeTimeout == 0 {
+		option.TLSHandshakeTimeout = defaultOption.TLSHandshakeTimeout
+	}
 	if option.MaxIdldConnPerHost == 0 {
 		option.MaxIdldConnPerHost = defaultOption.MaxIdldConnPerHost
 	}
 	if option.ReadConsistency == "" {
 		option.ReadConsistency = defaultOption.ReadConsistency
 	}
+	if option.RetryBackoff == 0 {
+		option.RetryBackoff = defaultOption.RetryBackoff
+	}
+	if option.RetryMaxBackoff == 0 {
+		option.RetryMaxBackoff = defaultOption.RetryMaxBackoff
+	}
+	if option.EndpointFailureThreshold == 0 {
+		option.EndpointFailureThreshold = defaultOption.EndpointFailureThreshold
+	}
+	if option.EndpointProbeInterval == 0 {
+		option.EndpointProbeInterval = defaultOption.EndpointProbeInterval
+	}
+	if option.CompressionThreshold == 0 {
+		option.CompressionThreshold = defaultOption.CompressionThreshold
+	}
+	if option.MaxRequestBytes == 0 {
+		option.MaxRequestBytes = defaultOption.MaxRequestBytes
+	}
 	return option
 }
@@ -0,0 +1,85 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"net/http/httptrace"
+	"sync/atomic"
+)
+
+// ClientStats the snapshot of the request metrics of client, which are counted without the debug logging.
+type ClientStats struct {
+	// InFlight: the http requests being sent
+	InFlight int64
+	// Requests: the http requests sent, each retry is counted as a request
+	Requests int64
+	// Errors: the http requests failed, including the ones retried later
+	Errors int64
+	// Retries: the requests resent after failure
+	Retries int64
+	// ConnsReused, ConnsNew: the requests sent on an idle connection, or on a new one
+	ConnsReused int64
+	ConnsNew    int64
+}
+
+// ConnReuseRatio returns the ratio of the requests sent on the reused connections, 0 if no request is sent.
+func (s ClientStats) ConnReuseRatio() float64 {
+	total := s.ConnsReused + s.ConnsNew
+	if total == 0 {
+		return 0
+	}
+	return float64(s.ConnsReused) / float64(total)
+}
+
+// clientStats the counters of ClientStats, shared by the clients returned by WithOptions.
+type clientStats struct {
+	inFlight    int64
+	requests    int64
+	errors      int64
+	retries     int64
+	connsReused int64
+	connsNew    int64
+	trace       *httptrace.ClientTrace
+}
+
+func newClientStats() *clientStats {
+	stats := new(clientStats)
+	stats.trace = &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				atomic.AddInt64(&stats.connsReused, 1)
+			} else {
+				atomic.AddInt64(&stats.connsNew, 1)
+			}
+		},
+	}
+	return stats
+}
+
+// Stats returns the snapshot of the request metrics, the connections are only counted by the http client.
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		InFlight:    atomic.LoadInt64(&c.stats.inFlight),
+		Requests:    atomic.LoadInt64(&c.stats.requests),
+		Errors:      atomic.LoadInt64(&c.stats.errors),
+		Retries:     atomic.LoadInt64(&c.stats.retries),
+		ConnsReused: atomic.LoadInt64(&c.stats.connsReused),
+		ConnsNew:    atomic.LoadInt64(&c.stats.connsNew),
+	}
+}
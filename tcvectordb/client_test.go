@@ -0,0 +1,683 @@
+package tcvectordb
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/collection"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, option ClientOption) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	cli, err := NewClient(server.URL, "root", "key", &option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cli
+}
+
+func TestRequestRetry(t *testing.T) {
+	var calls int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":0,"collection":{"collection":"coll"}}`))
+	}, ClientOption{RetryCount: 3, RetryBackoff: time.Millisecond})
+
+	res := new(collection.DescribeRes)
+	err := cli.Request(context.Background(), &collection.DescribeReq{Database: "db", Collection: "coll"}, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || res.Collection.Collection != "coll" {
+		t.Errorf("expect success at the 3rd attempt, calls %d, res %+v", calls, res.Collection)
+	}
+}
+
+func TestRequestRetryNotIdempotent(t *testing.T) {
+	var calls int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}, ClientOption{RetryCount: 3, RetryBackoff: time.Millisecond})
+
+	err := cli.Request(context.Background(), &document.UpsertReq{Database: "db", Collection: "coll"}, new(document.UpsertRes))
+	if err == nil || calls != 1 {
+		t.Errorf("expect upsert not retried on 5xx, calls %d, err %v", calls, err)
+	}
+
+	calls = 0
+	err = cli.Request(WithRetryCount(context.Background(), 1), &document.QueryReq{Database: "db", Collection: "coll"}, new(document.QueryRes))
+	if err == nil || calls != 2 {
+		t.Errorf("expect query retried once by context override, calls %d, err %v", calls, err)
+	}
+}
+
+func TestRequestContextCanceled(t *testing.T) {
+	release := make(chan struct{})
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}, ClientOption{Timeout: 10 * time.Second})
+	t.Cleanup(func() { close(release) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	err := cli.Request(ctx, &document.QueryReq{}, new(document.QueryRes))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expect context canceled, got %v", err)
+	}
+	if cost := time.Since(start); cost > time.Second {
+		t.Errorf("expect request aborted promptly, cost %v", cost)
+	}
+}
+
+func TestRequestContextDeadline(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{Timeout: 50 * time.Millisecond})
+
+	err := cli.Request(context.Background(), &document.QueryReq{}, new(document.QueryRes))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect client timeout without ctx deadline, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = cli.Request(ctx, &document.QueryReq{}, new(document.QueryRes))
+	if err != nil {
+		t.Errorf("expect ctx deadline overrides client timeout, got %v", err)
+	}
+
+	cli.WithTimeout(0)
+	err = cli.Request(context.Background(), &document.QueryReq{}, new(document.QueryRes))
+	if err != nil {
+		t.Errorf("expect no timeout with 0, got %v", err)
+	}
+}
+
+func TestEndpointPickWraps(t *testing.T) {
+	pool := newEndpointPool([]string{"a", "b", "c"})
+	pool.next = math.MaxUint32
+	for _, url := range []string{"a", "a", "b"} {
+		if e := pool.pick(); e.url != url {
+			t.Errorf("expect endpoint %s, got %s", url, e.url)
+		}
+	}
+}
+
+func TestClientWithEndpoints(t *testing.T) {
+	var badCalls, goodCalls int32
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&badCalls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&goodCalls, 1)
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer good.Close()
+
+	cli, err := NewClientWithEndpoints([]string{bad.URL, good.URL}, "root", "key",
+		&ClientOption{RetryCount: 1, RetryBackoff: time.Millisecond, EndpointFailureThreshold: 1, EndpointProbeInterval: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		err = cli.Request(context.Background(), &document.QueryReq{}, new(document.QueryRes))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if badCalls != 1 || goodCalls != 5 {
+		t.Errorf("expect the failed endpoint skipped, bad calls %d, good calls %d", badCalls, goodCalls)
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	var header http.Header
+	var calls int32
+	veto := errors.New("no tenant")
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		header = r.Header
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{
+		DefaultHeaders: map[string]string{"X-Tenant-Id": "default", "X-Env": "test"},
+		RequestInterceptors: []RequestInterceptor{
+			func(req *http.Request) error {
+				req.Header.Set("X-Trace-Id", "trace-"+req.Header.Get("X-Tenant-Id"))
+				return nil
+			},
+			func(req *http.Request) error {
+				if req.Header.Get("X-Tenant-Id") == "" {
+					return veto
+				}
+				return nil
+			},
+		},
+	})
+
+	ctx := WithHeader(context.Background(), "X-Tenant-Id", "t1")
+	err := cli.Request(ctx, &document.QueryReq{}, new(document.QueryRes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("X-Tenant-Id") != "t1" || header.Get("X-Env") != "test" || header.Get("X-Trace-Id") != "trace-t1" {
+		t.Errorf("unexpected headers %v", header)
+	}
+
+	ctx = WithHeader(context.Background(), "X-Tenant-Id", "")
+	err = cli.Request(ctx, &document.QueryReq{}, new(document.QueryRes))
+	if !errors.Is(err, veto) || calls != 1 {
+		t.Errorf("expect request vetoed by interceptor, calls %d, err %v", calls, err)
+	}
+}
+
+func TestRequestCompression(t *testing.T) {
+	var encodings []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			if _, err := gzip.NewReader(r.Body); err != nil {
+				t.Errorf("invalid gzip body: %v", err)
+			}
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("expect gzip accepted, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		gw.Write([]byte(`{"code":0,"collection":{"collection":"coll"}}`))
+		gw.Close()
+	}, ClientOption{EnableCompression: true, CompressionThreshold: 10})
+
+	for i := 0; i < 2; i++ {
+		res := new(collection.DescribeRes)
+		err := cli.Request(context.Background(), &collection.DescribeReq{Database: "db", Collection: "coll"}, res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Collection.Collection != "coll" {
+			t.Errorf("unexpected response %+v", res)
+		}
+	}
+	if len(encodings) != 3 || encodings[0] != "gzip" || encodings[1] != "" || encodings[2] != "" {
+		t.Errorf("expect one compressed request then fallback, got %q", encodings)
+	}
+}
+
+func TestRequestCompressionBadRequest(t *testing.T) {
+	var encodings []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		switch {
+		case len(encodings) == 1:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":1,"msg":"invalid collection"}`))
+		case r.Header.Get("Content-Encoding") == "gzip" && len(encodings) == 2:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":1,"msg":"unsupported content encoding gzip"}`))
+		default:
+			w.Write([]byte(`{"code":0}`))
+		}
+	}, ClientOption{EnableCompression: true, CompressionThreshold: 10})
+
+	ctx := context.Background()
+	req := &collection.DescribeReq{Database: "db", Collection: "coll"}
+	if err := cli.Request(ctx, req, new(collection.DescribeRes)); err == nil {
+		t.Fatal("expect the plain 400 returned")
+	}
+	for i := 0; i < 2; i++ {
+		if err := cli.Request(ctx, req, new(collection.DescribeRes)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(encodings) != 4 || encodings[0] != "gzip" || encodings[1] != "gzip" || encodings[2] != "" || encodings[3] != "gzip" {
+		t.Fatalf("expect only the request naming the encoding resent uncompressed, got %q", encodings)
+	}
+}
+
+func TestClientTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		option  ClientOption
+		succeed bool
+	}{
+		{ClientOption{}, false},
+		{ClientOption{InsecureSkipVerify: true}, true},
+		{ClientOption{CAFile: caFile}, true},
+	} {
+		cli, err := NewClient(server.URL, "root", "key", &c.option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = cli.Request(context.Background(), &document.QueryReq{}, new(document.QueryRes))
+		if (err == nil) != c.succeed {
+			t.Errorf("option %+v: expect succeed %v, got err %v", c.option, c.succeed, err)
+		}
+	}
+
+	_, err = NewClient(server.URL, "root", "key", &ClientOption{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	if err == nil {
+		t.Error("expect error of missing CAFile")
+	}
+}
+
+type recordLogger struct {
+	entries []map[string]interface{}
+}
+
+func (l *recordLogger) record(level, msg string, keysAndValues []interface{}) {
+	entry := map[string]interface{}{"level": level, "msg": msg}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		entry[keysAndValues[i].(string)] = keysAndValues[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *recordLogger) Debug(msg string, kvs ...interface{}) { l.record("debug", msg, kvs) }
+func (l *recordLogger) Info(msg string, kvs ...interface{})  { l.record("info", msg, kvs) }
+func (l *recordLogger) Warn(msg string, kvs ...interface{})  { l.record("warn", msg, kvs) }
+func (l *recordLogger) Error(msg string, kvs ...interface{}) { l.record("error", msg, kvs) }
+
+func TestRequestLogger(t *testing.T) {
+	logger := new(recordLogger)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/document/delete" {
+			w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{Logger: logger})
+
+	cli.Request(context.Background(), &document.QueryReq{Database: "db"}, new(document.QueryRes))
+	cli.Request(context.Background(), &document.DeleteReq{Database: "db"}, new(document.DeleteRes))
+	if len(logger.entries) != 2 {
+		t.Fatalf("expect 2 log entries, got %v", logger.entries)
+	}
+	query, del := logger.entries[0], logger.entries[1]
+	if query["level"] != "debug" || query["path"] != "/document/query" || query["status"] != 200 || query["requestSize"].(int) == 0 {
+		t.Errorf("unexpected log of query %v", query)
+	}
+	if _, ok := query["requestBody"]; ok {
+		t.Errorf("expect no body logged without LogBodies, got %v", query)
+	}
+	if del["level"] != "warn" || del["code"] != int32(ERR_UNDEFINED_COLLECTION) || del["msg"] != "collection not exist" {
+		t.Errorf("unexpected log of failed delete %v", del)
+	}
+
+	cli.Debug(true)
+	cli.Request(context.Background(), &document.QueryReq{Database: "db"}, new(document.QueryRes))
+	body, _ := logger.entries[2]["requestBody"].(string)
+	if !strings.Contains(body, `"database":"db"`) || strings.Contains(body, "key") {
+		t.Errorf("expect request body logged in debug mode, got %v", logger.entries[2])
+	}
+}
+
+type recordTracer struct {
+	spans []RequestSpan
+}
+
+func (t *recordTracer) Start(ctx context.Context, span *RequestSpan) (context.Context, func(*RequestSpan)) {
+	return WithHeader(ctx, "Traceparent", "00-trace-span-01"), func(span *RequestSpan) {
+		t.spans = append(t.spans, *span)
+	}
+}
+
+func TestRequestTracer(t *testing.T) {
+	tracer := new(recordTracer)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Traceparent") != "00-trace-span-01" {
+			t.Errorf("expect trace header propagated, got %v", r.Header)
+		}
+		if r.URL.Path == "/document/delete" {
+			w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"documents":[]}`))
+	}, ClientOption{Tracer: tracer})
+
+	cli.Request(context.Background(), &document.QueryReq{Database: "db", Collection: "coll"}, new(document.QueryRes))
+	cli.Request(context.Background(), &document.DeleteReq{Database: "db"}, new(document.DeleteRes))
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expect 2 spans, got %v", tracer.spans)
+	}
+	query, del := tracer.spans[0], tracer.spans[1]
+	if query.Operation != "/document/query" || query.Database != "db" || query.Collection != "coll" ||
+		query.Status != http.StatusOK || query.RequestSize == 0 || query.ResponseSize == 0 || query.Err != nil {
+		t.Errorf("unexpected span of query %+v", query)
+	}
+	if del.Code != ERR_UNDEFINED_COLLECTION || !IsCollectionNotExist(del.Err) {
+		t.Errorf("unexpected span of failed delete %+v", del)
+	}
+}
+
+func TestClientEagerConnect(t *testing.T) {
+	var conns, pings int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&pings, 1)
+		if !strings.Contains(r.Header.Get("Authorization"), "api_key=key") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"code":0,"databases":[]}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	cli, err := NewClient(server.URL, "root", "key", &ClientOption{EagerConnect: true, MaxIdldConnPerHost: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, c := atomic.LoadInt32(&pings), atomic.LoadInt32(&conns); p != 3 || c != 3 {
+		t.Errorf("expect 3 connections warmed up, pings %d, conns %d", p, c)
+	}
+	if err = cli.Ping(context.Background()); err != nil || atomic.LoadInt32(&conns) != 3 {
+		t.Errorf("expect ping reusing the idle connections, conns %d, err %v", atomic.LoadInt32(&conns), err)
+	}
+
+	_, err = NewClient(server.URL, "root", "wrong", &ClientOption{EagerConnect: true})
+	if !IsPermissionDenied(err) {
+		t.Errorf("expect permission denied of wrong key, got %v", err)
+	}
+}
+
+func TestClientWithOptions(t *testing.T) {
+	consistencies := make(chan string, 16)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := new(document.QueryReq)
+		json.NewDecoder(r.Body).Decode(req)
+		consistencies <- req.ReadConsistency
+		if r.Header.Get("X-Slow") != "" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Write([]byte(`{"code":0,"documents":[]}`))
+	}, ClientOption{Timeout: time.Second})
+
+	debug := true
+	scoped := cli.WithOptions(ScopedOption{Timeout: 20 * time.Millisecond, Debug: &debug, ReadConsistency: StrongConsistency})
+	if cli.Options().Timeout != time.Second || cli.isDebug() || cli.Options().ReadConsistency != EventualConsistency {
+		t.Errorf("expect origin client unchanged, got %+v", cli.Options())
+	}
+	if scoped.Options().Timeout != 20*time.Millisecond || !scoped.isDebug() {
+		t.Errorf("unexpected scoped options %+v", scoped.Options())
+	}
+
+	if _, err := scoped.Query(context.Background(), "db", "coll", []string{"0001"}); err != nil {
+		t.Fatal(err)
+	}
+	if c := <-consistencies; c != string(StrongConsistency) {
+		t.Errorf("expect scoped read consistency, got %q", c)
+	}
+	if _, err := cli.Query(context.Background(), "db", "coll", []string{"0001"}); err != nil {
+		t.Fatal(err)
+	}
+	if c := <-consistencies; c != string(EventualConsistency) {
+		t.Errorf("expect origin read consistency, got %q", c)
+	}
+
+	_, err := scoped.Query(WithHeader(context.Background(), "X-Slow", "1"), "db", "coll", []string{"0001"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect scoped timeout exceeded, got %v", err)
+	}
+	<-consistencies
+
+	// Debug and WithTimeout are safe to call when the client is shared by goroutines
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			cli.Debug(i%2 == 0)
+			cli.WithTimeout(time.Duration(i+1) * time.Second)
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		if _, err := cli.Query(context.Background(), "db", "coll", []string{"0001"}); err != nil {
+			t.Fatal(err)
+		}
+		<-consistencies
+	}
+	<-done
+}
+
+func TestClientStats(t *testing.T) {
+	var calls int32
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":0,"databases":[]}`))
+	}, ClientOption{RetryCount: 1, RetryBackoff: time.Millisecond, MaxConnsPerHost: 4, ForceAttemptHTTP2: true})
+
+	for i := 0; i < 2; i++ {
+		if _, err := cli.ListDatabase(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats := cli.Stats()
+	if stats.Requests != 3 || stats.Errors != 1 || stats.Retries != 1 || stats.InFlight != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.ConnsNew != 1 || stats.ConnsReused != 2 || stats.ConnReuseRatio() < 0.6 {
+		t.Errorf("expect the connection reused, got %+v", stats)
+	}
+	if scoped := cli.WithOptions(ScopedOption{}); scoped.Stats().Requests != 3 {
+		t.Error("expect stats shared by the scoped client")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	logger := new(recordLogger)
+	var got []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(RequestIDHeader))
+		if r.URL.Path == "/document/delete" {
+			w.Write([]byte(`{"code":15302,"msg":"collection not exist","requestId":"srv-1"}`))
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{Logger: logger})
+
+	cli.Request(context.Background(), &document.QueryReq{Database: "db"}, new(document.QueryRes))
+	if len(got[0]) != 36 || got[0][14] != '4' {
+		t.Errorf("expect a generated uuid v4, got %q", got[0])
+	}
+	if logger.entries[0]["requestId"] != got[0] {
+		t.Errorf("expect request id logged, got %v", logger.entries[0])
+	}
+
+	ctx := WithRequestID(context.Background(), "my-id")
+	err := cli.Request(ctx, &document.DeleteReq{Database: "db"}, new(document.DeleteRes))
+	if got[1] != "my-id" {
+		t.Errorf("expect request id from context, got %q", got[1])
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "my-id" || apiErr.ServerRequestID != "srv-1" {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if id := RequestIDFromError(err); id != "srv-1" {
+		t.Errorf("expect server request id, got %q", id)
+	}
+	if logger.entries[1]["serverRequestId"] != "srv-1" {
+		t.Errorf("expect server request id logged, got %v", logger.entries[1])
+	}
+	if id := RequestIDFromError(errors.New("x")); id != "" {
+		t.Errorf("expect no request id, got %q", id)
+	}
+}
+
+func TestClientShutdown(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{})
+	scoped := cli.WithOptions(ScopedOption{Timeout: time.Minute})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- scoped.Request(context.Background(), &collection.ListReq{Database: "db"}, new(collection.ListRes))
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := cli.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect shutdown timed out with the request in flight, got %v", err)
+	}
+	err := cli.Request(context.Background(), &collection.ListReq{Database: "db"}, new(collection.ListRes))
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expect ErrClientClosed after shutdown, got %v", err)
+	}
+
+	close(release)
+	if err = <-done; err != nil {
+		t.Errorf("expect the request in flight finished, got %v", err)
+	}
+	if err = scoped.Shutdown(context.Background()); err != nil {
+		t.Errorf("expect shutdown without requests in flight, got %v", err)
+	}
+}
+
+func TestRequestTiming(t *testing.T) {
+	var infos []RequestInfo
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/document/search" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Server-Timing", `db;dur=5, total;dur=12.5`)
+		w.Write([]byte(`{"code":0,"documents":[{"id":"1"}]}`))
+	}, ClientOption{CollectTimings: true, OnRequestDone: func(info RequestInfo) {
+		infos = append(infos, info)
+	}})
+	coll := cli.Database("db").Collection("coll")
+
+	res, err := coll.Query(context.Background(), []string{"1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	timing := res.Timing
+	if timing == nil || timing.Attempts != 1 || timing.Total <= 0 || timing.FirstByte <= 0 || timing.Connect <= 0 ||
+		timing.ConnReused || timing.Server != 12500*time.Microsecond {
+		t.Fatalf("unexpected timing %+v", timing)
+	}
+	res, err = coll.Query(context.Background(), []string{"1"})
+	if err != nil || !res.Timing.ConnReused || res.Timing.Connect != 0 {
+		t.Errorf("expect the connection reused, got %+v, %v", res.Timing, err)
+	}
+
+	_, err = coll.Search(context.Background(), [][]float32{{0.1}}, &SearchDocumentParams{SkipDimensionCheck: true})
+	if err == nil {
+		t.Fatal("expect search failed")
+	}
+	if len(infos) != 3 {
+		t.Fatalf("expect 3 requests done, got %d", len(infos))
+	}
+	if info := infos[0]; info.Operation != "/document/query" || info.Collection != "coll" || info.Status != http.StatusOK ||
+		info.Err != nil || info.Duration <= 0 || info.ResponseSize == 0 || info.Timing.Server != 12500*time.Microsecond {
+		t.Errorf("unexpected query info %+v", info)
+	}
+	if info := infos[2]; info.Operation != "/document/search" || info.Status != http.StatusInternalServerError || info.Err == nil {
+		t.Errorf("unexpected search info %+v", info)
+	}
+}
+
+func TestDo(t *testing.T) {
+	var bodies []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") == "" {
+			t.Errorf("unexpected request %s %v", r.Method, r.Header)
+		}
+		switch r.URL.Path {
+		case "/future/echo":
+			w.Write([]byte(`{"code":0,"value":"ok"}`))
+		case "/future/fail":
+			w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`<html>bad gateway</html>`))
+		}
+	}, ClientOption{})
+	ctx := context.Background()
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := cli.Do(ctx, http.MethodPost, "future/echo", map[string]string{"filter": "a<b"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Value != "ok" || strings.TrimSpace(bodies[0]) != `{"filter":"a<b"}` {
+		t.Errorf("unexpected response %+v of body %s", out, bodies[0])
+	}
+
+	err := cli.Do(ctx, http.MethodPost, "/future/fail", nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestPath != "/future/fail" || !IsCollectionNotExist(err) {
+		t.Errorf("expect the APIError of code, got %v", err)
+	}
+	if strings.TrimSpace(bodies[1]) != `{}` {
+		t.Errorf("expect the nil body sent as {}, got %s", bodies[1])
+	}
+	var httpErr *HTTPError
+	if err = cli.Do(ctx, http.MethodPost, "/future/proxy", nil, nil); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("expect the HTTPError of proxy, got %v", err)
+	}
+}
+
+func TestAtomicAlignment(t *testing.T) {
+	// the 64-bit atomic fields must be 64-bit aligned on 32-bit platforms
+	if off := unsafe.Offsetof(Client{}.timeout); off%8 != 0 {
+		t.Errorf("Client.timeout is at offset %d", off)
+	}
+	if off := unsafe.Offsetof(RpcClient{}.timeout); off%8 != 0 {
+		t.Errorf("RpcClient.timeout is at offset %d", off)
+	}
+	if off := unsafe.Offsetof(Session{}.lastWrite); off%8 != 0 {
+		t.Errorf("Session.lastWrite is at offset %d", off)
+	}
+}
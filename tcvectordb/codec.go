@@ -0,0 +1,109 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+)
+
+// Codec encodes the request bodies and decodes the response bodies of the http client. The server answers
+// with json unless it supports ContentType, the responses are decoded by Codec only if they are of ContentType.
+// Note that the vectordb gateway only supports json currently, the binary protocol is served by grpc, see NewRpcClient.
+type Codec interface {
+	// ContentType: the media type of the encoded bodies, such as application/json
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec the default Codec, encoding with encoding/json without escaping html characters.
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type codecKey struct{}
+
+// requestCodec returns the codec of the request body sent with ctx.
+func requestCodec(ctx context.Context) Codec {
+	if codec, ok := ctx.Value(codecKey{}).(Codec); ok {
+		return codec
+	}
+	return JSONCodec{}
+}
+
+// responseCodec returns codec if the content type of response is the one of codec, otherwise JSONCodec.
+func responseCodec(codec Codec, contentType string) Codec {
+	if _, ok := codec.(JSONCodec); ok {
+		return codec
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && mediaType == codec.ContentType() {
+		return codec
+	}
+	return JSONCodec{}
+}
+
+// StreamMarshaler is implemented by the requests which could be encoded while the body is being sent,
+// such as document.UpsertReq, see ClientOption.StreamRequests.
+type StreamMarshaler interface {
+	// MarshalStream writes the json of request to w, it is called again to resend the request.
+	MarshalStream(w io.Writer) error
+}
+
+type streamBodyKey struct{}
+
+// maxStreamLogBytes the max size of the streamed request body kept for the debug logs.
+const maxStreamLogBytes = 4096
+
+// streamLog keeps the head of the streamed body for the logs, and counts its size.
+type streamLog struct {
+	head []byte
+	size int
+}
+
+func (l *streamLog) Write(p []byte) (int, error) {
+	if n := maxStreamLogBytes - len(l.head); n > 0 {
+		if n > len(p) {
+			n = len(p)
+		}
+		l.head = append(l.head, p[:n]...)
+	}
+	l.size += len(p)
+	return len(p), nil
+}
@@ -0,0 +1,227 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/collection"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
+	"google.golang.org/protobuf/proto"
+)
+
+// testCodec prefixes the json bodies with a marker to tell them from json.
+type testCodec struct{ unmarshaled int }
+
+func (*testCodec) ContentType() string { return "application/x-test" }
+
+func (*testCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	return append([]byte("T"), data...), err
+}
+
+func (c *testCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshaled++
+	return json.Unmarshal(bytes.TrimPrefix(data, []byte("T")), v)
+}
+
+func TestRequestCodec(t *testing.T) {
+	codec := new(testCodec)
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != "application/x-test" || !bytes.HasPrefix(body, []byte("T")) {
+			t.Errorf("unexpected request %q: %s", r.Header.Get("Content-Type"), body)
+		}
+		w.Header().Set("Content-Type", "application/x-test; charset=utf-8")
+		w.Write([]byte(`T{"code":0,"collection":{"collection":"coll"}}`))
+	}, ClientOption{Codec: codec})
+
+	res := new(collection.DescribeRes)
+	err := cli.Request(context.Background(), &collection.DescribeReq{Database: "db", Collection: "coll"}, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Collection.Collection != "coll" || codec.unmarshaled != 2 {
+		t.Errorf("unexpected response %+v, unmarshaled %d", res, codec.unmarshaled)
+	}
+}
+
+func TestRequestCodecFallback(t *testing.T) {
+	var contentTypes []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"collection":{"collection":"coll"}}`))
+	}, ClientOption{Codec: new(testCodec)})
+
+	for i := 0; i < 2; i++ {
+		res := new(collection.DescribeRes)
+		err := cli.Request(context.Background(), &collection.DescribeReq{Database: "db", Collection: "coll"}, res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Collection.Collection != "coll" {
+			t.Errorf("unexpected response %+v", res)
+		}
+	}
+	if len(contentTypes) != 3 || contentTypes[0] != "application/x-test" ||
+		contentTypes[1] != "application/json" || contentTypes[2] != "application/json" {
+		t.Errorf("expect one request of codec then fallback, got %q", contentTypes)
+	}
+}
+
+func TestStreamRequests(t *testing.T) {
+	req := &document.UpsertReq{Database: "db", Collection: "coll", BuildIndex: new(bool)}
+	for i := 0; i < 100; i++ {
+		req.Documents = append(req.Documents, &document.Document{Id: "<doc>", Vector: []float32{0.1, 0.2},
+			Fields: map[string]interface{}{"page": i, "author": "a&b"}})
+	}
+	expect, err := (JSONCodec{}).Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, body)
+		if r.ContentLength != -1 || len(r.TransferEncoding) == 0 || r.TransferEncoding[0] != "chunked" {
+			t.Errorf("expect the body sent in chunks, got length %d, encoding %v", r.ContentLength, r.TransferEncoding)
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "api_key=new") {
+			// the rejected key is refreshed and the request is resent
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"code":0,"affectedCount":100}`))
+	}))
+	defer server.Close()
+	logger := new(recordLogger)
+	cli, err := NewClientWithCredentials(server.URL, new(rotatingCredentials), &ClientOption{StreamRequests: true, Logger: logger, LogBodies: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := new(document.UpsertRes)
+	if err = cli.Request(context.Background(), req, res); err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 2 || !bytes.Equal(bodies[0], expect) || !bytes.Equal(bodies[1], expect) {
+		t.Errorf("expect the body encoded again for the resend the same as json, got %d bodies\n%s\n%s", len(bodies), bodies[0], expect)
+	}
+	entry := logger.entries[len(logger.entries)-1]
+	logged, _ := entry["requestBody"].(string)
+	if entry["requestSize"] != len(expect) || len(logged) != maxStreamLogBytes+3 || !strings.HasSuffix(logged, "...") {
+		t.Errorf("expect the head of body logged, got size %v, body of %d bytes", entry["requestSize"], len(logged))
+	}
+
+	empty := &document.UpsertReq{}
+	buf := bytes.NewBuffer(nil)
+	if err = empty.MarshalStream(buf); err != nil || buf.String() != "{}\n" {
+		t.Errorf("unexpected stream of empty request %q, %v", buf.String(), err)
+	}
+}
+
+const benchmarkDocuments, benchmarkDimension = 100, 768
+
+func benchmarkVector(seed int) []float32 {
+	vector := make([]float32, benchmarkDimension)
+	for i := range vector {
+		vector[i] = float32(seed*benchmarkDimension+i) / 7919
+	}
+	return vector
+}
+
+func BenchmarkMarshalUpsertJSON(b *testing.B) {
+	req := &document.UpsertReq{Database: "db", Collection: "coll"}
+	for i := 0; i < benchmarkDocuments; i++ {
+		req.Documents = append(req.Documents, &document.Document{Id: "doc", Vector: benchmarkVector(i)})
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := (JSONCodec{}).Marshal(req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkMarshalUpsertProto(b *testing.B) {
+	req := &olama.UpsertRequest{Database: "db", Collection: "coll"}
+	for i := 0; i < benchmarkDocuments; i++ {
+		req.Documents = append(req.Documents, &olama.Document{Id: "doc", Vector: benchmarkVector(i)})
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := proto.Marshal(req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkUnmarshalSearchJSON(b *testing.B) {
+	res := &document.SearchRes{Documents: [][]*document.Document{nil}}
+	for i := 0; i < benchmarkDocuments; i++ {
+		res.Documents[0] = append(res.Documents[0], &document.Document{Id: "doc", Score: 0.5, Vector: benchmarkVector(i)})
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := (JSONCodec{}).Unmarshal(data, new(document.SearchRes)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalSearchProto(b *testing.B) {
+	result := &olama.SearchResult{}
+	for i := 0; i < benchmarkDocuments; i++ {
+		result.Documents = append(result.Documents, &olama.Document{Id: "doc", Score: 0.5, Vector: benchmarkVector(i)})
+	}
+	data, err := proto.Marshal(&olama.SearchResponse{Results: []*olama.SearchResult{result}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := proto.Unmarshal(data, new(olama.SearchResponse)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
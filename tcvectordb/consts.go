@@ -18,7 +18,10 @@
 
 package tcvectordb
 
-import "errors"
+import (
+	"errors"
+	"sort"
+)
 
 type IndexType string
 
@@ -31,6 +34,7 @@ const (
 	IVF_SQ4  IndexType = "IVF_SQ4"
 	IVF_SQ8  IndexType = "IVF_SQ8"
 	IVF_SQ16 IndexType = "IVF_SQ16"
+	BIN_FLAT IndexType = "BIN_FLAT"
 
 	// scalar index type
 	PRIMARY         IndexType = "primaryKey"
@@ -38,22 +42,29 @@ const (
 	SPARSE_INVERTED IndexType = "inverted"
 )
 
+// AutoIdUUID the AutoId of primary key, the server generates uuid for the documents without id
+const AutoIdUUID = "uuid"
+
 type MetricType string
 
 const (
-	L2     MetricType = "L2"
-	IP     MetricType = "IP"
-	COSINE MetricType = "COSINE"
+	L2      MetricType = "L2"
+	IP      MetricType = "IP"
+	COSINE  MetricType = "COSINE"
+	HAMMING MetricType = "HAMMING"
 )
 
 type FieldType string
 
 const (
 	Uint64       FieldType = "uint64"
+	Int64        FieldType = "int64"
+	Float64      FieldType = "double"
 	String       FieldType = "string"
 	Array        FieldType = "array"
 	Vector       FieldType = "vector"
 	SparseVector FieldType = "sparseVector"
+	BinaryVector FieldType = "binary_vector"
 )
 
 type EmbeddingModel string
@@ -75,6 +86,32 @@ const (
 	BAAI_BGE_M3 EmbeddingModel = "BAAI/bge-m3"
 )
 
+// embeddingModelDimensions the output dimensions of the embedding models supported by the server
+var embeddingModelDimensions = map[EmbeddingModel]uint32{
+	M3E_BASE:               768,
+	BGE_BASE_ZH:            768,
+	BGE_LARGE_ZH:           1024,
+	MULTILINGUAL_E5_BASE:   768,
+	E5_LARGE_V2:            1024,
+	TEXT2VEC_LARGE_CHINESE: 1024,
+	BAAI_BGE_M3:            1024,
+}
+
+// Dimension returns the output dimension of the embedding model, 0 if the model is unknown.
+func (m EmbeddingModel) Dimension() uint32 {
+	return embeddingModelDimensions[m]
+}
+
+// EmbeddingModels returns the embedding models supported by the server, sorted by name.
+func EmbeddingModels() []EmbeddingModel {
+	models := make([]EmbeddingModel, 0, len(embeddingModelDimensions))
+	for m := range embeddingModelDimensions {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i] < models[j] })
+	return models
+}
+
 type ReadConsistency string
 
 const (
@@ -83,6 +120,13 @@ const (
 	StrongConsistency   ReadConsistency = "strongConsistency"
 )
 
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
 type Language string
 
 const (
@@ -129,6 +173,17 @@ var (
 const (
 	ERR_UNDEFINED_DATABASE   = 15301
 	ERR_UNDEFINED_COLLECTION = 15302
+	// ERR_COLLECTION_EXIST is returned by creating a collection or restoring a backup to a collection which exists
+	ERR_COLLECTION_EXIST = 15203
+	// ERR_BACKUP_IN_PROGRESS is returned by the backup api if the collection is being backed up or restored
+	ERR_BACKUP_IN_PROGRESS = 15902
+)
+
+const (
+	IndexStatusReady    = "ready"
+	IndexStatusTraining = "training"
+	IndexStatusBuilding = "building"
+	IndexStatusFailed   = "failed"
 )
 
 type RerankMethod string
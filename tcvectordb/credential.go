@@ -0,0 +1,109 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CredentialProvider provides the username and api key of requests, used by NewClientWithCredentials
+// when the key is rotated.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (username, key string, err error)
+}
+
+// StaticCredentials the fixed username and api key, which is used by NewClient.
+type StaticCredentials struct {
+	Username string
+	Key      string
+}
+
+func (s StaticCredentials) Credentials(ctx context.Context) (string, string, error) {
+	return s.Username, s.Key, nil
+}
+
+// defaultCredentialTTL the default time to cache the credentials of provider
+const defaultCredentialTTL = time.Minute
+
+// credentialCache caches the credentials of provider for ttl, the ttl is ignored by the static credentials.
+type credentialCache struct {
+	provider CredentialProvider
+	ttl      time.Duration
+
+	mu       sync.Mutex
+	username string
+	key      string
+	expires  time.Time
+}
+
+func newCredentialCache(provider CredentialProvider, ttl time.Duration) *credentialCache {
+	if ttl <= 0 {
+		ttl = defaultCredentialTTL
+	}
+	cache := &credentialCache{provider: provider, ttl: ttl}
+	if static, ok := provider.(StaticCredentials); ok {
+		cache.username, cache.key = static.Username, static.Key
+	}
+	return cache
+}
+
+func (c *credentialCache) static() bool {
+	_, ok := c.provider.(StaticCredentials)
+	return ok
+}
+
+// get returns the cached credentials, and fetches them from provider if they are expired.
+func (c *credentialCache) get(ctx context.Context) (string, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.static() || c.key != "" && time.Now().Before(c.expires) {
+		return c.username, c.key, nil
+	}
+	username, key, err := c.provider.Credentials(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	if username == "" || key == "" {
+		return "", "", errors.New("username or key provided is empty")
+	}
+	c.username, c.key, c.expires = username, key, time.Now().Add(c.ttl)
+	return username, key, nil
+}
+
+// invalidate drops the cached credentials, so that the next request fetches them again.
+// It reports false for the static credentials which could not be refreshed.
+func (c *credentialCache) invalidate() bool {
+	if c.static() {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.key = ""
+	return true
+}
+
+// isCredentialError reports whether err is returned because the credentials are rejected by the server.
+func isCredentialError(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.HTTPStatus == http.StatusUnauthorized
+}
@@ -0,0 +1,60 @@
+package tcvectordb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type rotatingCredentials struct {
+	calls int32
+}
+
+func (p *rotatingCredentials) Credentials(ctx context.Context) (string, string, error) {
+	n := atomic.AddInt32(&p.calls, 1)
+	if n == 1 {
+		return "root", "old", nil
+	}
+	return "root", "new", nil
+}
+
+func TestClientWithCredentials(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !strings.Contains(r.Header.Get("Authorization"), "api_key=new") {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":1,"msg":"invalid api key"}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"databases":["db"]}`))
+	}))
+	defer server.Close()
+
+	provider := new(rotatingCredentials)
+	cli, err := NewClientWithCredentials(server.URL, provider, &ClientOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.ListDatabase(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if provider.calls != 2 || requests != 2 {
+		t.Errorf("expect the rejected key refreshed once, provider calls %d, requests %d", provider.calls, requests)
+	}
+	if _, err = cli.ListDatabase(context.Background()); err != nil || provider.calls != 2 {
+		t.Errorf("expect cached credentials, provider calls %d, err %v", provider.calls, err)
+	}
+
+	static, err := NewClient(server.URL, "root", "old", &ClientOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests = 0
+	if _, err = static.ListDatabase(context.Background()); !IsPermissionDenied(err) || requests != 1 {
+		t.Errorf("expect static key rejected without retry, requests %d, err %v", requests, err)
+	}
+}
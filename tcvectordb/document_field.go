@@ -20,13 +20,20 @@ package tcvectordb
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Field struct {
 	Val interface{} `json:"val,omitempty"`
+	// RawPassthrough: the value is returned as it is stored, because it is not a valid payload of the
+	// FieldCodec registered for the field, such as the data upserted before the codec is registered
+	RawPassthrough bool `json:"-"`
 }
 
 func (f Field) String() string {
@@ -35,7 +42,7 @@ func (f Field) String() string {
 
 func (f Field) StringArray() []string {
 	t := reflect.TypeOf(f.Val)
-	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+	if t == nil || t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
 		return nil
 	}
 	v := reflect.ValueOf(f.Val)
@@ -46,9 +53,15 @@ func (f Field) StringArray() []string {
 	return res
 }
 
+// StringSlice returns the value of the Array field as []string, nil if the value is not an array.
+// The array returned by Query and Search is decoded as []interface{}, which is converted as well.
+func (f Field) StringSlice() []string {
+	return f.StringArray()
+}
+
 func (f Field) Uint64Array() []uint64 {
 	t := reflect.TypeOf(f.Val)
-	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+	if t == nil || t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
 		return nil
 	}
 	v := reflect.ValueOf(f.Val)
@@ -78,7 +91,10 @@ func (f Field) Uint64() uint64 {
 	case float32, float64:
 		return uint64(reflect.ValueOf(v).Float())
 	case json.Number:
-		n, _ := v.Int64()
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return n
+		}
+		n, _ := v.Float64()
 		return uint64(n)
 	}
 	return 0
@@ -102,17 +118,112 @@ func (f Field) Float() float64 {
 	return 0
 }
 
+// Int64 returns the value of the field as int64, the number beyond int64 is truncated as the conversion of Go.
+func (f Field) Int64() int64 {
+	switch v := f.Val.(type) {
+	case int, int8, int16, int32, int64:
+		return reflect.ValueOf(v).Int()
+	case uint, uint8, uint16, uint32, uint64:
+		return int64(reflect.ValueOf(v).Uint())
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	case float32, float64:
+		return int64(reflect.ValueOf(v).Float())
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		n, _ := v.Float64()
+		return int64(n)
+	}
+	return 0
+}
+
+// Float64 returns the value of the field as float64, same as Float.
+func (f Field) Float64() float64 {
+	return f.Float()
+}
+
+// Bool returns the value of the field as bool, the string is parsed by strconv.ParseBool,
+// and the number is true if it is not 0.
+func (f Field) Bool() bool {
+	switch v := f.Val.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return f.Float() != 0
+}
+
+// Exists reports whether the field has a value, which is false for the field missing in the document,
+// used to distinguish the missing field from the zero value returned by the accessors.
+func (f Field) Exists() bool {
+	return f.Val != nil
+}
+
+// Time returns the value of field as time, the string is parsed by layout, and the number is the unix seconds.
+// It returns zero time if the value can not be converted.
+func (f Field) Time(layout string) time.Time {
+	switch v := f.Val.(type) {
+	case time.Time:
+		return v
+	case string:
+		t, _ := time.Parse(layout, v)
+		return t
+	case nil, bool:
+		return time.Time{}
+	}
+	return time.Unix(int64(f.Uint64()), 0)
+}
+
+// As decodes the value of field into dst, which must be a pointer, with the conversions of encoding/json.
+// It returns error if the field is missing or the value does not fit dst.
+func (f Field) As(dst interface{}) error {
+	if !f.Exists() {
+		return errors.New("field not exist")
+	}
+	val := f.Val
+	if n, ok := val.(json.Number); ok {
+		// the integral float such as 3.0 is decoded into the integer as well
+		if _, err := n.Int64(); err != nil {
+			if v, err := n.Float64(); err == nil && v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+				val = int64(v)
+			}
+		}
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// Type returns the FieldType of the value, the negative integer is Int64 and the float is Float64.
 func (f Field) Type() FieldType {
-	switch f.Val.(type) {
+	switch v := f.Val.(type) {
 	case int, int8, int16, int32, int64:
+		if reflect.ValueOf(v).Int() < 0 {
+			return Int64
+		}
 		return Uint64
 	case uint, uint8, uint16, uint32, uint64:
 		return Uint64
+	case float32, float64:
+		return Float64
 	case string:
 		return String
 	case []string, []uint64, []int64, []int, []uint, []interface{}:
 		return Array
 	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return Float64
+		}
+		if strings.HasPrefix(v.String(), "-") {
+			return Int64
+		}
 		return Uint64
 	}
 	return ""
@@ -0,0 +1,76 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFieldAccessors(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"documents":[{"id":"0001","big":18446744073709551615,"price":2.5,"count":3.0,
+			"flag":true,"created":"2024-01-02 03:04:05","updated":1704164645,"tags":["a","b"]}]}`))
+	}, ClientOption{})
+
+	res, err := cli.Database("db").Collection("coll").Query(context.Background(), []string{"0001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := res.Documents[0].Fields
+	if fields["big"].Uint64() != 18446744073709551615 || fields["count"].Uint64() != 3 {
+		t.Errorf("expect numbers decoded with full fidelity, got %v %v", fields["big"].Val, fields["count"].Val)
+	}
+	if fields["price"].Float64() != 2.5 || !fields["flag"].Bool() {
+		t.Errorf("unexpected float or bool field %v %v", fields["price"].Val, fields["flag"].Val)
+	}
+	if fields["big"].Type() != Uint64 || fields["price"].Type() != Float64 || (Field{Val: -3}).Type() != Int64 {
+		t.Errorf("unexpected field types %s %s", fields["big"].Type(), fields["price"].Type())
+	}
+	if n := (Field{Val: json.Number("-9223372036854775808")}).Int64(); n != math.MinInt64 {
+		t.Errorf("expect min int64, got %d", n)
+	}
+	created := fields["created"].Time("2006-01-02 15:04:05")
+	if !created.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) || !fields["updated"].Time("").Equal(created) {
+		t.Errorf("unexpected time fields %v %v", created, fields["updated"].Time(""))
+	}
+	if fields["missing"].Exists() || !fields["flag"].Exists() {
+		t.Error("expect Exists to distinguish the missing field")
+	}
+
+	var tags []string
+	if err = fields["tags"].As(&tags); err != nil || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("unexpected tags %v, %v", tags, err)
+	}
+	var count int
+	if err = fields["count"].As(&count); err != nil || count != 3 {
+		t.Errorf("unexpected count %d, %v", count, err)
+	}
+	if err = fields["price"].As(&count); err == nil {
+		t.Error("expect error of decoding 2.5 into int")
+	}
+	if err = fields["missing"].As(&count); err == nil {
+		t.Error("expect error of missing field")
+	}
+}
+
+func TestConvertField2GrpcInt64(t *testing.T) {
+	// the non-negative integers are sent as uint64, the negative ones as double
+	if field, err := convertField2Grpc(&Field{Val: int64(1<<53 + 1)}); err != nil || field.GetValU64() != 1<<53+1 {
+		t.Errorf("expect 2^53+1 sent as uint64, got %v, %v", field, err)
+	}
+	if field, err := convertField2Grpc(&Field{Val: int64(-1 << 53)}); err != nil || field.GetValDouble() != -1<<53 {
+		t.Errorf("expect -2^53 sent exactly, got %v, %v", field, err)
+	}
+	if _, err := convertField2Grpc(&Field{Val: int64(-(1<<53 + 1))}); err == nil {
+		t.Error("expect -(2^53+1) rejected")
+	}
+	docs := []Document{{Id: "a", Fields: map[string]Field{"n": {Val: int64(-(1<<53 + 1))}}}}
+	_, err := (&rpcImplementerFlatDocument{}).Upsert(context.Background(), "db", "coll", docs)
+	if err == nil || !strings.Contains(err.Error(), "2^53") {
+		t.Errorf("expect the rpc upsert rejected without sending, got %v", err)
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvdbtext/encoder"
+)
+
+// The struct fields are mapped to the document by the `vdb` tag:
+//
+//	type Book struct {
+//		Id     string    `vdb:"id,primary"`
+//		Vector []float32 `vdb:"vector"`
+//		Author string
+//		Page   uint64    `vdb:"page,omitempty"`
+//		Note   string    `vdb:"-"`
+//	}
+//
+// The field without tag uses its name with the first letter lower cased, e.g. Author -> author.
+// The field named id or with primary option is the document id, which must be a string.
+// The field named vector is the vector ([]float32) or binary vector ([]byte),
+// and the field named sparse_vector is the sparse vector ([]encoder.SparseVecItem).
+// Other fields must be string, int, uint, float, bool or []string.
+// The field with omitempty option is not upserted if it is zero value, and the field tagged "-" is ignored.
+
+const vdbTagName = "vdb"
+
+type structField struct {
+	index     int
+	name      string
+	primary   bool
+	omitEmpty bool
+}
+
+var sparseVecItemsType = reflect.TypeOf([]encoder.SparseVecItem{})
+
+func parseStructFields(t reflect.Type) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tag := f.Tag.Get(vdbTagName)
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		field := structField{index: i, name: opts[0]}
+		if field.name == "" {
+			r, n := utf8.DecodeRuneInString(f.Name)
+			field.name = string(unicode.ToLower(r)) + f.Name[n:]
+		}
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "primary":
+				field.primary = true
+			case "omitempty":
+				field.omitEmpty = true
+			default:
+				return nil, fmt.Errorf("unknown option %s in vdb tag of field %s.%s", opt, t.Name(), f.Name)
+			}
+		}
+		if err := checkStructFieldType(field, f.Type); err != nil {
+			return nil, fmt.Errorf("field %s.%s: %v", t.Name(), f.Name, err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func checkStructFieldType(field structField, t reflect.Type) error {
+	switch {
+	case field.primary || field.name == "id":
+		if t.Kind() != reflect.String {
+			return fmt.Errorf("the document id must be string, but is %v", t)
+		}
+	case field.name == "vector":
+		if t != reflect.TypeOf([]float32{}) && t != reflect.TypeOf([]byte{}) {
+			return fmt.Errorf("the vector must be []float32 or []byte, but is %v", t)
+		}
+	case field.name == "sparse_vector":
+		if t != sparseVecItemsType {
+			return fmt.Errorf("the sparse_vector must be []encoder.SparseVecItem, but is %v", t)
+		}
+	default:
+		switch t.Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		case reflect.Slice:
+			if t.Elem().Kind() != reflect.String {
+				return fmt.Errorf("unsupported type %v, the array field must be []string", t)
+			}
+		default:
+			return fmt.Errorf("unsupported type %v", t)
+		}
+	}
+	return nil
+}
+
+// structSliceType returns the struct type of the slice element, which could be struct or pointer to struct.
+func structSliceType(t reflect.Type) (reflect.Type, bool, error) {
+	if t.Kind() != reflect.Slice {
+		return nil, false, fmt.Errorf("expect a slice of struct, but got %v", t)
+	}
+	elem := t.Elem()
+	isPtr := elem.Kind() == reflect.Ptr
+	if isPtr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, false, fmt.Errorf("expect a slice of struct, but got %v", t)
+	}
+	return elem, isPtr, nil
+}
+
+// EncodeDocuments convert a slice of struct, or pointer to struct, to documents by the `vdb` tags.
+func EncodeDocuments(structs interface{}) ([]Document, error) {
+	v := reflect.ValueOf(structs)
+	if !v.IsValid() {
+		return nil, fmt.Errorf("expect a slice of struct, but got nil")
+	}
+	elemType, isPtr, err := structSliceType(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseStructFields(elemType)
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]Document, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		sv := v.Index(i)
+		if isPtr {
+			if sv.IsNil() {
+				return nil, fmt.Errorf("structs[%d] is nil", i)
+			}
+			sv = sv.Elem()
+		}
+		doc := Document{Fields: make(map[string]Field)}
+		for _, field := range fields {
+			fv := sv.Field(field.index)
+			if field.omitEmpty && fv.IsZero() {
+				continue
+			}
+			switch {
+			case field.primary || field.name == "id":
+				doc.Id = fv.String()
+			case field.name == "vector":
+				if vector, ok := fv.Interface().([]byte); ok {
+					doc.BinaryVector = vector
+				} else {
+					doc.Vector = fv.Interface().([]float32)
+				}
+			case field.name == "sparse_vector":
+				doc.SparseVector = fv.Interface().([]encoder.SparseVecItem)
+			default:
+				doc.Fields[field.name] = Field{Val: encodeFieldValue(fv)}
+			}
+		}
+		documents = append(documents, doc)
+	}
+	return documents, nil
+}
+
+func encodeFieldValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	case reflect.Slice:
+		res := make([]string, v.Len())
+		for i := range res {
+			res[i] = v.Index(i).String()
+		}
+		return res
+	}
+	return v.Interface()
+}
+
+// DecodeDocuments convert the documents returned by Query or Search to the slice of struct,
+// out must be a pointer to a slice of struct, or pointer to struct. The fields not in a document keep zero value.
+func DecodeDocuments(documents []Document, out interface{}) error {
+	ptr := reflect.ValueOf(out)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return fmt.Errorf("expect a pointer to slice of struct, but got %T", out)
+	}
+	elemType, isPtr, err := structSliceType(ptr.Elem().Type())
+	if err != nil {
+		return err
+	}
+	fields, err := parseStructFields(elemType)
+	if err != nil {
+		return err
+	}
+	slice := reflect.MakeSlice(ptr.Elem().Type(), 0, len(documents))
+	for _, doc := range documents {
+		sv := reflect.New(elemType).Elem()
+		for _, field := range fields {
+			fv := sv.Field(field.index)
+			switch {
+			case field.primary || field.name == "id":
+				fv.SetString(doc.Id)
+			case field.name == "vector":
+				if fv.Type() == reflect.TypeOf([]byte{}) {
+					vector := doc.BinaryVector
+					if vector == nil && doc.Vector != nil {
+						vector = make([]byte, len(doc.Vector))
+						for i, b := range doc.Vector {
+							vector[i] = byte(b)
+						}
+					}
+					fv.SetBytes(vector)
+				} else {
+					fv.Set(reflect.ValueOf(doc.Vector))
+				}
+			case field.name == "sparse_vector":
+				fv.Set(reflect.ValueOf(doc.SparseVector))
+			default:
+				f, ok := doc.Fields[field.name]
+				if !ok || f.Val == nil {
+					continue
+				}
+				if err := decodeFieldValue(fv, f); err != nil {
+					return fmt.Errorf("document %s field %s: %v", doc.Id, field.name, err)
+				}
+			}
+		}
+		if isPtr {
+			sv = sv.Addr()
+		}
+		slice = reflect.Append(slice, sv)
+	}
+	ptr.Elem().Set(slice)
+	return nil
+}
+
+func decodeFieldValue(v reflect.Value, f Field) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := f.Val.(json.Number); ok {
+			i, err := n.Int64()
+			if err != nil {
+				return err
+			}
+			v.SetInt(i)
+			return nil
+		}
+		rv := reflect.ValueOf(f.Val)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(rv.Int())
+		default:
+			v.SetInt(int64(f.Uint64()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(f.Uint64())
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(f.Float())
+	case reflect.Bool:
+		b, ok := f.Val.(bool)
+		if !ok {
+			return fmt.Errorf("expect bool, but got %T", f.Val)
+		}
+		v.SetBool(b)
+	case reflect.String:
+		s, ok := f.Val.(string)
+		if !ok {
+			return fmt.Errorf("expect string, but got %T", f.Val)
+		}
+		v.SetString(s)
+	case reflect.Slice:
+		v.Set(reflect.ValueOf(f.StringArray()).Convert(v.Type()))
+	}
+	return nil
+}
+
+// UpsertStructs upsert the slice of struct, which is converted to documents by EncodeDocuments.
+func (c *Collection) UpsertStructs(ctx context.Context, structs interface{}, params ...*UpsertDocumentParams) (*UpsertDocumentResult, error) {
+	documents, err := EncodeDocuments(structs)
+	if err != nil {
+		return nil, err
+	}
+	return c.Upsert(ctx, documents, params...)
+}
+
+// QueryInto query the documents and decode them into out by DecodeDocuments.
+func (c *Collection) QueryInto(ctx context.Context, documentIds []string, out interface{}, params ...*QueryDocumentParams) (*QueryDocumentResult, error) {
+	res, err := c.Query(ctx, documentIds, params...)
+	if err != nil {
+		return nil, err
+	}
+	return res, DecodeDocuments(res.Documents, out)
+}
@@ -0,0 +1,71 @@
+package tcvectordb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testBook struct {
+	Id       string    `vdb:"id,primary"`
+	Vector   []float32 `vdb:"vector"`
+	Author   string
+	Page     uint64 `vdb:"page,omitempty"`
+	Offset   int
+	Score    float64
+	Free     bool
+	Tags     []string `vdb:"tags"`
+	Internal string   `vdb:"-"`
+}
+
+func TestEncodeDecodeDocuments(t *testing.T) {
+	books := []testBook{
+		{Id: "0001", Vector: []float32{0.1}, Author: "jerry", Page: 21, Offset: -1, Score: 0.5, Free: true, Tags: []string{"a"}, Internal: "x"},
+		{Id: "0002", Author: "tom"},
+	}
+	docs, err := EncodeDocuments(books)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docs[0].Id != "0001" || docs[0].Fields["author"].Val != "jerry" || docs[0].Fields["page"].Val != uint64(21) {
+		t.Errorf("unexpected document %+v", docs[0])
+	}
+	if _, ok := docs[1].Fields["page"]; ok {
+		t.Error("expect zero page omitted")
+	}
+	if _, ok := docs[0].Fields["internal"]; ok {
+		t.Error("expect field tagged - ignored")
+	}
+
+	// the fields are json.Number after query by http
+	docs[0].Fields["page"] = Field{Val: json.Number("21")}
+	docs[0].Fields["offset"] = Field{Val: json.Number("-1")}
+	docs[0].Fields["tags"] = Field{Val: []interface{}{"a"}}
+	var res []*testBook
+	if err = DecodeDocuments(docs, &res); err != nil {
+		t.Fatal(err)
+	}
+	books[0].Internal = ""
+	if len(res) != 2 || !reflect.DeepEqual(*res[0], books[0]) || res[1].Author != "tom" {
+		t.Errorf("unexpected decoded books %+v", res[0])
+	}
+}
+
+func TestEncodeDocumentsUnsupported(t *testing.T) {
+	type invalid struct {
+		Id   string
+		Meta map[string]string
+	}
+	if _, err := EncodeDocuments([]invalid{{}}); err == nil {
+		t.Error("expect error of unsupported map field")
+	}
+	if _, err := EncodeDocuments(testBook{}); err == nil {
+		t.Error("expect error of non slice")
+	}
+	if _, err := EncodeDocuments(nil); err == nil {
+		t.Error("expect error of nil")
+	}
+	if _, err := EncodeDocuments("books"); err == nil {
+		t.Error("expect error of string")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDryRun is returned by the requests recorded in dry run if DryRunOption.ReturnError is set.
+var ErrDryRun = errors.New("request is not sent in dry run")
+
+// DryRunOption records the requests of client instead of sending them to the server.
+type DryRunOption struct {
+	// Sink: write the recorded requests as json lines, which could be sent later by ReplayRequests.
+	// The requests are kept in memory for DryRunRequests only if Sink is nil.
+	Sink io.Writer
+	// ReturnError: the recorded requests fail with ErrDryRun, otherwise they succeed with the zero response
+	ReturnError bool
+	// ReadsPassThrough: send the read requests (query, search, describe, list and count) to the server as usual
+	ReadsPassThrough bool
+}
+
+// RecordedRequest is a request recorded in dry run.
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body"`
+}
+
+type dryRunRecorder struct {
+	option   DryRunOption
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+func newDryRunRecorder(option *DryRunOption) *dryRunRecorder {
+	if option == nil {
+		return nil
+	}
+	return &dryRunRecorder{option: *option}
+}
+
+// intercepts reports whether the request of path is recorded instead of sent.
+func (r *dryRunRecorder) intercepts(path string) bool {
+	if r == nil {
+		return false
+	}
+	return !r.option.ReadsPassThrough || !idempotentActions[path[strings.LastIndex(path, "/")+1:]]
+}
+
+func (r *dryRunRecorder) record(method, path string, req interface{}) error {
+	body, err := JSONCodec{}.Marshal(req)
+	if err != nil {
+		return errors.Wrapf(err, "marshal request of %s failed", path)
+	}
+	recorded := RecordedRequest{Method: method, Path: path, Body: bytes.TrimSpace(body)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.option.Sink == nil {
+		r.requests = append(r.requests, recorded)
+	} else {
+		line, err := json.Marshal(recorded)
+		if err != nil {
+			return err
+		}
+		if _, err = r.option.Sink.Write(append(line, '\n')); err != nil {
+			return errors.Wrap(err, "write recorded request failed")
+		}
+	}
+	if r.option.ReturnError {
+		return ErrDryRun
+	}
+	return nil
+}
+
+// DryRunRequests returns the requests recorded in dry run in order, nil if ClientOption.DryRun is not set
+// or its Sink is set, which the requests are written to instead.
+func (c *Client) DryRunRequests() []RecordedRequest {
+	if c.dryRun == nil {
+		return nil
+	}
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return append([]RecordedRequest(nil), c.dryRun.requests...)
+}
+
+// ReplayRequests sends the requests written to DryRunOption.Sink with cli in order.
+// It stops at the first failed request, and returns the number of the succeeded ones.
+// The recorded bodies are sent as they are, so the namespace of cli is not applied to them again.
+func ReplayRequests(ctx context.Context, cli *Client, r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxReplayLineBytes)
+	replayed := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req RecordedRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			return replayed, errors.Wrapf(err, "invalid recorded request at %d", replayed)
+		}
+		if err := cli.replay(ctx, req); err != nil {
+			return replayed, errors.Wrapf(err, "replay %s failed", req.Path)
+		}
+		replayed++
+	}
+	return replayed, scanner.Err()
+}
+
+// maxReplayLineBytes the max size of a recorded request, which is large enough for the max upsert.
+const maxReplayLineBytes = 256 << 20
+
+func (c *Client) replay(ctx context.Context, req RecordedRequest) error {
+	if err := c.lifecycle.begin(); err != nil {
+		return err
+	}
+	defer c.lifecycle.end()
+	if _, ok := ctx.Value(requestIDKey{}).(string); !ok {
+		ctx = WithRequestID(ctx, newRequestID())
+	}
+	return c.retry(ctx, req.Method, req.Path, req.Body, new(CommmonResponse), nil)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/collection"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/database"
+)
+
+func TestDryRun(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r.URL.Path+" "+strings.TrimSpace(string(body)))
+		mu.Unlock()
+		w.Write([]byte(`{"code":0,"collection":{"collection":"coll"}}`))
+	}
+	sink := bytes.NewBuffer(nil)
+	cli := newTestClient(t, handler, ClientOption{DryRun: &DryRunOption{Sink: sink, ReadsPassThrough: true}})
+
+	ctx := context.Background()
+	describe := new(collection.DescribeRes)
+	if err := cli.Request(ctx, &collection.DescribeReq{Database: "db", Collection: "coll"}, describe); err != nil {
+		t.Fatal(err)
+	}
+	if describe.Collection.Collection != "coll" {
+		t.Errorf("expect the read request sent, got %+v", describe)
+	}
+	for _, name := range []string{"db1", "db2"} {
+		if err := cli.Request(ctx, &database.CreateReq{Database: name}, new(database.CreateRes)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(received) != 1 {
+		t.Fatalf("expect only the read request sent, got %q", received)
+	}
+	if recorded := cli.DryRunRequests(); len(recorded) != 0 {
+		t.Errorf("expect the requests written to sink not kept in memory, got %+v", recorded)
+	}
+
+	failing := newTestClient(t, handler, ClientOption{DryRun: &DryRunOption{ReturnError: true}})
+	err := failing.Request(ctx, &collection.DescribeReq{Database: "db", Collection: "coll"}, new(collection.DescribeRes))
+	if !errors.Is(err, ErrDryRun) || len(received) != 1 {
+		t.Errorf("expect ErrDryRun without sending, got %v", err)
+	}
+	recorded := failing.DryRunRequests()
+	if len(recorded) != 1 || recorded[0].Path != "/collection/describe" || string(recorded[0].Body) != `{"database":"db","collection":"coll"}` {
+		t.Errorf("unexpected recorded requests %+v", recorded)
+	}
+
+	replayer := newTestClient(t, handler, ClientOption{})
+	n, err := ReplayRequests(ctx, replayer, sink)
+	if err != nil || n != 2 {
+		t.Fatalf("replay failed %d: %v", n, err)
+	}
+	if len(received) != 3 || received[1] != `/database/create {"database":"db1"}` || received[2] != `/database/create {"database":"db2"}` {
+		t.Errorf("expect the recorded requests replayed in order, got %q", received)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// endpoint a server url of the client, with its passive health state.
+type endpoint struct {
+	url string
+
+	mu        sync.Mutex
+	failures  int
+	downUntil time.Time
+}
+
+// report record the result of a request. The endpoint is skipped for probeInterval
+// after failing threshold times in a row, then it is probed by the next request.
+func (e *endpoint) report(failed bool, threshold int, probeInterval time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !failed {
+		e.failures = 0
+		e.downUntil = time.Time{}
+		return
+	}
+	e.failures++
+	if e.failures >= threshold {
+		e.downUntil = time.Now().Add(probeInterval)
+	}
+}
+
+// endpointPool pick endpoints by round-robin, skipping the unhealthy ones.
+type endpointPool struct {
+	endpoints []*endpoint
+	next      uint32
+}
+
+func newEndpointPool(urls []string) *endpointPool {
+	pool := new(endpointPool)
+	for _, url := range urls {
+		pool.endpoints = append(pool.endpoints, &endpoint{url: url})
+	}
+	return pool
+}
+
+// pick returns the next healthy endpoint, or the one recovering earliest if all are unhealthy.
+func (p *endpointPool) pick() *endpoint {
+	n := len(p.endpoints)
+	// the modulo is taken in uint32, since the counter converted to int is negative on 32-bit after 2^31 picks
+	start := int((atomic.AddUint32(&p.next, 1) - 1) % uint32(n))
+	now := time.Now()
+	var (
+		earliest      *endpoint
+		earliestUntil time.Time
+	)
+	for i := 0; i < n; i++ {
+		e := p.endpoints[(start+i)%n]
+		e.mu.Lock()
+		downUntil := e.downUntil
+		e.mu.Unlock()
+		if !now.Before(downUntil) {
+			return e
+		}
+		if earliest == nil || downUntil.Before(earliestUntil) {
+			earliest, earliestUntil = e, downUntil
+		}
+	}
+	return earliest
+}
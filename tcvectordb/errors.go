@@ -0,0 +1,293 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError the error returned by vectordb server, use errors.As to get it from the error of api.
+type APIError struct {
+	// Code: the code of response body, 0 if the body is not a valid response
+	Code int32
+	// Message: the msg of response body, or the whole body if it is not a valid response
+	Message string
+	// HTTPStatus: the http status code, 0 for the rpc client
+	HTTPStatus int
+	// RequestPath: the http path or the rpc method of the request
+	RequestPath string
+	// RequestID: the request id sent by the http client, generated or set by WithRequestID
+	RequestID string
+	// ServerRequestID: the request id returned by the server, empty if it is not returned
+	ServerRequestID string
+}
+
+// Is reports whether the error is ErrRateLimited, ErrRequestTooLarge, ErrUnauthorized,
+// ErrBackupInProgress or ErrTargetCollectionExists.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.HTTPStatus == http.StatusUnauthorized || e.HTTPStatus == http.StatusForbidden
+	case ErrRateLimited:
+		return e.HTTPStatus == http.StatusTooManyRequests
+	case ErrRequestTooLarge:
+		return e.HTTPStatus == http.StatusRequestEntityTooLarge
+	case ErrBackupInProgress:
+		if !strings.Contains(e.RequestPath, "/backup/") {
+			return false
+		}
+		// the message is matched only for the servers returning a general code
+		return e.Code == ERR_BACKUP_IN_PROGRESS || strings.Contains(strings.ToLower(e.Message), "in progress")
+	case ErrTargetCollectionExists:
+		if !strings.HasSuffix(e.RequestPath, "/backup/restore") {
+			return false
+		}
+		// the message is matched only for the servers returning a general code, the same as ErrBackupInProgress
+		return e.Code == ERR_COLLECTION_EXIST || strings.Contains(e.Message, "already exist")
+	}
+	return false
+}
+
+func (e *APIError) Error() string {
+	if e.HTTPStatus != 0 && e.HTTPStatus/100 != 2 {
+		return fmt.Sprintf("response code is %d, %s", e.HTTPStatus, e.Message)
+	}
+	return fmt.Sprintf("code: %d, message: %s", e.Code, e.Message)
+}
+
+// HTTPError is returned if the response of non-2xx status is not a response of vectordb,
+// such as the html page of a proxy or load balancer in front of the server.
+// It wraps the APIError of the status, so errors.As(err, &apiErr) works as well.
+type HTTPError struct {
+	StatusCode  int
+	ContentType string
+	// Body: the first 512 bytes of the body, with the whitespaces collapsed
+	Body string
+	// RequestPath: the http path of the request
+	RequestPath string
+
+	apiErr *APIError
+}
+
+func (e *HTTPError) Error() string {
+	if e.ContentType == "" {
+		return fmt.Sprintf("%s responds http status %d: %s", e.RequestPath, e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("%s responds http status %d with %s: %s", e.RequestPath, e.StatusCode, e.ContentType, e.Body)
+}
+
+// Unwrap returns the APIError of the status.
+func (e *HTTPError) Unwrap() error {
+	return e.apiErr
+}
+
+// SchemaMismatchError is returned by CreateCollectionIfNotExists if the existing collection
+// has different indexes from the requested ones.
+type SchemaMismatchError struct {
+	Collection string
+	// Diffs: the description of each different index
+	Diffs []string
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf("collection %s exists with different indexes: %s", e.Collection, strings.Join(e.Diffs, "; "))
+}
+
+// RenameCollectionError is returned by ModifyCollection if the collection could not be renamed.
+type RenameCollectionError struct {
+	Collection string
+	NewName    string
+	// Exists: the collection of NewName already exists
+	Exists bool
+	// Aliases: the aliases of collection, which are moved only with ModifyCollectionParams.MoveAliases
+	Aliases []string
+}
+
+func (e *RenameCollectionError) Error() string {
+	if e.Exists {
+		return fmt.Sprintf("rename collection %s failed, collection %s already exists", e.Collection, e.NewName)
+	}
+	return fmt.Sprintf("rename collection %s to %s failed, the aliases %s point at it, set MoveAliases to move them",
+		e.Collection, e.NewName, strings.Join(e.Aliases, ", "))
+}
+
+// WarningError is returned instead of the result if ClientOption.StrictWarnings is set
+// and the server responds a write request with warning.
+type WarningError struct {
+	Warning string
+	// RequestPath: the http path of the request
+	RequestPath string
+}
+
+func (e *WarningError) Error() string {
+	return fmt.Sprintf("%s responds warning: %s", e.RequestPath, e.Warning)
+}
+
+// ZeroVectorError is returned with the result if ClientOption.AutoNormalize is set and some of the vectors
+// are zero, which could not be normalized and are sent as they are.
+type ZeroVectorError struct {
+	// DocumentIds: the ids of the upserted documents with zero vector
+	DocumentIds []string
+	// Vectors: the indexes of the zero search vectors
+	Vectors []int
+}
+
+func (e *ZeroVectorError) Error() string {
+	if len(e.DocumentIds) != 0 {
+		return fmt.Sprintf("the zero vectors of documents %s are not normalized", strings.Join(e.DocumentIds, ", "))
+	}
+	return fmt.Sprintf("the zero search vectors %v are not normalized", e.Vectors)
+}
+
+// RequestTooLargeError is returned without sending the request if its size exceeds ClientOption.MaxRequestBytes.
+type RequestTooLargeError struct {
+	// RequestPath: the http path of the request
+	RequestPath string
+	Size        int
+	Limit       int
+	// FitDocuments: the estimated number of documents fitting the limit, 0 if the request is not an upsert
+	FitDocuments int
+}
+
+func (e *RequestTooLargeError) Error() string {
+	msg := fmt.Sprintf("%s request of %d bytes exceeds the limit %d bytes", e.RequestPath, e.Size, e.Limit)
+	if e.FitDocuments > 0 {
+		msg += fmt.Sprintf(", about %d documents fit the limit, set UpsertDocumentParams.BatchSize to split them", e.FitDocuments)
+	}
+	return msg
+}
+
+// Is reports whether the error is ErrRequestTooLarge.
+func (e *RequestTooLargeError) Is(target error) bool {
+	return target == ErrRequestTooLarge
+}
+
+// ErrRequestTooLarge matches the RequestTooLargeError, and the APIError of http status 413.
+var ErrRequestTooLarge = errors.New("request too large")
+
+// ErrUnauthorized matches the APIError and HTTPError of http status 401 and 403,
+// returned if the username or key is wrong, or the account has no permission.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrRateLimited matches the APIError returned because the requests exceed the quota of server, which is
+// the http status 429 or the rpc status ResourceExhausted. Use errors.Is(err, ErrRateLimited) to check it and back off.
+var ErrRateLimited = errors.New("rate limited by server")
+
+// ErrClientClosed is returned by the requests of client after Close or Shutdown.
+var ErrClientClosed = errors.New("client is closed")
+
+// ErrScanLimitReached is returned with the documents found so far by Collection.ScanByIdPrefix
+// if maxScanned documents are scanned.
+var ErrScanLimitReached = errors.New("scan limit reached")
+
+// ErrDocumentNotExist is returned by Collection.Get if the document is not found.
+var ErrDocumentNotExist = errors.New("document not exist")
+
+// ErrBackupInProgress matches the APIError of backup api returned because the collection
+// is being backed up or restored, by the code ERR_BACKUP_IN_PROGRESS, or by the message
+// containing "in progress" if the server returns a general code.
+var ErrBackupInProgress = errors.New("backup in progress")
+
+// ErrTargetCollectionExists matches the APIError of RestoreBackup returned because the target collection exists,
+// by the code ERR_COLLECTION_EXIST, or by the message containing "already exist" if the server returns a general code.
+var ErrTargetCollectionExists = errors.New("target collection exists")
+
+// UnknownOutputFieldError is returned with ClientOption.StrictOutputFields if an output field
+// is not known by the schema of collection.
+type UnknownOutputFieldError struct {
+	Collection string
+	Field      string
+	// Suggestions: the known fields close to Field, which could be misspelled
+	Suggestions []string
+	// Recognized: the output fields known by the schema
+	Recognized []string
+}
+
+func (e *UnknownOutputFieldError) Error() string {
+	msg := fmt.Sprintf("unknown output field %s of collection %s", e.Field, e.Collection)
+	if len(e.Suggestions) != 0 {
+		msg += fmt.Sprintf(", did you mean %s", strings.Join(e.Suggestions, " or "))
+	}
+	return msg + fmt.Sprintf(", the recognized fields are %v", e.Recognized)
+}
+
+// Is reports whether the error is ErrUnknownOutputField.
+func (e *UnknownOutputFieldError) Is(target error) bool {
+	return target == ErrUnknownOutputField
+}
+
+// ErrUnknownOutputField matches the UnknownOutputFieldError.
+var ErrUnknownOutputField = errors.New("unknown output field")
+
+// RequestIDFromError returns the request id of server of the APIError, or the request id of client
+// if the server returns none, used for the support tickets. It is empty if err is not an APIError.
+func RequestIDFromError(err error) string {
+	apiErr, ok := asAPIError(err)
+	if !ok {
+		return ""
+	}
+	if apiErr.ServerRequestID != "" {
+		return apiErr.ServerRequestID
+	}
+	return apiErr.RequestID
+}
+
+func asAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+// IsDatabaseNotExist reports whether err is returned because the database does not exist.
+func IsDatabaseNotExist(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.Code == ERR_UNDEFINED_DATABASE
+}
+
+// IsCollectionNotExist reports whether err is returned because the collection does not exist.
+func IsCollectionNotExist(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.Code == ERR_UNDEFINED_COLLECTION
+}
+
+// IsAlreadyExist reports whether err is returned because the database, collection or alias already exists,
+// by the code ERR_COLLECTION_EXIST, or by the message containing "already exist" for the other codes.
+func IsAlreadyExist(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.Code == ERR_COLLECTION_EXIST || strings.Contains(apiErr.Message, "already exist"))
+}
+
+// IsPermissionDenied reports whether err is returned because the account has no permission.
+func IsPermissionDenied(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.HTTPStatus == http.StatusUnauthorized || apiErr.HTTPStatus == http.StatusForbidden ||
+		strings.Contains(strings.ToLower(apiErr.Message), "permission denied"))
+}
+
+// isNotExist reports whether err is returned by dropping a database or collection which does not exist.
+func isNotExist(err error) bool {
+	if IsDatabaseNotExist(err) || IsCollectionNotExist(err) {
+		return true
+	}
+	apiErr, ok := asAPIError(err)
+	return ok && (strings.Contains(apiErr.Message, "not exist") || strings.Contains(apiErr.Message, "can not find database"))
+}
@@ -0,0 +1,99 @@
+package tcvectordb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/collection"
+)
+
+func TestAPIError(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":15302,"msg":"collection not exist"}`))
+	}, ClientOption{})
+
+	_, err := cli.Database("db").DescribeCollection(context.Background(), "coll")
+	apiErr, ok := asAPIError(err)
+	if !ok {
+		t.Fatalf("expect APIError, got %v", err)
+	}
+	if apiErr.Code != ERR_UNDEFINED_COLLECTION || apiErr.RequestPath != "/collection/describe" || !IsCollectionNotExist(err) {
+		t.Errorf("unexpected APIError: %+v", apiErr)
+	}
+	if IsDatabaseNotExist(err) || IsPermissionDenied(err) {
+		t.Errorf("unexpected error kind of %v", err)
+	}
+
+	exists, err := cli.Database("db").ExistsCollection(context.Background(), "coll")
+	if err != nil || exists {
+		t.Errorf("expect collection not exists, got %v, %v", exists, err)
+	}
+}
+
+func TestIsAlreadyExist(t *testing.T) {
+	if !IsAlreadyExist(&APIError{Code: ERR_COLLECTION_EXIST, Message: "collection coll is duplicated"}) {
+		t.Error("expect the error matched by code")
+	}
+	if !IsAlreadyExist(&APIError{Code: 1, Message: "database db already exist"}) {
+		t.Error("expect the error matched by message")
+	}
+	if IsAlreadyExist(&APIError{Code: ERR_UNDEFINED_COLLECTION, Message: "collection not exist"}) {
+		t.Error("expect the not exist error not matched")
+	}
+}
+
+func TestAPIErrorHTTPStatus(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":1,"msg":"unauthorized"}`))
+	}, ClientOption{})
+
+	err := cli.Request(context.Background(), &collection.ListReq{Database: "db"}, new(collection.ListRes))
+	if !IsPermissionDenied(err) || !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expect permission denied, got %v", err)
+	}
+	if apiErr, _ := asAPIError(err); apiErr == nil || apiErr.Code != 1 || apiErr.HTTPStatus != http.StatusUnauthorized {
+		t.Errorf("unexpected APIError: %+v", apiErr)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	status := http.StatusBadGateway
+	body := "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>" + strings.Repeat("x", 1024) + "</body></html>"
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}, ClientOption{})
+
+	err := cli.Request(context.Background(), &collection.CreateReq{Database: "db"}, new(collection.CreateRes))
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expect HTTPError, got %v", err)
+	}
+	if httpErr.StatusCode != status || httpErr.ContentType != "text/html" || httpErr.RequestPath != "/collection/create" ||
+		len(httpErr.Body) > 520 || !strings.HasPrefix(httpErr.Body, "<html> <head><title>502 Bad Gateway</title></head> <body>xxx") {
+		t.Errorf("unexpected HTTPError %+v", httpErr)
+	}
+	if apiErr, ok := asAPIError(err); !ok || apiErr.HTTPStatus != status || apiErr.Message != httpErr.Body {
+		t.Errorf("expect APIError wrapped, got %+v", apiErr)
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expect 502 not unauthorized")
+	}
+
+	status = http.StatusForbidden
+	err = cli.Request(context.Background(), &collection.CreateReq{Database: "db"}, new(collection.CreateRes))
+	if !errors.Is(err, ErrUnauthorized) || !IsPermissionDenied(err) || !errors.As(err, &httpErr) {
+		t.Errorf("expect unauthorized HTTPError, got %v", err)
+	}
+
+	status = http.StatusOK
+	err = cli.Request(context.Background(), &collection.CreateReq{Database: "db"}, new(collection.CreateRes))
+	if err == nil || !strings.Contains(err.Error(), "/collection/create") || len(err.Error()) > 200 || errors.As(err, &httpErr) {
+		t.Errorf("expect invalid content error with the path and first bytes, got %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"io"
+	"strings"
+)
+
+// FieldCodec transforms the string value of a scalar field, see Collection.RegisterFieldCodec.
+type FieldCodec interface {
+	// Encode encodes the value upserted or updated
+	Encode(value string) (string, error)
+	// Decode decodes the value queried or searched, ok is false if the value is not a payload of the codec
+	Decode(value string) (decoded string, ok bool)
+}
+
+// GzipBase64 the FieldCodec compressing the value by gzip, which is stored in base64.
+type GzipBase64 struct{}
+
+// gzipBase64Prefix the base64 of the magic number and the deflate method of gzip.
+const gzipBase64Prefix = "H4sI"
+
+func (GzipBase64) Encode(value string) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	w := gzip.NewWriter(buf)
+	if _, err := io.WriteString(w, value); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func (GzipBase64) Decode(value string) (string, bool) {
+	if !strings.HasPrefix(value, gzipBase64Prefix) {
+		return value, false
+	}
+	compressed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return value, false
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return value, false
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return value, false
+	}
+	return string(decoded), true
+}
+
+// RegisterFieldCodec encodes the string values of the field by codec in Upsert and Update, and decodes them
+// in the documents of Query and Search, so the callers read the decoded values by Field.String. The values
+// which are not payloads of codec are returned as they are, with Field.RawPassthrough set.
+// The field could not be filtered by its decoded value, the other fields are not affected.
+// The codecs must be registered before the collection is used, and they are kept by WithDefaults and Session.
+func (c *Collection) RegisterFieldCodec(fieldName string, codec FieldCodec) {
+	if d, ok := c.DocumentInterface.(*fieldCodecDocument); ok {
+		d.codecs[fieldName] = codec
+		return
+	}
+	c.DocumentInterface = &fieldCodecDocument{DocumentInterface: c.DocumentInterface, codecs: map[string]FieldCodec{fieldName: codec}}
+}
+
+// fieldCodecDocument applies the FieldCodecs of collection to the documents.
+type fieldCodecDocument struct {
+	DocumentInterface
+	codecs map[string]FieldCodec
+}
+
+func (d *fieldCodecDocument) Upsert(ctx context.Context, documents interface{}, params ...*UpsertDocumentParams) (*UpsertDocumentResult, error) {
+	documents, err := d.encodeDocuments(documents)
+	if err != nil {
+		return nil, err
+	}
+	return d.DocumentInterface.Upsert(ctx, documents, params...)
+}
+
+func (d *fieldCodecDocument) Update(ctx context.Context, param UpdateDocumentParams) (*UpdateDocumentResult, error) {
+	switch fields := param.UpdateFields.(type) {
+	case map[string]Field:
+		encoded, err := d.encodeFields(fields)
+		if err != nil {
+			return nil, err
+		}
+		param.UpdateFields = encoded
+	case map[string]interface{}:
+		encoded, err := d.encodeMap(fields)
+		if err != nil {
+			return nil, err
+		}
+		param.UpdateFields = encoded
+	}
+	return d.DocumentInterface.Update(ctx, param)
+}
+
+func (d *fieldCodecDocument) Query(ctx context.Context, documentIds []string, params ...*QueryDocumentParams) (*QueryDocumentResult, error) {
+	res, err := d.DocumentInterface.Query(ctx, documentIds, params...)
+	if res != nil {
+		d.decodeDocuments(res.Documents)
+	}
+	return res, err
+}
+
+func (d *fieldCodecDocument) QueryIterator(ctx context.Context, filter *Filter, batchSize int64, params ...*QueryDocumentParams) *QueryIterator {
+	return newQueryIterator(ctx, filter, batchSize, params, func(ctx context.Context, param *QueryDocumentParams) (*QueryDocumentResult, error) {
+		return d.Query(ctx, nil, param)
+	})
+}
+
+func (d *fieldCodecDocument) Search(ctx context.Context, vectors [][]float32, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	return d.decodeSearch(d.DocumentInterface.Search(ctx, vectors, params...))
+}
+
+func (d *fieldCodecDocument) SearchBinary(ctx context.Context, vectors [][]byte, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	return d.decodeSearch(d.DocumentInterface.SearchBinary(ctx, vectors, params...))
+}
+
+func (d *fieldCodecDocument) HybridSearch(ctx context.Context, params HybridSearchDocumentParams) (*SearchDocumentResult, error) {
+	return d.decodeSearch(d.DocumentInterface.HybridSearch(ctx, params))
+}
+
+func (d *fieldCodecDocument) SearchById(ctx context.Context, documentIds []string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	return d.decodeSearch(d.DocumentInterface.SearchById(ctx, documentIds, params...))
+}
+
+func (d *fieldCodecDocument) SearchByText(ctx context.Context, text map[string][]string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	return d.decodeSearch(d.DocumentInterface.SearchByText(ctx, text, params...))
+}
+
+func (d *fieldCodecDocument) decodeSearch(res *SearchDocumentResult, err error) (*SearchDocumentResult, error) {
+	if res != nil {
+		for _, docs := range res.Documents {
+			d.decodeDocuments(docs)
+		}
+	}
+	return res, err
+}
+
+// encodeDocuments returns the copy of documents whose fields are encoded, the documents passed in are not modified.
+func (d *fieldCodecDocument) encodeDocuments(documents interface{}) (interface{}, error) {
+	switch docs := documents.(type) {
+	case []Document:
+		encoded := make([]Document, len(docs))
+		for i, doc := range docs {
+			fields, err := d.encodeFields(doc.Fields)
+			if err != nil {
+				return nil, err
+			}
+			doc.Fields = fields
+			encoded[i] = doc
+		}
+		return encoded, nil
+	case []map[string]interface{}:
+		encoded := make([]map[string]interface{}, len(docs))
+		for i, doc := range docs {
+			m, err := d.encodeMap(doc)
+			if err != nil {
+				return nil, err
+			}
+			encoded[i] = m
+		}
+		return encoded, nil
+	}
+	return documents, nil
+}
+
+// encodeFields returns fields if none of them is encoded, or else the copy with the encoded values.
+func (d *fieldCodecDocument) encodeFields(fields map[string]Field) (map[string]Field, error) {
+	var encoded map[string]Field
+	for name, codec := range d.codecs {
+		value, ok := fields[name].Val.(string)
+		if !ok {
+			continue
+		}
+		if encoded == nil {
+			encoded = make(map[string]Field, len(fields))
+			for k, v := range fields {
+				encoded[k] = v
+			}
+		}
+		value, err := codec.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+		encoded[name] = Field{Val: value}
+	}
+	if encoded == nil {
+		return fields, nil
+	}
+	return encoded, nil
+}
+
+// encodeMap is encodeFields of the document map.
+func (d *fieldCodecDocument) encodeMap(doc map[string]interface{}) (map[string]interface{}, error) {
+	var encoded map[string]interface{}
+	for name, codec := range d.codecs {
+		value, ok := doc[name].(string)
+		if !ok {
+			continue
+		}
+		if encoded == nil {
+			encoded = make(map[string]interface{}, len(doc))
+			for k, v := range doc {
+				encoded[k] = v
+			}
+		}
+		value, err := codec.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+		encoded[name] = value
+	}
+	if encoded == nil {
+		return doc, nil
+	}
+	return encoded, nil
+}
+
+// decodeDocuments decodes the fields of docs in place, the fields absent are skipped.
+func (d *fieldCodecDocument) decodeDocuments(docs []Document) {
+	for _, doc := range docs {
+		for name, codec := range d.codecs {
+			field, ok := doc.Fields[name]
+			if !ok {
+				continue
+			}
+			value, ok := field.Val.(string)
+			if !ok {
+				doc.Fields[name] = Field{Val: field.Val, RawPassthrough: true}
+				continue
+			}
+			decoded, ok := codec.Decode(value)
+			doc.Fields[name] = Field{Val: decoded, RawPassthrough: !ok}
+		}
+	}
+}
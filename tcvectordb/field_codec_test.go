@@ -0,0 +1,80 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFieldCodec(t *testing.T) {
+	text := strings.Repeat("the chunk text stored along the vector. ", 50)
+	var stored string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/document/upsert":
+			docs := body["documents"].([]interface{})
+			stored, _ = docs[0].(map[string]interface{})["text"].(string)
+			if docs[0].(map[string]interface{})["author"] != "ann" || docs[1].(map[string]interface{})["text"] != nil {
+				t.Errorf("expect only the text field encoded, got %v", docs)
+			}
+			w.Write([]byte(`{"code":0,"affectedCount":2}`))
+		case "/document/query":
+			if fields, _ := body["query"].(map[string]interface{})["outputFields"].([]interface{}); len(fields) == 1 {
+				w.Write([]byte(`{"code":0,"documents":[{"id":"new","author":"ann"}]}`))
+				return
+			}
+			fmt.Fprintf(w, `{"code":0,"documents":[{"id":"new","text":%q},{"id":"old","text":"old text"},{"id":"none"}]}`, stored)
+		case "/document/search":
+			fmt.Fprintf(w, `{"code":0,"documents":[[{"id":"new","score":1,"text":%q}]]}`, stored)
+		}
+	}, ClientOption{})
+	ctx := context.Background()
+
+	coll := cli.Database("db").Collection("coll")
+	coll.RegisterFieldCodec("text", GzipBase64{})
+	docs := []Document{
+		{Id: "new", Vector: []float32{1, 0}, Fields: map[string]Field{"text": {Val: text}, "author": {Val: "ann"}}},
+		{Id: "none", Vector: []float32{0, 1}},
+	}
+	if _, err := coll.Upsert(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored, gzipBase64Prefix) || len(stored) >= len(text) || docs[0].Fields["text"].Val != text {
+		t.Fatalf("expect the text compressed without modifying the document, got %d bytes %q", len(stored), stored)
+	}
+
+	res, err := coll.Query(ctx, []string{"new", "old", "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if field := res.Documents[0].Fields["text"]; field.String() != text || field.RawPassthrough {
+		t.Errorf("expect the text decoded, got %+v", field)
+	}
+	if field := res.Documents[1].Fields["text"]; field.String() != "old text" || !field.RawPassthrough {
+		t.Errorf("expect the uncompressed text passed through, got %+v", field)
+	}
+	if _, ok := res.Documents[2].Fields["text"]; ok {
+		t.Errorf("expect the absent field skipped, got %+v", res.Documents[2].Fields)
+	}
+
+	res, err = coll.Query(ctx, []string{"new"}, &QueryDocumentParams{OutputFields: []string{"author"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.Documents[0].Fields["text"]; ok || res.Documents[0].Fields["author"].String() != "ann" {
+		t.Errorf("expect the projected fields kept, got %+v", res.Documents[0].Fields)
+	}
+
+	search, err := coll.WithDefaults(CallOptions{ReadConsistency: StrongConsistency}).Search(ctx, [][]float32{{1, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if search.Documents[0][0].Fields["text"].String() != text {
+		t.Errorf("expect the text of search decoded by the handle with defaults, got %+v", search.Documents[0][0].Fields)
+	}
+}
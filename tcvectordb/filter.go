@@ -19,8 +19,10 @@
 package tcvectordb
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -87,84 +89,64 @@ func (f *Filter) OrNot(cond string) *Filter {
 // In `in` condition function,
 // use with other condition. eg: And(In("key1", []string{"value1"})).And(In("key2", []int{2}))
 func In(key string, list interface{}) string {
-	if reflect.TypeOf(list).Kind() != reflect.Slice &&
-		reflect.TypeOf(list).Kind() != reflect.Array {
-		return ""
-	}
-	values := reflect.ValueOf(list)
-	if values.Len() == 0 {
-		return ""
-	}
-	var b strings.Builder
-	for i := 0; i < values.Len(); i++ {
-		b.WriteString(",")
-		v := values.Index(i)
-		if v.Kind() == reflect.String {
-			b.WriteString(fmt.Sprintf(`"%v"`, v.Interface()))
-		} else {
-			b.WriteString(fmt.Sprintf(`%v`, v.Interface()))
-		}
-	}
-	if b.Len() != 0 {
-		return fmt.Sprintf("%s in (%s)", key, b.String()[1:])
-	}
-	return ""
+	return listCond(key, "in", list)
 }
 
-// In `not in` condition function,
+// NotIn `not in` condition function,
 // use with other condition. eg: And(NotIn("key1", []string{"value1", "value2"}))
 func NotIn(key string, list interface{}) string {
-	if reflect.TypeOf(list).Kind() != reflect.Slice &&
-		reflect.TypeOf(list).Kind() != reflect.Array {
-		return ""
-	}
-	values := reflect.ValueOf(list)
-	if values.Len() == 0 {
-		return ""
-	}
-	var b strings.Builder
-	for i := 0; i < values.Len(); i++ {
-		b.WriteString(",")
-		v := values.Index(i)
-		if v.Kind() == reflect.String {
-			b.WriteString(fmt.Sprintf(`"%v"`, v.Interface()))
-		} else {
-			b.WriteString(fmt.Sprintf(`%v`, v.Interface()))
-		}
-	}
-	if b.Len() != 0 {
-		return fmt.Sprintf("%s not in (%s)", key, b.String()[1:])
-	}
-	return ""
+	return listCond(key, "not in", list)
 }
 
+// Include `include` condition function of the array field, matches if the array has any of the values.
+// eg: And(Include("tags", []string{"value1", "value2"}))
 func Include(key string, list interface{}) string {
-	if reflect.TypeOf(list).Kind() != reflect.Slice &&
-		reflect.TypeOf(list).Kind() != reflect.Array {
-		return ""
-	}
-	values := reflect.ValueOf(list)
-	if values.Len() == 0 {
-		return ""
-	}
-	var b strings.Builder
-	for i := 0; i < values.Len(); i++ {
-		b.WriteString(",")
-		v := values.Index(i)
-		if v.Kind() == reflect.String {
-			b.WriteString(fmt.Sprintf(`"%v"`, v.Interface()))
-		} else {
-			b.WriteString(fmt.Sprintf(`%v`, v.Interface()))
-		}
-	}
-	if b.Len() != 0 {
-		return fmt.Sprintf("%s include (%s)", key, b.String()[1:])
-	}
-	return ""
+	return listCond(key, "include", list)
 }
 
+// Exclude `exclude` condition function of the array field, matches if the array has none of the values.
 func Exclude(key string, list interface{}) string {
-	if reflect.TypeOf(list).Kind() != reflect.Slice &&
+	return listCond(key, "exclude", list)
+}
+
+// IncludeAll `include all` condition function of the array field, matches if the array has all of the values.
+func IncludeAll(key string, list interface{}) string {
+	return listCond(key, "include all", list)
+}
+
+// Eq `=` condition function, the string value is quoted. eg: And(Eq("author", "jerry")).And(Eq("page", 10))
+func Eq(key string, value interface{}) string {
+	return fmt.Sprintf("%s = %s", key, formatValue(value))
+}
+
+// Ne `!=` condition function, the string value is quoted.
+func Ne(key string, value interface{}) string {
+	return fmt.Sprintf("%s != %s", key, formatValue(value))
+}
+
+// Gt `>` condition function. eg: And(Gte("page", 10)).And(Lt("page", 20))
+func Gt(key string, value interface{}) string {
+	return fmt.Sprintf("%s > %s", key, formatValue(value))
+}
+
+// Gte `>=` condition function.
+func Gte(key string, value interface{}) string {
+	return fmt.Sprintf("%s >= %s", key, formatValue(value))
+}
+
+// Lt `<` condition function.
+func Lt(key string, value interface{}) string {
+	return fmt.Sprintf("%s < %s", key, formatValue(value))
+}
+
+// Lte `<=` condition function.
+func Lte(key string, value interface{}) string {
+	return fmt.Sprintf("%s <= %s", key, formatValue(value))
+}
+
+// listCond returns the condition of key with op and the values of list, empty if list is not a slice or is empty.
+func listCond(key, op string, list interface{}) string {
+	if list == nil || reflect.TypeOf(list).Kind() != reflect.Slice &&
 		reflect.TypeOf(list).Kind() != reflect.Array {
 		return ""
 	}
@@ -174,43 +156,62 @@ func Exclude(key string, list interface{}) string {
 	}
 	var b strings.Builder
 	for i := 0; i < values.Len(); i++ {
-		b.WriteString(",")
-		v := values.Index(i)
-		if v.Kind() == reflect.String {
-			b.WriteString(fmt.Sprintf(`"%v"`, v.Interface()))
-		} else {
-			b.WriteString(fmt.Sprintf(`%v`, v.Interface()))
+		if i != 0 {
+			b.WriteString(",")
 		}
+		b.WriteString(formatValue(values.Index(i).Interface()))
 	}
-	if b.Len() != 0 {
-		return fmt.Sprintf("%s exclude (%s)", key, b.String()[1:])
-	}
-	return ""
+	return fmt.Sprintf("%s %s (%s)", key, op, b.String())
 }
 
-func IncludeAll(key string, list interface{}) string {
-	if reflect.TypeOf(list).Kind() != reflect.Slice &&
-		reflect.TypeOf(list).Kind() != reflect.Array {
-		return ""
-	}
-	values := reflect.ValueOf(list)
-	if values.Len() == 0 {
-		return ""
-	}
-	var b strings.Builder
-	for i := 0; i < values.Len(); i++ {
-		b.WriteString(",")
-		v := values.Index(i)
-		if v.Kind() == reflect.String {
-			b.WriteString(fmt.Sprintf(`"%v"`, v.Interface()))
-		} else {
-			b.WriteString(fmt.Sprintf(`%v`, v.Interface()))
+// formatValue returns the string value quoted with the backslashes and double quotes escaped,
+// the float value in decimal without exponent, and the other values as they are.
+func formatValue(value interface{}) string {
+	if n, ok := value.(json.Number); ok {
+		return n.String()
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		s := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v.String())
+		return `"` + s + `"`
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Build returns the condition of filter, or an error if its parentheses are not balanced
+// or a string value is not closed.
+func (f *Filter) Build() (string, error) {
+	cond := f.Cond()
+	depth := 0
+	quoted := false
+	for i := 0; i < len(cond); i++ {
+		switch c := cond[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return "", fmt.Errorf("invalid filter %s, unexpected ) at %d", cond, i)
+			}
 		}
 	}
-	if b.Len() != 0 {
-		return fmt.Sprintf("%s include all (%s)", key, b.String()[1:])
+	if quoted {
+		return "", fmt.Errorf("invalid filter %s, unclosed string value", cond)
+	}
+	if depth != 0 {
+		return "", fmt.Errorf("invalid filter %s, unbalanced parentheses", cond)
 	}
-	return ""
+	return cond, nil
 }
 
 func (f *Filter) Cond() string {
@@ -0,0 +1,42 @@
+package tcvectordb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFilterConditions(t *testing.T) {
+	cases := map[string]string{
+		Eq("author", `say "hi" \ 你好`):                   `author = "say \"hi\" \\ 你好"`,
+		Ne("page", uint64(10)):                          `page != 10`,
+		Gte("page", 10) + " and " + Lt("page", 20.5):    `page >= 10 and page < 20.5`,
+		NotIn("author", []string{"a", `b"`}):            `author not in ("a","b\"")`,
+		IncludeAll("tags", []interface{}{"x", 1}):       `tags include all ("x",1)`,
+		In("author", []string{}):                        ``,
+		Gt("price", 1e21):                               `price > 1000000000000000000000`,
+		Lt("price", float32(0.1)):                       `price < 0.1`,
+		Lte("price", 1.5e-7):                            `price <= 0.00000015`,
+		In("price", []float64{-2, 0.25}):                `price in (-2,0.25)`,
+		Eq("page", json.Number("18446744073709551615")): `page = 18446744073709551615`,
+	}
+	for cond, expect := range cases {
+		if cond != expect {
+			t.Errorf("expect %s, got %s", expect, cond)
+		}
+	}
+}
+
+func TestFilterBuild(t *testing.T) {
+	cond, err := NewFilter(Eq("author", "(a")).And(Include("tags", []string{"x)"})).OrNot(Gt("page", 1)).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond != `author = "(a" and (tags include ("x)")) or not (page > 1)` {
+		t.Errorf("unexpected filter %s", cond)
+	}
+	for _, invalid := range []string{`(a = 1`, `a = 1)`, `a = "1`} {
+		if _, err = NewFilter(invalid).Build(); err == nil {
+			t.Errorf("expect error of filter %s", invalid)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package tcvectordb
 
 import (
+	"fmt"
+
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
 )
 
@@ -15,10 +17,30 @@ func ConvertDbType(dataType olama.DataType) string {
 	}
 }
 
+// maxExactInt64 the max magnitude of int64 sent as the double of rpc without precision loss.
+const maxExactInt64 = 1 << 53
+
+// ConvertField2Grpc converts the field to the field of rpc, the Int64 beyond ±2^53 loses precision
+// as the proto has no int64 value, which is rejected by the rpc client before sending.
 func ConvertField2Grpc(field *Field) (result *olama.Field) {
+	result, _ = convertField2Grpc(field)
+	return
+}
+
+// convertField2Grpc is ConvertField2Grpc returning error if the Int64 could not be sent exactly.
+func convertField2Grpc(field *Field) (result *olama.Field, err error) {
 	switch field.Type() {
 	case Uint64:
 		result = &olama.Field{OneofVal: &olama.Field_ValU64{ValU64: field.Uint64()}}
+	case Float64:
+		result = &olama.Field{OneofVal: &olama.Field_ValDouble{ValDouble: field.Float64()}}
+	case Int64:
+		// the proto has no int64 value, the integer is exact within 2^53
+		n := field.Int64()
+		if n > maxExactInt64 || n < -maxExactInt64 {
+			err = fmt.Errorf("int64 value %d is beyond ±2^53, which loses precision by rpc, use the http client instead", n)
+		}
+		result = &olama.Field{OneofVal: &olama.Field_ValDouble{ValDouble: float64(n)}}
 	case String:
 		result = &olama.Field{OneofVal: &olama.Field_ValStr{ValStr: []byte(field.String())}}
 	case Array:
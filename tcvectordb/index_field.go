@@ -26,6 +26,7 @@ type Indexes struct {
 	VectorIndex       []VectorIndex
 	FilterIndex       []FilterIndex
 	SparseVectorIndex []SparseVectorIndex
+	BinaryVectorIndex []BinaryVectorIndex
 }
 
 type SparseVectorIndex struct {
@@ -35,17 +36,40 @@ type SparseVectorIndex struct {
 	MetricType MetricType
 }
 
+// BinaryVectorIndex the index of binary vector, the Dimension is the number of bits,
+// which must be a multiple of 8.
+type BinaryVectorIndex struct {
+	FieldName    string
+	FieldType    FieldType
+	IndexType    IndexType
+	Dimension    uint32
+	MetricType   MetricType
+	IndexedCount uint64
+}
+
 type FilterIndex struct {
 	FieldName string
 	FieldType FieldType
 	ElemType  FieldType
 	IndexType IndexType
+	// AutoId: only for the primary key, AutoIdUUID makes the server generate the id of the documents upserted without id
+	AutoId string
 }
 
 func (i *FilterIndex) IsPrimaryKey() bool {
 	return i.IndexType == PRIMARY
 }
 
+// autoId reports whether the ids of documents are generated by server, false if the indexes are unknown.
+func (i Indexes) autoId() bool {
+	for _, v := range i.FilterIndex {
+		if v.IsPrimaryKey() && v.AutoId != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *FilterIndex) IsVectorField() bool {
 	return i.FieldType == Vector
 }
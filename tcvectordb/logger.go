@@ -0,0 +1,74 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger the leveled structured logger of client, the keysAndValues are pairs of key and value.
+// *slog.Logger satisfies it, and a zap.SugaredLogger could be adapted by its Debugw, Infow, Warnw and Errorw.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// stdLogger the logger used by Debug(true) if ClientOption.Logger is not set, which writes to the std log.
+type stdLogger struct{}
+
+func (stdLogger) Debug(msg string, keysAndValues ...interface{}) {
+	stdLog("DEBUG", msg, keysAndValues)
+}
+
+func (stdLogger) Info(msg string, keysAndValues ...interface{}) {
+	stdLog("INFO", msg, keysAndValues)
+}
+
+func (stdLogger) Warn(msg string, keysAndValues ...interface{}) {
+	stdLog("WARN", msg, keysAndValues)
+}
+
+func (stdLogger) Error(msg string, keysAndValues ...interface{}) {
+	stdLog("ERROR", msg, keysAndValues)
+}
+
+func stdLog(level, msg string, keysAndValues []interface{}) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", level, msg)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fmt.Fprintf(&b, ", %v: %v", keysAndValues[i], keysAndValues[i+1])
+	}
+	log.Print(b.String())
+}
+
+// clientLogger returns the logger of client and whether the bodies should be logged,
+// nil if the client has no Logger and is not in debug mode.
+func clientLogger(option ClientOption, debug bool) (logger Logger, verbose bool) {
+	if option.Logger != nil {
+		return option.Logger, option.LogBodies || debug
+	}
+	if debug {
+		return stdLogger{}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package mock provides the test doubles of tcvectordb.SdkClient for the unit tests of the code using the sdk.
+// Client records the requests and returns the canned responses, Fake stores the documents in memory.
+// Both are used with tcvectordb.NewVDBClient.
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
+)
+
+var _ tcvectordb.SdkClient = &Client{}
+
+// Call a request received by the Client.
+type Call struct {
+	// Path: the api path of the request, eg: /document/upsert
+	Path string
+	// Req: the request struct of the api package, eg: *document.UpsertReq
+	Req interface{}
+}
+
+// HandlerFunc handle the request of a path, it fills res and returns the error of the request.
+type HandlerFunc func(ctx context.Context, req, res interface{}) error
+
+// Client a recording mock of tcvectordb.SdkClient. The requests without response set succeed with an empty response.
+type Client struct {
+	mu       sync.Mutex
+	calls    []Call
+	handlers map[string]HandlerFunc
+	option   tcvectordb.ClientOption
+}
+
+// NewClient new a recording mock client.
+func NewClient() *Client {
+	return &Client{
+		handlers: make(map[string]HandlerFunc),
+		option:   tcvectordb.ClientOption{ReadConsistency: tcvectordb.EventualConsistency},
+	}
+}
+
+// SetResponse set the response and error of the requests of path. res is encoded to json and decoded
+// into the response of the request, so it could be the response struct of the api package or a map.
+func (c *Client) SetResponse(path string, res interface{}, err error) {
+	c.Handle(path, func(ctx context.Context, _, out interface{}) error {
+		if err != nil {
+			return err
+		}
+		return copyJSON(res, out)
+	})
+}
+
+// Handle set the handler of the requests of path, which overrides the response set by SetResponse.
+func (c *Client) Handle(path string, handler HandlerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[path] = handler
+}
+
+// Request record the request and returns the response set for its path.
+func (c *Client) Request(ctx context.Context, req, res interface{}) error {
+	path := api.Path(req)
+	c.mu.Lock()
+	c.calls = append(c.calls, Call{Path: path, Req: req})
+	handler := c.handlers[path]
+	c.mu.Unlock()
+	if handler == nil {
+		return nil
+	}
+	return handler(ctx, req, res)
+}
+
+// Calls returns the recorded requests in order.
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// CallsOf returns the recorded requests of path in order.
+func (c *Client) CallsOf(path string) []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var calls []Call
+	for _, call := range c.calls {
+		if call.Path == path {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset clear the recorded requests, the responses are kept.
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = nil
+}
+
+func (c *Client) Options() tcvectordb.ClientOption {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.option
+}
+
+func (c *Client) WithTimeout(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.option.Timeout = d
+}
+
+func (c *Client) Debug(v bool) {}
+
+func (c *Client) Close() {}
+
+// copyJSON encode in to json and decode it into out, as the response is sent by the server.
+func copyJSON(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
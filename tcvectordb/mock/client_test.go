@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	mock := NewClient()
+	mock.SetResponse("/document/query", &document.QueryRes{
+		Count:     1,
+		Documents: []*document.Document{{Id: "0001", Fields: map[string]interface{}{"author": "a"}}},
+	}, nil)
+	failure := errors.New("upsert failed")
+	mock.SetResponse("/document/upsert", nil, failure)
+
+	coll := tcvectordb.NewVDBClient(mock).Database("db").Collection("coll")
+	res, err := coll.Query(ctx, []string{"0001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 || res.Documents[0].Fields["author"].String() != "a" {
+		t.Errorf("unexpected query result %+v", res)
+	}
+	_, err = coll.Upsert(ctx, []tcvectordb.Document{{Id: "0001", Vector: []float32{0.1}}})
+	if !errors.Is(err, failure) {
+		t.Errorf("expect canned upsert error, got %v", err)
+	}
+
+	calls := mock.CallsOf("/document/query")
+	if len(calls) != 1 || len(mock.Calls()) != 2 {
+		t.Fatalf("unexpected calls %+v", mock.Calls())
+	}
+	req := calls[0].Req.(*document.QueryReq)
+	if req.Database != "db" || req.Collection != "coll" || req.Query.DocumentIds[0] != "0001" {
+		t.Errorf("unexpected query request %+v", req)
+	}
+}
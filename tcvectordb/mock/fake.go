@@ -0,0 +1,633 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/collection"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/database"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+)
+
+var _ tcvectordb.SdkClient = &Fake{}
+
+// codeInvalidRequest the code of the errors returned by Fake for the invalid requests.
+const codeInvalidRequest = 1
+
+// defaultSearchLimit the limit of search if it is not set.
+const defaultSearchLimit = 10
+
+// Fake an in-memory tcvectordb.SdkClient for the integration-style tests without a vectordb server.
+// It supports the database, collection and document apis of the base database: the documents are kept
+// per database and collection, Upsert overwrites the document of the same id, and Search scores all
+// documents by brute force with the metric of the vector index, L2 is the squared euclidean distance.
+// The filters support the conditions built by tcvectordb.Filter. The other apis return an error.
+type Fake struct {
+	mu        sync.Mutex
+	databases map[string]*fakeDatabase
+	option    tcvectordb.ClientOption
+}
+
+type fakeDatabase struct {
+	createTime  string
+	collections map[string]*fakeCollection
+}
+
+type fakeCollection struct {
+	item *collection.DescribeCollectionItem
+	ids  []string
+	docs map[string]*document.Document
+	// autoId the sequence of the generated ids
+	autoId uint64
+}
+
+// NewFake new an empty in-memory client.
+func NewFake() *Fake {
+	return &Fake{
+		databases: make(map[string]*fakeDatabase),
+		option:    tcvectordb.ClientOption{ReadConsistency: tcvectordb.EventualConsistency},
+	}
+}
+
+// Request handle the request in memory. The request and response are copied by json as they are sent by the client.
+func (f *Fake) Request(ctx context.Context, req, res interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path := api.Path(req)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var (
+		out interface{}
+		err error
+	)
+	switch path {
+	case "/database/create":
+		r := new(database.CreateReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.createDatabase(r)
+		}
+	case "/database/drop":
+		r := new(database.DropReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.dropDatabase(r)
+		}
+	case "/database/list":
+		out, err = f.listDatabase()
+	case "/database/describe":
+		r := new(database.DescribeReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.describeDatabase(r)
+		}
+	case "/collection/create":
+		r := new(collection.CreateReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.createCollection(r)
+		}
+	case "/collection/describe":
+		r := new(collection.DescribeReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.describeCollection(r)
+		}
+	case "/collection/list":
+		r := new(collection.ListReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.listCollection(r)
+		}
+	case "/collection/drop":
+		r := new(collection.DropReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.dropCollection(r)
+		}
+	case "/collection/truncate":
+		r := new(collection.TruncateReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.truncateCollection(r)
+		}
+	case "/document/upsert":
+		r := new(document.UpsertReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.upsert(r)
+		}
+	case "/document/query":
+		r := new(document.QueryReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.query(r)
+		}
+	case "/document/count":
+		r := new(document.CountReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.count(r)
+		}
+	case "/document/search":
+		r := new(document.SearchReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.search(r)
+		}
+	case "/document/delete":
+		r := new(document.DeleteReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.delete(r)
+		}
+	case "/document/update":
+		r := new(document.UpdateReq)
+		if err = copyJSON(req, r); err == nil {
+			out, err = f.update(r)
+		}
+	default:
+		err = fmt.Errorf("api %s is not supported", path)
+	}
+	if err != nil {
+		apiErr, ok := err.(*tcvectordb.APIError)
+		if !ok {
+			apiErr = &tcvectordb.APIError{Code: codeInvalidRequest, Message: err.Error()}
+		}
+		apiErr.RequestPath = path
+		return apiErr
+	}
+	return copyJSON(out, res)
+}
+
+func (f *Fake) Options() tcvectordb.ClientOption {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.option
+}
+
+func (f *Fake) WithTimeout(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.option.Timeout = d
+}
+
+func (f *Fake) Debug(v bool) {}
+
+func (f *Fake) Close() {}
+
+func (f *Fake) createDatabase(req *database.CreateReq) (*database.CreateRes, error) {
+	if _, ok := f.databases[req.Database]; ok {
+		return nil, fmt.Errorf("database %s already exist", req.Database)
+	}
+	f.databases[req.Database] = &fakeDatabase{
+		createTime:  time.Now().Format("2006-01-02 15:04:05"),
+		collections: make(map[string]*fakeCollection),
+	}
+	return &database.CreateRes{AffectedCount: 1}, nil
+}
+
+func (f *Fake) dropDatabase(req *database.DropReq) (*database.DropRes, error) {
+	if _, err := f.database(req.Database); err != nil {
+		return nil, err
+	}
+	delete(f.databases, req.Database)
+	return &database.DropRes{AffectedCount: 1}, nil
+}
+
+func (f *Fake) listDatabase() (*database.ListRes, error) {
+	res := &database.ListRes{Info: make(map[string]database.DatabaseInfo)}
+	for name, db := range f.databases {
+		res.Databases = append(res.Databases, name)
+		res.Info[name] = database.DatabaseInfo{CreateTime: db.createTime, DbType: tcvectordb.DbTypeBase, Count: int64(len(db.collections))}
+	}
+	sort.Strings(res.Databases)
+	return res, nil
+}
+
+func (f *Fake) describeDatabase(req *database.DescribeReq) (*database.DescribeRes, error) {
+	db, err := f.database(req.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &database.DescribeRes{Database: &database.DatabaseInfo{
+		Database:   req.Database,
+		CreateTime: db.createTime,
+		DbType:     tcvectordb.DbTypeBase,
+		Count:      int64(len(db.collections)),
+	}}, nil
+}
+
+func (f *Fake) database(name string) (*fakeDatabase, error) {
+	db, ok := f.databases[name]
+	if !ok {
+		return nil, &tcvectordb.APIError{Code: tcvectordb.ERR_UNDEFINED_DATABASE, Message: fmt.Sprintf("database %s not exist", name)}
+	}
+	return db, nil
+}
+
+func (f *Fake) collection(databaseName, collectionName string) (*fakeCollection, error) {
+	db, err := f.database(databaseName)
+	if err != nil {
+		return nil, err
+	}
+	coll, ok := db.collections[collectionName]
+	if !ok {
+		return nil, &tcvectordb.APIError{Code: tcvectordb.ERR_UNDEFINED_COLLECTION, Message: fmt.Sprintf("collection %s not exist", collectionName)}
+	}
+	return coll, nil
+}
+
+func (f *Fake) createCollection(req *collection.CreateReq) (*collection.CreateRes, error) {
+	db, err := f.database(req.Database)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := db.collections[req.Collection]; ok {
+		return nil, fmt.Errorf("collection %s already exist", req.Collection)
+	}
+	item := &collection.DescribeCollectionItem{
+		Database:    req.Database,
+		Collection:  req.Collection,
+		ReplicaNum:  req.ReplicaNum,
+		ShardNum:    req.ShardNum,
+		CreateTime:  time.Now().Format("2006-01-02 15:04:05"),
+		Description: req.Description,
+		Indexes:     req.Indexes,
+		IndexStatus: &collection.IndexStatus{Status: tcvectordb.IndexStatusReady},
+		Alias:       []string{},
+		TtlConfig:   req.TtlConfig,
+	}
+	if req.Embedding.Field != "" {
+		item.Embedding = &collection.EmbeddingRes{Embedding: req.Embedding, Status: "enabled"}
+	}
+	db.collections[req.Collection] = &fakeCollection{item: item, docs: make(map[string]*document.Document)}
+	return &collection.CreateRes{AffectedCount: 1}, nil
+}
+
+func (f *Fake) describeCollection(req *collection.DescribeReq) (*collection.DescribeRes, error) {
+	coll, err := f.collection(req.Database, req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return &collection.DescribeRes{Collection: coll.describe()}, nil
+}
+
+func (f *Fake) listCollection(req *collection.ListReq) (*collection.ListRes, error) {
+	db, err := f.database(req.Database)
+	if err != nil {
+		return nil, err
+	}
+	res := new(collection.ListRes)
+	for _, coll := range db.collections {
+		res.Collections = append(res.Collections, coll.describe())
+	}
+	sort.Slice(res.Collections, func(i, j int) bool { return res.Collections[i].Collection < res.Collections[j].Collection })
+	return res, nil
+}
+
+func (f *Fake) dropCollection(req *collection.DropReq) (*collection.DropRes, error) {
+	if _, err := f.collection(req.Database, req.Collection); err != nil {
+		return nil, err
+	}
+	delete(f.databases[req.Database].collections, req.Collection)
+	return &collection.DropRes{AffectedCount: 1}, nil
+}
+
+func (f *Fake) truncateCollection(req *collection.TruncateReq) (*collection.TruncateRes, error) {
+	coll, err := f.collection(req.Database, req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	coll.ids = nil
+	coll.docs = make(map[string]*document.Document)
+	return &collection.TruncateRes{AffectedCount: 1}, nil
+}
+
+func (f *Fake) upsert(req *document.UpsertReq) (*document.UpsertRes, error) {
+	coll, err := f.collection(req.Database, req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	autoId := false
+	for _, index := range coll.item.Indexes {
+		if index.IndexType == string(tcvectordb.PRIMARY) && index.AutoId != "" {
+			autoId = true
+		}
+	}
+	for _, doc := range req.Documents {
+		if doc.Id == "" && !autoId {
+			return nil, fmt.Errorf("document id is empty")
+		}
+	}
+	res := &document.UpsertRes{AffectedCount: len(req.Documents)}
+	for _, doc := range req.Documents {
+		if doc.Id == "" {
+			coll.autoId++
+			doc.Id = fmt.Sprintf("%016x", coll.autoId)
+			res.Ids = append(res.Ids, doc.Id)
+		}
+		if _, ok := coll.docs[doc.Id]; !ok {
+			coll.ids = append(coll.ids, doc.Id)
+		}
+		coll.docs[doc.Id] = doc
+	}
+	return res, nil
+}
+
+func (f *Fake) query(req *document.QueryReq) (*document.QueryRes, error) {
+	coll, err := f.collection(req.Database, req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	query := req.Query
+	if query == nil {
+		query = new(document.QueryCond)
+	}
+	docs, err := coll.match(query.DocumentIds, query.Filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(query.Sort) - 1; i >= 0; i-- {
+		rule := query.Sort[i]
+		sort.SliceStable(docs, func(a, b int) bool {
+			c := compareFields(docs[a].Fields[rule.FieldName], docs[b].Fields[rule.FieldName])
+			if rule.Direction == string(tcvectordb.SortDesc) {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+	res := &document.QueryRes{Count: uint64(len(docs))}
+	if query.Offset > 0 {
+		if query.Offset > int64(len(docs)) {
+			query.Offset = int64(len(docs))
+		}
+		docs = docs[query.Offset:]
+	}
+	if query.Limit > 0 && query.Limit < int64(len(docs)) {
+		docs = docs[:query.Limit]
+	}
+	for _, doc := range docs {
+		res.Documents = append(res.Documents, project(doc, query.RetrieveVector, query.OutputFields, 0))
+	}
+	return res, nil
+}
+
+func (f *Fake) count(req *document.CountReq) (*document.CountRes, error) {
+	coll, err := f.collection(req.Database, req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	var filter string
+	if req.Query != nil {
+		filter = req.Query.Filter
+	}
+	docs, err := coll.match(nil, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &document.CountRes{Count: uint64(len(docs))}, nil
+}
+
+func (f *Fake) search(req *document.SearchReq) (*document.SearchRes, error) {
+	coll, err := f.collection(req.Database, req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	search := req.Search
+	if search == nil {
+		search = new(document.SearchCond)
+	}
+	if len(search.EmbeddingItems) != 0 || len(search.Retrieves) != 0 {
+		return nil, fmt.Errorf("search by text is not supported")
+	}
+	vectors := search.Vectors
+	for _, id := range search.DocumentIds {
+		if doc, ok := coll.docs[id]; ok {
+			vectors = append(vectors, doc.Vector)
+		} else {
+			vectors = append(vectors, nil)
+		}
+	}
+	docs, err := coll.match(nil, search.Filter)
+	if err != nil {
+		return nil, err
+	}
+	limit := search.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	metric := coll.metricType()
+	ascending := metric == tcvectordb.L2 || metric == tcvectordb.HAMMING
+
+	res := new(document.SearchRes)
+	for _, vector := range vectors {
+		type scored struct {
+			doc   *document.Document
+			score float32
+		}
+		var candidates []scored
+		if len(vector) != 0 {
+			for _, doc := range docs {
+				if len(doc.Vector) != len(vector) {
+					continue
+				}
+				candidates = append(candidates, scored{doc: doc, score: score(metric, vector, doc.Vector)})
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if ascending {
+				return candidates[i].score < candidates[j].score
+			}
+			return candidates[i].score > candidates[j].score
+		})
+		if int64(len(candidates)) > limit {
+			candidates = candidates[:limit]
+		}
+		result := make([]*document.Document, 0, len(candidates))
+		for _, c := range candidates {
+			result = append(result, project(c.doc, search.RetrieveVector, search.OutputFields, c.score))
+		}
+		res.Documents = append(res.Documents, result)
+	}
+	return res, nil
+}
+
+func (f *Fake) delete(req *document.DeleteReq) (*document.DeleteRes, error) {
+	coll, err := f.collection(req.Database, req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	query := req.Query
+	if query == nil {
+		query = new(document.QueryCond)
+	}
+	docs, err := coll.match(query.DocumentIds, query.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if query.Limit > 0 && query.Limit < int64(len(docs)) {
+		docs = docs[:query.Limit]
+	}
+	for _, doc := range docs {
+		delete(coll.docs, doc.Id)
+	}
+	ids := coll.ids[:0]
+	for _, id := range coll.ids {
+		if _, ok := coll.docs[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	coll.ids = ids
+	return &document.DeleteRes{AffectedCount: len(docs)}, nil
+}
+
+func (f *Fake) update(req *document.UpdateReq) (*document.UpdateRes, error) {
+	coll, err := f.collection(req.Database, req.Collection)
+	if err != nil {
+		return nil, err
+	}
+	query := req.Query
+	if query == nil || len(query.DocumentIds) == 0 && query.Filter == "" {
+		return nil, fmt.Errorf("documentIds or filter is required to update")
+	}
+	docs, err := coll.match(query.DocumentIds, query.Filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		updated := *doc
+		if len(req.Update.Vector) != 0 {
+			updated.Vector = req.Update.Vector
+		}
+		if len(req.Update.SparseVector) != 0 {
+			updated.SparseVector = req.Update.SparseVector
+		}
+		updated.Fields = make(map[string]interface{}, len(doc.Fields)+len(req.Update.Fields))
+		for k, v := range doc.Fields {
+			updated.Fields[k] = v
+		}
+		for k, v := range req.Update.Fields {
+			updated.Fields[k] = v
+		}
+		coll.docs[doc.Id] = &updated
+	}
+	return &document.UpdateRes{AffectedCount: len(docs)}, nil
+}
+
+func (c *fakeCollection) describe() *collection.DescribeCollectionItem {
+	item := *c.item
+	item.DocumentCount = int64(len(c.docs))
+	return &item
+}
+
+// metricType returns the metric of the vector index, L2 if the collection has none.
+func (c *fakeCollection) metricType() tcvectordb.MetricType {
+	for _, index := range c.item.Indexes {
+		if index.FieldType == string(tcvectordb.Vector) || index.FieldType == string(tcvectordb.BinaryVector) {
+			return tcvectordb.MetricType(index.MetricType)
+		}
+	}
+	return tcvectordb.L2
+}
+
+// match returns the documents of ids matching the filter, in the order of ids,
+// or all documents matching the filter in the order of insertion if ids is empty.
+func (c *fakeCollection) match(ids []string, cond string) ([]*document.Document, error) {
+	filter, err := parseFilter(cond)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		ids = c.ids
+	}
+	var docs []*document.Document
+	for _, id := range ids {
+		doc, ok := c.docs[id]
+		if !ok {
+			continue
+		}
+		fields := make(map[string]interface{}, len(doc.Fields)+1)
+		for k, v := range doc.Fields {
+			fields[k] = v
+		}
+		fields["id"] = doc.Id
+		if filter(fields) {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// project returns the copy of doc with the output fields, and the vector if retrieveVector.
+func project(doc *document.Document, retrieveVector bool, outputFields []string, score float32) *document.Document {
+	res := &document.Document{Id: doc.Id, Score: score, Fields: make(map[string]interface{})}
+	if retrieveVector {
+		res.Vector = doc.Vector
+		res.SparseVector = doc.SparseVector
+	}
+	if len(outputFields) == 0 {
+		for k, v := range doc.Fields {
+			res.Fields[k] = v
+		}
+		return res
+	}
+	for _, name := range outputFields {
+		if v, ok := doc.Fields[name]; ok {
+			res.Fields[name] = v
+		}
+	}
+	return res
+}
+
+// score returns the distance or similarity of the vectors by metric.
+func score(metric tcvectordb.MetricType, a, b []float32) float32 {
+	switch metric {
+	case tcvectordb.IP:
+		var dot float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+		}
+		return float32(dot)
+	case tcvectordb.COSINE:
+		var dot, na, nb float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+			na += float64(a[i]) * float64(a[i])
+			nb += float64(b[i]) * float64(b[i])
+		}
+		if na == 0 || nb == 0 {
+			return 0
+		}
+		return float32(dot / math.Sqrt(na*nb))
+	case tcvectordb.HAMMING:
+		// the binary vectors are sent as the arrays of uint8
+		distance := 0
+		for i := range a {
+			distance += bits.OnesCount8(uint8(a[i]) ^ uint8(b[i]))
+		}
+		return float32(distance)
+	default:
+		var sum float64
+		for i := range a {
+			d := float64(a[i]) - float64(b[i])
+			sum += d * d
+		}
+		return float32(sum)
+	}
+}
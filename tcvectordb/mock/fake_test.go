@@ -0,0 +1,191 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb"
+)
+
+func newFakeCollection(t *testing.T) *tcvectordb.Collection {
+	ctx := context.Background()
+	cli := tcvectordb.NewVDBClient(NewFake())
+	db, err := cli.CreateDatabase(ctx, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.CreateCollection(ctx, "coll", 1, 1, "", tcvectordb.Indexes{
+		VectorIndex: []tcvectordb.VectorIndex{{
+			FilterIndex: tcvectordb.FilterIndex{FieldName: "vector", FieldType: tcvectordb.Vector, IndexType: tcvectordb.FLAT},
+			Dimension:   2,
+			MetricType:  tcvectordb.COSINE,
+		}},
+		FilterIndex: []tcvectordb.FilterIndex{
+			{FieldName: "id", FieldType: tcvectordb.String, IndexType: tcvectordb.PRIMARY},
+			{FieldName: "author", FieldType: tcvectordb.String, IndexType: tcvectordb.FILTER},
+			{FieldName: "tags", FieldType: tcvectordb.Array, ElemType: tcvectordb.String, IndexType: tcvectordb.FILTER},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := db.DescribeCollection(ctx, "coll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &res.Collection
+}
+
+func TestFakeDocuments(t *testing.T) {
+	ctx := context.Background()
+	coll := newFakeCollection(t)
+
+	_, err := coll.Upsert(ctx, []tcvectordb.Document{
+		{Id: "0001", Vector: []float32{1, 0}, Fields: map[string]tcvectordb.Field{"author": {Val: "a"}, "page": {Val: 10}}},
+		{Id: "0002", Vector: []float32{0, 1}, Fields: map[string]tcvectordb.Field{"author": {Val: "b"}, "page": {Val: 20}}},
+		{Id: "0003", Vector: []float32{1, 1}, Fields: map[string]tcvectordb.Field{"author": {Val: "c"}, "page": {Val: 30}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = coll.Upsert(ctx, []tcvectordb.Document{
+		{Id: "0001", Vector: []float32{1, 0.1}, Fields: map[string]tcvectordb.Field{"author": {Val: "a"}, "page": {Val: 11}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, err := coll.Query(ctx, nil, &tcvectordb.QueryDocumentParams{
+		Filter: tcvectordb.NewFilter(tcvectordb.In("author", []string{"a", "c"})).And("page > 10"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Total != 2 || query.Documents[0].Id != "0001" || query.Documents[0].Fields["page"].Uint64() != 11 {
+		t.Errorf("unexpected query result %+v", query.Documents)
+	}
+
+	search, err := coll.Search(ctx, [][]float32{{0, 1}}, &tcvectordb.SearchDocumentParams{
+		Filter: tcvectordb.NewFilter(`author != "c"`),
+		Limit:  2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(search.Documents[0]) != 2 || search.Documents[0][0].Id != "0002" || search.Documents[0][0].Score < 0.99 {
+		t.Errorf("unexpected search result %+v", search.Documents)
+	}
+
+	del, err := coll.Delete(ctx, tcvectordb.DeleteDocumentParams{Filter: tcvectordb.NewFilter(tcvectordb.Lt("page", 100)), Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := coll.Count(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if del.AffectedCount != 1 || count.Count != 2 {
+		t.Errorf("expect 1 document deleted and 2 left, deleted %d, left %d", del.AffectedCount, count.Count)
+	}
+}
+
+func TestFakeArrayField(t *testing.T) {
+	ctx := context.Background()
+	coll := newFakeCollection(t)
+	_, err := coll.Upsert(ctx, []tcvectordb.Document{
+		{Id: "0001", Vector: []float32{1, 0}, Fields: map[string]tcvectordb.Field{"tags": {Val: []string{"go", "sdk"}}}},
+		{Id: "0002", Vector: []float32{0, 1}, Fields: map[string]tcvectordb.Field{"tags": {Val: []string{"java"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := coll.Query(ctx, nil, &tcvectordb.QueryDocumentParams{
+		Filter: tcvectordb.NewFilter(tcvectordb.Include("tags", []string{"sdk", "python"})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Documents) != 1 || res.Documents[0].Id != "0001" {
+		t.Fatalf("unexpected query result %+v", res.Documents)
+	}
+	if tags := res.Documents[0].Fields["tags"].StringSlice(); len(tags) != 2 || tags[1] != "sdk" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+}
+
+func TestFakeAutoId(t *testing.T) {
+	ctx := context.Background()
+	db, err := tcvectordb.NewVDBClient(NewFake()).CreateDatabase(ctx, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.CreateCollection(ctx, "coll", 1, 1, "", tcvectordb.Indexes{
+		VectorIndex: []tcvectordb.VectorIndex{{
+			FilterIndex: tcvectordb.FilterIndex{FieldName: "vector", FieldType: tcvectordb.Vector, IndexType: tcvectordb.FLAT},
+			Dimension:   2,
+			MetricType:  tcvectordb.L2,
+		}},
+		FilterIndex: []tcvectordb.FilterIndex{
+			{FieldName: "id", FieldType: tcvectordb.String, IndexType: tcvectordb.PRIMARY, AutoId: tcvectordb.AutoIdUUID},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc, err := db.DescribeCollection(ctx, "coll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := desc.Upsert(ctx, []tcvectordb.Document{{Vector: []float32{1, 0}}, {Id: "0002", Vector: []float32{0, 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Ids) != 1 {
+		t.Fatalf("expect 1 generated id, got %v", res.Ids)
+	}
+	if _, err = desc.Get(ctx, res.Ids[0]); err != nil {
+		t.Errorf("expect document of generated id, got %v", err)
+	}
+}
+
+func TestFakeNotExist(t *testing.T) {
+	ctx := context.Background()
+	coll := newFakeCollection(t)
+	db := tcvectordb.NewVDBClient(NewFake()).Database("db")
+	_, err := db.DescribeCollection(ctx, "coll")
+	if !tcvectordb.IsDatabaseNotExist(err) {
+		t.Errorf("expect database not exist, got %v", err)
+	}
+	_, err = tcvectordb.NewVDBClient(NewFake()).DescribeDatabase(ctx, "db")
+	if !tcvectordb.IsDatabaseNotExist(err) {
+		t.Errorf("expect database not exist, got %v", err)
+	}
+	_, err = coll.Query(ctx, nil, &tcvectordb.QueryDocumentParams{Filter: tcvectordb.NewFilter(`author = `)})
+	if err == nil {
+		t.Error("expect error of invalid filter")
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	fields := map[string]interface{}{"author": "a", "page": 10.0, "tags": []interface{}{"x", "y"}}
+	cases := map[string]bool{
+		`author = "a" and page >= 10`:           true,
+		`author = "a" and not (page = 10)`:      false,
+		`author = "b" or page < 11`:             true,
+		`author not in ("b", "c")`:              true,
+		`tags include ("y", "z")`:               true,
+		`tags include all ("y", "z")`:           false,
+		`tags exclude ("z")`:                    true,
+		`missing = "a" or (page != 1 and id=1)`: false,
+	}
+	for cond, expect := range cases {
+		f, err := parseFilter(cond)
+		if err != nil {
+			t.Errorf("parse %q: %v", cond, err)
+			continue
+		}
+		if f(fields) != expect {
+			t.Errorf("expect %q to be %v", cond, expect)
+		}
+	}
+}
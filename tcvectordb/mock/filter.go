@@ -0,0 +1,389 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// filterFunc reports whether the fields of a document match the filter.
+type filterFunc func(fields map[string]interface{}) bool
+
+// parseFilter parse the filter expression of vectordb. It supports and, or, not, parentheses,
+// the comparisons =, !=, >, >=, <, <= and the list conditions in, not in, include, exclude, include all.
+func parseFilter(cond string) (filterFunc, error) {
+	if strings.TrimSpace(cond) == "" {
+		return func(map[string]interface{}) bool { return true }, nil
+	}
+	tokens, err := tokenize(cond)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("invalid filter %q: unexpected %q", cond, p.tokens[p.pos].text)
+	}
+	return f, nil
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(cond string) ([]token, error) {
+	var tokens []token
+	runes := []rune(cond)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("invalid filter %q: unterminated string", cond)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String()})
+			i = j + 1
+		case r == '-' || r == '.' || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || strings.ContainsRune(".eE+-", runes[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:j])})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(runes) && (runes[j] == '_' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:j])})
+			i = j
+		case strings.ContainsRune("!<>", r) && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(runes[i : i+2])})
+			i += 2
+		case strings.ContainsRune("=<>(),", r):
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(r)})
+			i++
+		default:
+			return nil, fmt.Errorf("invalid filter %q: unexpected %q", cond, r)
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// keyword consumes the next token if it is the keyword, case insensitive.
+func (p *filterParser) keyword(word string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokenIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) symbol(s string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokenSymbol && t.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filterFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(fields map[string]interface{}) bool { return l(fields) || right(fields) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterFunc, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(fields map[string]interface{}) bool { return l(fields) && right(fields) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filterFunc, error) {
+	if p.keyword("not") {
+		f, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(fields map[string]interface{}) bool { return !f(fields) }, nil
+	}
+	if p.symbol("(") {
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.symbol(")") {
+			return nil, fmt.Errorf("invalid filter: missing )")
+		}
+		return f, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *filterParser) parseCondition() (filterFunc, error) {
+	t, ok := p.peek()
+	if !ok || t.kind != tokenIdent {
+		return nil, fmt.Errorf("invalid filter: expect field name, got %q", t.text)
+	}
+	p.pos++
+	field := t.text
+
+	switch {
+	case p.keyword("in"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return func(fields map[string]interface{}) bool { return containsAny(values, fields[field]) }, nil
+	case p.keyword("not"):
+		if !p.keyword("in") {
+			return nil, fmt.Errorf("invalid filter: expect in after not")
+		}
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return func(fields map[string]interface{}) bool {
+			v, ok := fields[field]
+			return ok && !containsAny(values, v)
+		}, nil
+	case p.keyword("include"):
+		all := p.keyword("all")
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return func(fields map[string]interface{}) bool {
+			elems, _ := fields[field].([]interface{})
+			for _, v := range values {
+				if containsAny(elems, v) != all {
+					return !all
+				}
+			}
+			return all
+		}, nil
+	case p.keyword("exclude"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return func(fields map[string]interface{}) bool {
+			elems, _ := fields[field].([]interface{})
+			for _, v := range values {
+				if containsAny(elems, v) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	}
+
+	op, ok := p.peek()
+	if !ok || op.kind != tokenSymbol {
+		return nil, fmt.Errorf("invalid filter: expect operator after %s", field)
+	}
+	p.pos++
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	var match func(c int) bool
+	switch op.text {
+	case "=":
+		match = func(c int) bool { return c == 0 }
+	case "!=":
+		match = func(c int) bool { return c != 0 }
+	case ">":
+		match = func(c int) bool { return c > 0 }
+	case ">=":
+		match = func(c int) bool { return c >= 0 }
+	case "<":
+		match = func(c int) bool { return c < 0 }
+	case "<=":
+		match = func(c int) bool { return c <= 0 }
+	default:
+		return nil, fmt.Errorf("invalid filter: unknown operator %s", op.text)
+	}
+	return func(fields map[string]interface{}) bool {
+		c, ok := compare(fields[field], value)
+		return ok && match(c)
+	}, nil
+}
+
+func (p *filterParser) parseList() ([]interface{}, error) {
+	if !p.symbol("(") {
+		return nil, fmt.Errorf("invalid filter: expect ( of list")
+	}
+	var values []interface{}
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if p.symbol(")") {
+			return values, nil
+		}
+		if !p.symbol(",") {
+			return nil, fmt.Errorf("invalid filter: expect , or ) in list")
+		}
+	}
+}
+
+func (p *filterParser) parseValue() (interface{}, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("invalid filter: expect value")
+	}
+	p.pos++
+	switch t.kind {
+	case tokenString:
+		return t.text, nil
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: invalid number %s", t.text)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid filter: expect value, got %q", t.text)
+}
+
+// containsAny reports whether v equals any of values.
+func containsAny(values []interface{}, v interface{}) bool {
+	for _, value := range values {
+		if c, ok := compare(v, value); ok && c == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// compare compares the field value with the filter value, ok is false if they are not comparable.
+func compare(field, value interface{}) (c int, ok bool) {
+	switch value := value.(type) {
+	case string:
+		s, ok := field.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(s, value), true
+	case float64:
+		f, ok := toFloat(field)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case f < value:
+			return -1, true
+		case f > value:
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// compareFields compares the values of two documents for sorting.
+func compareFields(a, b interface{}) int {
+	if f, ok := toFloat(b); ok {
+		b = f
+	}
+	c, _ := compare(a, b)
+	return c
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case interface{ Float64() (float64, error) }:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
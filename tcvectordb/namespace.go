@@ -0,0 +1,146 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/database"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/user"
+)
+
+// NamespaceDelimiter joins the namespace and the database name of the client returned by WithNamespace.
+const NamespaceDelimiter = "-"
+
+// WithNamespace returns a copy of the client whose database names are prefixed with namespace and NamespaceDelimiter
+// on the wire, such as the database "orders" of namespace "tenant1" is "tenant1-orders" on the server.
+// ListDatabase only lists the databases of namespace with the prefix stripped, and the collection names are unchanged.
+// The privileges of users are granted on the databases of namespace, and the database * is refused.
+// The database names containing NamespaceDelimiter are refused to keep the namespaces apart.
+func (c *Client) WithNamespace(namespace string) (*Client, error) {
+	if namespace == "" || strings.Contains(namespace, NamespaceDelimiter) {
+		return nil, fmt.Errorf("invalid namespace %q, which must be non-empty and without %q", namespace, NamespaceDelimiter)
+	}
+	clone := c.WithOptions(ScopedOption{})
+	clone.namespace = namespace
+	return clone, nil
+}
+
+// namespaceRequest returns a copy of req whose Database, or the database of privilege resources, is prefixed
+// with the namespace of client.
+func (c *Client) namespaceRequest(req interface{}) (interface{}, error) {
+	switch r := req.(type) {
+	case *user.GrantReq:
+		copied := *r
+		privileges, err := c.namespacePrivileges(r.Privileges)
+		copied.Privileges = privileges
+		return &copied, err
+	case *user.RevokeReq:
+		copied := *r
+		privileges, err := c.namespacePrivileges(r.Privileges)
+		copied.Privileges = privileges
+		return &copied, err
+	}
+	v := reflect.ValueOf(req)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return req, nil
+	}
+	field := v.FieldByName("Database")
+	if !field.IsValid() || field.Kind() != reflect.String || field.String() == "" {
+		return req, nil
+	}
+	if strings.Contains(field.String(), NamespaceDelimiter) {
+		return nil, fmt.Errorf("invalid database %q of namespace %s, which must be without %q", field.String(), c.namespace, NamespaceDelimiter)
+	}
+	copied := reflect.New(v.Type())
+	copied.Elem().Set(v)
+	copied.Elem().FieldByName("Database").SetString(c.namespace + NamespaceDelimiter + field.String())
+	return copied.Interface(), nil
+}
+
+// namespacePrivileges returns the copy of privileges whose resource database.collection is prefixed with
+// the namespace. The database * is refused, since it matches the databases of the other namespaces.
+func (c *Client) namespacePrivileges(privileges []*user.Privilege) ([]*user.Privilege, error) {
+	copied := make([]*user.Privilege, 0, len(privileges))
+	for _, p := range privileges {
+		if p == nil {
+			continue
+		}
+		db := strings.SplitN(p.Resource, ".", 2)[0]
+		if db == "" || db == "*" || strings.Contains(db, NamespaceDelimiter) {
+			return nil, fmt.Errorf("invalid resource %q of namespace %s, whose database must be a name without %q",
+				p.Resource, c.namespace, NamespaceDelimiter)
+		}
+		copied = append(copied, &user.Privilege{Resource: c.namespace + NamespaceDelimiter + p.Resource, Actions: p.Actions})
+	}
+	return copied, nil
+}
+
+// namespaceResponse keeps the databases of the namespace in the list response, and the privileges on them
+// in the user response, and strips their prefix.
+func (c *Client) namespaceResponse(res interface{}) {
+	v := reflect.ValueOf(res)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		switch r := v.Interface().(type) {
+		case *database.ListRes:
+			c.namespaceListRes(r)
+			return
+		case *user.DescribeRes:
+			c.namespaceUserRes(r)
+			return
+		}
+		v = v.Elem()
+	}
+}
+
+func (c *Client) namespaceUserRes(res *user.DescribeRes) {
+	prefix := c.namespace + NamespaceDelimiter
+	privileges := res.Privileges[:0]
+	for _, p := range res.Privileges {
+		if p != nil && strings.HasPrefix(p.Resource, prefix) {
+			p.Resource = strings.TrimPrefix(p.Resource, prefix)
+			privileges = append(privileges, p)
+		}
+	}
+	res.Privileges = privileges
+}
+
+func (c *Client) namespaceListRes(res *database.ListRes) {
+	prefix := c.namespace + NamespaceDelimiter
+	databases := res.Databases[:0]
+	info := make(map[string]database.DatabaseInfo)
+	for _, name := range res.Databases {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stripped := strings.TrimPrefix(name, prefix)
+		databases = append(databases, stripped)
+		if v, ok := res.Info[name]; ok {
+			v.Database = stripped
+			info[stripped] = v
+		}
+	}
+	res.Databases = databases
+	res.Info = info
+}
@@ -0,0 +1,103 @@
+package tcvectordb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/user"
+)
+
+func TestClientWithNamespace(t *testing.T) {
+	var databases []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/database/list" {
+			w.Write([]byte(`{"code":0,"databases":["tenant1-orders","tenant2-orders","orders"],
+				"info":{"tenant1-orders":{"database":"tenant1-orders","count":2},"tenant2-orders":{"count":3}}}`))
+			return
+		}
+		req := make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&req)
+		databases = append(databases, req["database"].(string))
+		w.Write([]byte(`{"code":0,"documents":[]}`))
+	}, ClientOption{})
+
+	if _, err := cli.WithNamespace("bad-ns"); err == nil {
+		t.Error("expect namespace with delimiter refused")
+	}
+	tenant, err := cli.WithNamespace("tenant1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tenant.CreateDatabase(context.Background(), "orders"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tenant.Query(context.Background(), "orders", "coll", []string{"0001"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tenant.Database("orders").Collection("coll").Query(context.Background(), []string{"0001"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Query(context.Background(), "orders", "coll", []string{"0001"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(databases) != 4 || databases[0] != "tenant1-orders" || databases[1] != "tenant1-orders" ||
+		databases[2] != "tenant1-orders" || databases[3] != "orders" {
+		t.Errorf("unexpected databases of requests %v", databases)
+	}
+
+	list, err := tenant.ListDatabase(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Databases) != 1 || list.Databases[0].DatabaseName != "orders" || list.Databases[0].Info.Count != 2 {
+		t.Errorf("expect only the database of namespace listed, got %+v", list.Databases)
+	}
+	if _, err = tenant.DropDatabase(context.Background(), "tenant2-orders"); err == nil {
+		t.Error("expect database with delimiter refused")
+	}
+}
+
+func TestNamespacePrivileges(t *testing.T) {
+	var resources []string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user/describe" {
+			w.Write([]byte(`{"code":0,"user":"u","privileges":[{"resource":"tenant1-orders.coll","actions":["read"]},
+				{"resource":"tenant2-orders.*","actions":["read"]}]}`))
+			return
+		}
+		req := new(user.GrantReq)
+		json.NewDecoder(r.Body).Decode(req)
+		for _, p := range req.Privileges {
+			resources = append(resources, p.Resource)
+		}
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{})
+	tenant, err := cli.WithNamespace("tenant1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err = tenant.GrantPrivilege(ctx, "u", "orders", "coll", []string{"read"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = tenant.RevokePrivilege(ctx, "u", "orders", "*", []string{"read"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = tenant.GrantPrivilege(ctx, "u", "*", "*", []string{"read"}); err == nil {
+		t.Error("expect the wildcard database refused")
+	}
+	if len(resources) != 2 || resources[0] != "tenant1-orders.coll" || resources[1] != "tenant1-orders.*" {
+		t.Errorf("expect the resources prefixed with namespace, got %v", resources)
+	}
+
+	res, err := tenant.DescribeUser(ctx, "u")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Privileges) != 1 || res.Privileges[0].Resource != "orders.coll" {
+		t.Errorf("expect only the privileges of namespace, got %+v", res.Privileges)
+	}
+}
@@ -0,0 +1,129 @@
+package tcvectordb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/api/document"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(&RateLimit{QPS: 100, Burst: 2})
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("expect the requests over burst to wait, elapsed %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	l = newRateLimiter(&RateLimit{QPS: 1})
+	l.wait(context.Background())
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect deadline exceeded while waiting, got %v", err)
+	}
+	if newRateLimiter(&RateLimit{}).wait(ctx) != nil {
+		t.Error("expect no limit without QPS")
+	}
+}
+
+func TestRequestRateLimited(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/document/upsert" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{ReadRateLimit: &RateLimit{QPS: 1000}, WriteRateLimit: &RateLimit{QPS: 0.001}})
+
+	err := cli.Request(context.Background(), &document.UpsertReq{}, new(document.UpsertRes))
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expect ErrRateLimited, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err = cli.Request(ctx, &document.UpsertReq{}, new(document.UpsertRes)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect the write waiting for its limit, got %v", err)
+	}
+	if err = cli.Request(context.Background(), &document.QueryReq{}, new(document.QueryRes)); err != nil {
+		t.Errorf("expect the read not limited by the write limit, got %v", err)
+	}
+}
+
+func TestRpcRateLimited(t *testing.T) {
+	httpc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {}, ClientOption{
+		ReadRateLimit: &RateLimit{QPS: 1000}, WriteRateLimit: &RateLimit{QPS: 0.001}})
+	interceptor := newInterceptor(&RpcClient{httpImplementer: httpc})
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if strings.HasSuffix(method, "/upsert") {
+			return status.Error(codes.ResourceExhausted, "too many requests")
+		}
+		return nil
+	}
+
+	err := interceptor(context.Background(), "/olama.SearchEngine/upsert", &olama.UpsertRequest{}, nil, nil, invoker)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expect ErrRateLimited, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = interceptor(ctx, "/olama.SearchEngine/upsert", &olama.UpsertRequest{}, nil, nil, invoker)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect the write waiting for its limit, got %v", err)
+	}
+	if err = interceptor(context.Background(), "/olama.SearchEngine/query", &olama.QueryRequest{}, nil, nil, invoker); err != nil {
+		t.Errorf("expect the read not limited by the write limit, got %v", err)
+	}
+	if (&APIError{Message: "rate limit of index exceeded"}).Is(ErrRateLimited) {
+		t.Error("expect the message not matched without the status")
+	}
+}
+
+func TestRpcShutdown(t *testing.T) {
+	httpc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {}, ClientOption{})
+	cc, err := grpc.Dial("127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := &RpcClient{httpImplementer: httpc, cc: cc}
+	interceptor := newInterceptor(cli)
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- interceptor(context.Background(), "/olama.SearchEngine/query", &olama.QueryRequest{}, nil, nil, invoker)
+	}()
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err = cli.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expect shutdown timed out with the rpc in flight, got %v", err)
+	}
+	err = interceptor(context.Background(), "/olama.SearchEngine/query", &olama.QueryRequest{}, nil, nil, invoker)
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expect ErrClientClosed after shutdown, got %v", err)
+	}
+	close(release)
+	if err = <-done; err != nil {
+		t.Errorf("expect the rpc in flight finished, got %v", err)
+	}
+}
@@ -43,3 +43,15 @@ func (r *rpcImplementerAlias) DeleteAlias(ctx context.Context, aliasName string)
 	}
 	return &DeleteAliasResult{AffectedCount: int(res.AffectedCount)}, nil
 }
+
+// ListAliases list the aliases by http, which is not supported by rpc.
+func (r *rpcImplementerAlias) ListAliases(ctx context.Context) (*ListAliasesResult, error) {
+	aliasImpl := &implementerAlias{SdkClient: r.SdkClient, database: r.database}
+	return aliasImpl.ListAliases(ctx)
+}
+
+// DescribeAlias describe the alias by http, which is not supported by rpc.
+func (r *rpcImplementerAlias) DescribeAlias(ctx context.Context, aliasName string) (*DescribeAliasResult, error) {
+	aliasImpl := &implementerAlias{SdkClient: r.SdkClient, database: r.database}
+	return aliasImpl.DescribeAlias(ctx, aliasName)
+}
@@ -3,8 +3,6 @@ package tcvectordb
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
@@ -21,7 +19,7 @@ type rpcImplementerCollection struct {
 func (r *rpcImplementerCollection) ExistsCollection(ctx context.Context, name string) (bool, error) {
 	res, err := r.DescribeCollection(ctx, name)
 	if err != nil {
-		if strings.Contains(err.Error(), strconv.Itoa(ERR_UNDEFINED_COLLECTION)) {
+		if IsCollectionNotExist(err) {
 			return false, nil
 		}
 		return false, fmt.Errorf("get collection %s failed, err: %v", name, err.Error())
@@ -33,24 +31,42 @@ func (r *rpcImplementerCollection) ExistsCollection(ctx context.Context, name st
 }
 
 func (r *rpcImplementerCollection) CreateCollectionIfNotExists(ctx context.Context, name string, shardNum, replicasNum uint32, description string,
-	indexes Indexes, params ...*CreateCollectionParams) (*Collection, error) {
-	res, err := r.DescribeCollection(ctx, name)
-	if err != nil {
-		if strings.Contains(err.Error(), strconv.Itoa(ERR_UNDEFINED_COLLECTION)) {
-			return r.CreateCollection(ctx, name, shardNum, replicasNum, description, indexes, params...)
-		}
-		return nil, fmt.Errorf("get collection %s failed, err: %v", name, err.Error())
-	}
-	if res == nil {
-		return nil, fmt.Errorf("get collection %s failed", name)
-	}
-	return &res.Collection, nil
+	indexes Indexes, params ...*CreateCollectionParams) (*CreateCollectionResult, error) {
+	return createCollectionIfNotExists(ctx, r, name, shardNum, replicasNum, description, indexes, params...)
 }
 
 func (r *rpcImplementerCollection) CreateCollection(ctx context.Context, name string, shardNum, replicasNum uint32, description string, indexes Indexes, params ...*CreateCollectionParams) (*Collection, error) {
 	if r.database.IsAIDatabase() {
 		return nil, AIDbTypeError
 	}
+	if err := checkMetricType(indexes); err != nil {
+		return nil, err
+	}
+	if err := checkIndexParams(indexes.VectorIndex); err != nil {
+		return nil, err
+	}
+	if err := checkEmbeddingDimension(indexes, params...); err != nil {
+		return nil, err
+	}
+	if err := checkShardReplica(r.Options(), shardNum, replicasNum); err != nil {
+		return nil, err
+	}
+	if indexes.autoId() {
+		// the rpc request has no AutoId of index
+		httpImpl := &implementerCollection{SdkClient: r.SdkClient, database: r.database}
+		created, err := httpImpl.CreateCollection(ctx, name, shardNum, replicasNum, description, indexes, params...)
+		if err != nil {
+			return nil, err
+		}
+		coll := r.Collection(name)
+		coll.ShardNum = created.ShardNum
+		coll.ReplicasNum = created.ReplicasNum
+		coll.Description = created.Description
+		coll.Indexes = created.Indexes
+		coll.Embedding = created.Embedding
+		coll.TtlConfig = created.TtlConfig
+		return coll, nil
+	}
 	req := &olama.CreateCollectionRequest{
 		Database:    r.database.DatabaseName,
 		Collection:  name,
@@ -82,6 +98,20 @@ func (r *rpcImplementerCollection) CreateCollection(ctx context.Context, name st
 		req.Indexes[v.FieldName] = column
 	}
 
+	for _, v := range indexes.BinaryVectorIndex {
+		if v.Dimension%8 != 0 {
+			return nil, fmt.Errorf("the dimension of binary vector index %s must be a multiple of 8", v.FieldName)
+		}
+		column := &olama.IndexColumn{
+			FieldName:  v.FieldName,
+			FieldType:  string(v.FieldType),
+			IndexType:  string(v.IndexType),
+			MetricType: string(v.MetricType),
+			Dimension:  v.Dimension,
+		}
+		req.Indexes[v.FieldName] = column
+	}
+
 	for _, v := range indexes.FilterIndex {
 		column := &olama.IndexColumn{
 			FieldName: v.FieldName,
@@ -123,10 +153,11 @@ func (r *rpcImplementerCollection) CreateCollection(ctx context.Context, name st
 	coll.ReplicasNum = req.ReplicaNum
 	coll.Description = req.Description
 	coll.Indexes = indexes
+	coll.setCreateParams(params...)
 	return coll, nil
 }
 
-func (r *rpcImplementerCollection) ListCollection(ctx context.Context) (*ListCollectionResult, error) {
+func (r *rpcImplementerCollection) ListCollection(ctx context.Context, params ...*ListCollectionParams) (*ListCollectionResult, error) {
 	if r.database.IsAIDatabase() {
 		return nil, AIDbTypeError
 	}
@@ -137,12 +168,23 @@ func (r *rpcImplementerCollection) ListCollection(ctx context.Context) (*ListCol
 	if err != nil {
 		return nil, err
 	}
+	param := listCollectionParam(params)
+	names := make([]string, len(res.Collections))
+	for n, item := range res.Collections {
+		names[n] = item.Collection
+	}
+	selected, total := listRange(names, param)
 	var collections []*Collection
-	for _, collection := range res.Collections {
-		collections = append(collections, r.toCollection(collection))
+	for _, n := range selected {
+		coll := r.toCollection(res.Collections[n])
+		if !param.WithSchema {
+			coll.Indexes = Indexes{}
+		}
+		collections = append(collections, coll)
 	}
 	result := &ListCollectionResult{
 		Collections: collections,
+		Total:       total,
 	}
 	return result, nil
 }
@@ -163,6 +205,7 @@ func (r *rpcImplementerCollection) DescribeCollection(ctx context.Context, name
 		return nil, fmt.Errorf("get collection %s failed", name)
 	}
 	coll := r.toCollection(res.Collection)
+	resolveAlias(coll, name)
 	result := &DescribeCollectionResult{
 		Collection: *coll,
 	}
@@ -178,8 +221,9 @@ func (r *rpcImplementerCollection) DropCollection(ctx context.Context, name stri
 		Collection: name,
 	}
 	res, err := r.rpcClient.DropCollection(ctx, req)
+	r.database.schemas.invalidate(name)
 	if err != nil {
-		if strings.Contains(err.Error(), "not exist") {
+		if isNotExist(err) {
 			return &DropCollectionResult{}, nil
 		}
 		return nil, err
@@ -202,10 +246,16 @@ func (r *rpcImplementerCollection) TruncateCollection(ctx context.Context, name
 	return &TruncateCollectionResult{AffectedCount: int(res.AffectedCount)}, nil
 }
 
+// ModifyCollection modify the description and name of collection, see implementerCollection.ModifyCollection.
+func (r *rpcImplementerCollection) ModifyCollection(ctx context.Context, name string, param ModifyCollectionParams) (*DescribeCollectionResult, error) {
+	return modifyCollection(ctx, r, r.database, name, param)
+}
+
 func (r *rpcImplementerCollection) Collection(name string) *Collection {
 	coll := &Collection{
 		DatabaseName:   r.database.DatabaseName,
 		CollectionName: name,
+		schemas:        r.database.schemas,
 	}
 	flatImpl := &rpcImplementerFlatDocument{
 		SdkClient: r.SdkClient,
@@ -259,7 +309,8 @@ func (r *rpcImplementerCollection) toCollection(collectionItem *olama.CreateColl
 	}
 	if collectionItem.IndexStatus != nil {
 		coll.IndexStatus = IndexStatus{
-			Status: collectionItem.IndexStatus.Status,
+			Status:   collectionItem.IndexStatus.Status,
+			Progress: collectionItem.IndexStatus.Progress,
 		}
 		coll.IndexStatus.StartTime, _ = time.Parse("2006-01-02 15:04:05", collectionItem.IndexStatus.StartTime)
 	}
@@ -300,6 +351,16 @@ func (r *rpcImplementerCollection) toCollection(collectionItem *olama.CreateColl
 			vector.MetricType = MetricType(index.MetricType)
 			coll.Indexes.SparseVectorIndex = append(coll.Indexes.SparseVectorIndex, vector)
 
+		case string(BinaryVector):
+			vector := BinaryVectorIndex{}
+			vector.FieldName = index.FieldName
+			vector.FieldType = FieldType(index.FieldType)
+			vector.IndexType = IndexType(index.IndexType)
+			vector.Dimension = index.Dimension
+			vector.MetricType = MetricType(index.MetricType)
+			vector.IndexedCount = collectionItem.Size
+			coll.Indexes.BinaryVectorIndex = append(coll.Indexes.BinaryVectorIndex, vector)
+
 		case string(Array):
 			filter := FilterIndex{}
 			filter.FieldName = index.FieldName
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
 )
@@ -57,6 +56,7 @@ func (r *rpcImplementerDatabase) CreateDatabase(ctx context.Context, name string
 	result := new(CreateDatabaseResult)
 	result.AffectedCount = int(res.AffectedCount)
 	result.Database = *(r.Database(name))
+	result.Created = true
 	return result, err
 }
 
@@ -68,7 +68,7 @@ func (r *rpcImplementerDatabase) DropDatabase(ctx context.Context, name string)
 	}
 	res, err := r.rpcClient.DropDatabase(ctx, req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not exist") || strings.Contains(err.Error(), "can not find database") {
+		if isNotExist(err) {
 			return result, nil
 		}
 		return result, err
@@ -77,14 +77,15 @@ func (r *rpcImplementerDatabase) DropDatabase(ctx context.Context, name string)
 	return result, err
 }
 
-func (r *rpcImplementerDatabase) ListDatabase(ctx context.Context) (result *ListDatabaseResult, err error) {
+func (r *rpcImplementerDatabase) ListDatabase(ctx context.Context, params ...*ListDatabaseParams) (result *ListDatabaseResult, err error) {
 	req := &olama.DatabaseRequest{}
 	res, err := r.rpcClient.ListDatabases(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	result = new(ListDatabaseResult)
-	for _, v := range res.Databases {
+	result.Total = len(res.Databases)
+	for _, v := range listDatabaseNames(res.Databases, params) {
 		if res.Info[v].DbType == olama.DataType_AI_DOC {
 			db := r.AIDatabase(v)
 			db.Info.CreateTime = strconv.FormatInt(res.Info[v].CreateTime, 10)
@@ -99,6 +100,17 @@ func (r *rpcImplementerDatabase) ListDatabase(ctx context.Context) (result *List
 	return result, nil
 }
 
+func (r *rpcImplementerDatabase) DescribeDatabase(ctx context.Context, name string) (*DescribeDatabaseResult, error) {
+	result, err := r.httpImplementer.DescribeDatabase(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	db := r.Database(name)
+	db.Info = result.Info
+	result.Database = *db
+	return result, nil
+}
+
 func (r *rpcImplementerDatabase) CreateAIDatabase(ctx context.Context, name string) (result *CreateAIDatabaseResult, err error) {
 	return r.httpImplementer.CreateAIDatabase(ctx, name)
 }
@@ -114,6 +126,7 @@ func (r *rpcImplementerDatabase) Database(name string) *Database {
 			DbType: DbTypeBase,
 		},
 	}
+	database.schemas = databaseSchemasOf(r.SdkClient, name)
 	collImpl := &rpcImplementerCollection{
 		SdkClient: r.SdkClient,
 		rpcClient: r.rpcClient,
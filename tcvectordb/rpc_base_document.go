@@ -6,6 +6,8 @@ import (
 
 	"github.com/tencent/vectordatabase-sdk-go/tcvdbtext/encoder"
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/vectorutil"
+	"google.golang.org/protobuf/proto"
 )
 
 var _ DocumentInterface = &rpcImplementerDocument{}
@@ -20,26 +22,137 @@ type rpcImplementerDocument struct {
 }
 
 func (r *rpcImplementerDocument) Upsert(ctx context.Context, documents interface{}, params ...*UpsertDocumentParams) (*UpsertDocumentResult, error) {
-	return r.flat.Upsert(ctx, r.database.DatabaseName, r.collection.CollectionName, documents, params...)
+	if len(params) != 0 && params[0] != nil && len(params[0].FieldMask) != 0 {
+		documents, err := checkMaskedDocuments(ctx, r.collection, documents, params[0])
+		if err != nil {
+			return nil, err
+		}
+		return upsertWithFieldMask(ctx, r.SdkClient, r.flat, r.database.DatabaseName, r.collection.CollectionName, documents, params[0],
+			func(ctx context.Context, docs interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+				return r.Upsert(ctx, docs, param)
+			})
+	}
+	if len(params) == 0 || params[0] == nil || !params[0].SkipDimensionCheck {
+		err := checkDocumentsDimension(r.collection.schema(ctx), documents)
+		if err != nil {
+			return nil, r.collection.schemaError(ctx, err)
+		}
+	}
+	if len(params) == 0 || params[0] == nil || !params[0].SkipTtlCheck {
+		err := checkTtlField(r.collection.schema(ctx), documents)
+		if err != nil {
+			return nil, r.collection.schemaError(ctx, err)
+		}
+	}
+	documents, err := coerceDocumentFields(r.collection.schema(ctx), documents)
+	if err != nil {
+		return nil, r.collection.schemaError(ctx, err)
+	}
+	var zeros []string
+	if autoNormalize(r.SdkClient.Options(), r.collection.schema(ctx)) {
+		// the NaN and Inf components are checked before normalizing, which spreads them to the whole vector
+		documents, err = checkDocumentVectors(documents, len(params) != 0 && params[0] != nil && params[0].SanitizeVectors)
+		if err != nil {
+			return nil, err
+		}
+		documents, zeros = normalizeDocuments(documents)
+	}
+	params = withCollectionAutoId(r.collection.schema(ctx), params)
+	res, err := r.flat.Upsert(ctx, r.database.DatabaseName, r.collection.CollectionName, documents, params...)
+	return res, zeroVectorError(err, zeros, nil)
 }
 
 func (r *rpcImplementerDocument) Query(ctx context.Context, documentIds []string, params ...*QueryDocumentParams) (*QueryDocumentResult, error) {
-	return r.flat.Query(ctx, r.database.DatabaseName, r.collection.CollectionName, documentIds, params...)
+	if len(params) != 0 && params[0] != nil {
+		if err := checkSortFields(r.collection.schema(ctx), params[0].Sort); err != nil {
+			return nil, r.collection.schemaError(ctx, err)
+		}
+		if err := checkOutputFields(r.SdkClient, r.collection.schema(ctx), params[0].OutputFields); err != nil {
+			return nil, err
+		}
+	}
+	res, err := r.flat.Query(ctx, r.database.DatabaseName, r.collection.CollectionName, documentIds, params...)
+	if err != nil {
+		return nil, err
+	}
+	fillBinaryVector(r.collection.schema(ctx), res.Documents)
+	return res, nil
+}
+
+func (r *rpcImplementerDocument) QueryIterator(ctx context.Context, filter *Filter, batchSize int64, params ...*QueryDocumentParams) *QueryIterator {
+	return newQueryIterator(ctx, filter, batchSize, params, func(ctx context.Context, param *QueryDocumentParams) (*QueryDocumentResult, error) {
+		return r.flat.Query(ctx, r.database.DatabaseName, r.collection.CollectionName, nil, param)
+	})
+}
+
+func (r *rpcImplementerDocument) Count(ctx context.Context, filter *Filter, params ...*CountDocumentParams) (*CountDocumentResult, error) {
+	return r.flat.Count(ctx, r.database.DatabaseName, r.collection.CollectionName, filter, params...)
 }
 
 func (r *rpcImplementerDocument) Search(ctx context.Context, vectors [][]float32, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
-	return r.flat.Search(ctx, r.database.DatabaseName, r.collection.CollectionName, vectors, params...)
+	if err := checkOutputFields(r.SdkClient, r.collection.schema(ctx), searchOutputFields(params)); err != nil {
+		return nil, err
+	}
+	if len(params) == 0 || params[0] == nil || !(params[0].SkipDimensionCheck || params[0].PartialFailure) {
+		err := checkSearchDimension(r.collection.schema(ctx), vectors)
+		if err != nil {
+			return nil, r.collection.schemaError(ctx, err)
+		}
+	}
+	var zeros []int
+	if len(vectors) != 0 && autoNormalize(r.SdkClient.Options(), r.collection.schema(ctx)) {
+		// the NaN and Inf components are checked before normalizing, the same as Upsert
+		checked, err := checkSearchVectors(vectors, params)
+		if err != nil {
+			return nil, err
+		}
+		vectors, zeros = vectorutil.NormalizedL2Batch(checked)
+	}
+	res, err := r.flat.Search(ctx, r.database.DatabaseName, r.collection.CollectionName, vectors, params...)
+	return res, zeroVectorError(err, nil, zeros)
+}
+
+func (r *rpcImplementerDocument) SearchBinary(ctx context.Context, vectors [][]byte, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	if err := checkOutputFields(r.SdkClient, r.collection.schema(ctx), searchOutputFields(params)); err != nil {
+		return nil, err
+	}
+	if len(params) == 0 || params[0] == nil || !params[0].SkipDimensionCheck {
+		err := checkSearchBinaryDimension(r.collection.schema(ctx), vectors)
+		if err != nil {
+			return nil, r.collection.schemaError(ctx, err)
+		}
+	}
+	res, err := r.flat.SearchBinary(ctx, r.database.DatabaseName, r.collection.CollectionName, vectors, params...)
+	if err != nil {
+		return nil, err
+	}
+	for _, docs := range res.Documents {
+		fillBinaryVector(r.collection.schema(ctx), docs)
+	}
+	return res, nil
 }
 
 func (r *rpcImplementerDocument) SearchById(ctx context.Context, documentIds []string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	if err := checkOutputFields(r.SdkClient, r.collection.schema(ctx), searchOutputFields(params)); err != nil {
+		return nil, err
+	}
 	return r.flat.SearchById(ctx, r.database.DatabaseName, r.collection.CollectionName, documentIds, params...)
 }
 
 func (r *rpcImplementerDocument) SearchByText(ctx context.Context, text map[string][]string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	if err := checkOutputFields(r.SdkClient, r.collection.schema(ctx), searchOutputFields(params)); err != nil {
+		return nil, err
+	}
+	if err := checkEmbeddingEnabled(r.collection.schema(ctx)); err != nil {
+		return nil, r.collection.schemaError(ctx, err)
+	}
 	return r.flat.SearchByText(ctx, r.database.DatabaseName, r.collection.CollectionName, text, params...)
 }
 
 func (r *rpcImplementerDocument) HybridSearch(ctx context.Context, params HybridSearchDocumentParams) (*SearchDocumentResult, error) {
+	if err := checkOutputFields(r.SdkClient, r.collection.schema(ctx), params.OutputFields); err != nil {
+		return nil, err
+	}
 	return r.flat.HybridSearch(ctx, r.database.DatabaseName, r.collection.CollectionName, params)
 }
 
@@ -58,6 +171,33 @@ type rpcImplementerFlatDocument struct {
 
 func (r *rpcImplementerFlatDocument) Upsert(ctx context.Context, databaseName, collectionName string,
 	documents interface{}, params ...*UpsertDocumentParams) (*UpsertDocumentResult, error) {
+	documents, err := checkDocumentVectors(documents, len(params) != 0 && params[0] != nil && params[0].SanitizeVectors)
+	if err != nil {
+		return nil, err
+	}
+	if len(params) != 0 && params[0] != nil && params[0].BatchSize > 0 {
+		return upsertInBatches(ctx, documents, params[0], r.Options().MaxRequestBytes, func(ctx context.Context, docs interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+			return r.Upsert(ctx, databaseName, collectionName, docs, param)
+		})
+	}
+	if len(params) != 0 && params[0] != nil && params[0].DiagnoseOnFailure {
+		return upsertDiagnosed(ctx, documents, params[0], func(ctx context.Context, docs interface{}, param *UpsertDocumentParams) (*UpsertDocumentResult, error) {
+			return r.Upsert(ctx, databaseName, collectionName, docs, param)
+		})
+	}
+
+	if err := checkArrayFields(documents); err != nil {
+		return nil, err
+	}
+	if len(params) != 0 && params[0] != nil && params[0].AutoId {
+		// the rpc response has no generated ids
+		httpImpl := &implementerFlatDocument{SdkClient: r.SdkClient}
+		return httpImpl.Upsert(ctx, databaseName, collectionName, documents, params...)
+	}
+	if err := checkDocumentIds(documents, false); err != nil {
+		return nil, err
+	}
+
 	req := &olama.UpsertRequest{
 		Database:   databaseName,
 		Collection: collectionName,
@@ -70,9 +210,16 @@ func (r *rpcImplementerFlatDocument) Upsert(ctx context.Context, databaseName, c
 				Vector: doc.Vector,
 				Fields: make(map[string]*olama.Field),
 			}
+			if len(doc.BinaryVector) != 0 {
+				d.Vector = binaryToFloat32(doc.BinaryVector)
+			}
 
+			sparseVector, err := checkSparseVector(doc.SparseVector)
+			if err != nil {
+				return nil, fmt.Errorf("upsert failed. doc's sparse_vector data is incorrect. doc id is %v. err: %v", d.Id, err.Error())
+			}
 			d.SparseVector = make([]*olama.SparseVecItem, 0)
-			for _, sv := range doc.SparseVector {
+			for _, sv := range sparseVector {
 				d.SparseVector = append(d.SparseVector, &olama.SparseVecItem{
 					TermId: sv.TermId,
 					Score:  sv.Score,
@@ -80,7 +227,11 @@ func (r *rpcImplementerFlatDocument) Upsert(ctx context.Context, databaseName, c
 			}
 
 			for k, v := range doc.Fields {
-				d.Fields[k] = ConvertField2Grpc(&v)
+				field, err := convertField2Grpc(&v)
+				if err != nil {
+					return nil, fmt.Errorf("upsert failed. doc id is %v, field %s: %v", d.Id, k, err)
+				}
+				d.Fields[k] = field
 			}
 			req.Documents = append(req.Documents, d)
 		}
@@ -90,16 +241,18 @@ func (r *rpcImplementerFlatDocument) Upsert(ctx context.Context, databaseName, c
 			var aVector []float32
 			if id, ok := doc["id"]; ok {
 				if sId, ok = id.(string); ok {
-					delete(doc, "id")
 				} else {
 					return nil, fmt.Errorf("upsert failed, because of incorrect id field type, which must be string")
 				}
 			}
 			if vector, ok := doc["vector"]; ok {
-				if aVector, ok = vector.([]float32); ok {
-					delete(doc, "vector")
-				} else {
-					return nil, fmt.Errorf("upsert failed, because of incorrect vector field type, which must be []float32")
+				switch v := vector.(type) {
+				case []float32:
+					aVector = v
+				case []byte:
+					aVector = binaryToFloat32(v)
+				default:
+					return nil, fmt.Errorf("upsert failed, because of incorrect vector field type, which must be []float32 or []byte")
 				}
 			}
 
@@ -112,23 +265,37 @@ func (r *rpcImplementerFlatDocument) Upsert(ctx context.Context, databaseName, c
 			if sparseVector, ok := doc["sparse_vector"]; ok {
 				if aSparseVector, ok := sparseVector.([][]interface{}); ok {
 					d.SparseVector = make([]*olama.SparseVecItem, 0)
+					svItems := make([]encoder.SparseVecItem, 0, len(aSparseVector))
 					for _, sv := range aSparseVector {
 						svItem, err := ConvSliceInterface2SparseVecItem(sv)
 						if err != nil {
 							return nil, fmt.Errorf("upsert failed. doc's sparse_vector data is incorrect. doc id is %v. err: %v", d.Id, err.Error())
 						}
+						svItems = append(svItems, *svItem)
+					}
+					svItems, err := checkSparseVector(svItems)
+					if err != nil {
+						return nil, fmt.Errorf("upsert failed. doc's sparse_vector data is incorrect. doc id is %v. err: %v", d.Id, err.Error())
+					}
+					for _, svItem := range svItems {
 						d.SparseVector = append(d.SparseVector, &olama.SparseVecItem{
 							TermId: svItem.TermId,
 							Score:  svItem.Score})
 					}
-					delete(doc, "sparse_vector")
 				} else {
 					return nil, fmt.Errorf("upsert failed, because of incorrect sparse_vector field type, which must be [][]interface{}")
 				}
 			}
 
 			for k, v := range doc {
-				d.Fields[k] = ConvertField2Grpc(&Field{Val: v})
+				if isUpsertReservedKey(k) {
+					continue
+				}
+				field, err := convertField2Grpc(&Field{Val: v})
+				if err != nil {
+					return nil, fmt.Errorf("upsert failed. doc id is %v, field %s: %v", d.Id, k, err)
+				}
+				d.Fields[k] = field
 			}
 			req.Documents = append(req.Documents, d)
 		}
@@ -147,15 +314,30 @@ func (r *rpcImplementerFlatDocument) Upsert(ctx context.Context, databaseName, c
 		req.BuildIndex = true
 	}
 
+	if err := checkRequestSize(r.Options(), "/document/upsert", proto.Size(req), len(req.Documents)); err != nil {
+		return nil, err
+	}
 	res, err := r.rpcClient.Upsert(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	return &UpsertDocumentResult{AffectedCount: int(res.AffectedCount)}, nil
+	if err = strictWarning(r.Options(), "/document/upsert", res.Warning); err != nil {
+		return nil, err
+	}
+	return &UpsertDocumentResult{
+		AffectedCount:      int(res.AffectedCount),
+		EmbeddingExtraInfo: EmbeddingExtraInfo{TokenUsed: res.GetEmbeddingExtraInfo().GetTokenUsed()},
+		Warning:            res.Warning,
+	}, nil
 }
 
 func (r *rpcImplementerFlatDocument) Query(ctx context.Context, databaseName, collectionName string,
 	documentIds []string, params ...*QueryDocumentParams) (*QueryDocumentResult, error) {
+	if len(params) != 0 && params[0] != nil && (len(params[0].Sort) != 0 || params[0].RawResponse) {
+		// the rpc query does not support sort yet, and has no raw json
+		httpImpl := &implementerFlatDocument{SdkClient: r.SdkClient}
+		return httpImpl.Query(ctx, databaseName, collectionName, documentIds, params...)
+	}
 	req := &olama.QueryRequest{
 		Database:   databaseName,
 		Collection: collectionName,
@@ -168,9 +350,12 @@ func (r *rpcImplementerFlatDocument) Query(ctx context.Context, databaseName, co
 		param := params[0]
 		req.Query.Filter = param.Filter.Cond()
 		req.Query.RetrieveVector = param.RetrieveVector
-		req.Query.OutputFields = param.OutputFields
+		req.Query.OutputFields = uniqueFields(param.OutputFields)
 		req.Query.Offset = param.Offset
 		req.Query.Limit = param.Limit
+		if param.ReadConsistency != "" {
+			req.ReadConsistency = string(param.ReadConsistency)
+		}
 	}
 	res, err := r.rpcClient.Query(ctx, req)
 	if err != nil {
@@ -202,14 +387,35 @@ func (r *rpcImplementerFlatDocument) Query(ctx context.Context, databaseName, co
 	return result, nil
 }
 
+// Count the rpc service has no count api, so it is sent by http.
+func (r *rpcImplementerFlatDocument) Count(ctx context.Context, databaseName, collectionName string,
+	filter *Filter, params ...*CountDocumentParams) (*CountDocumentResult, error) {
+	httpImpl := &implementerFlatDocument{SdkClient: r.SdkClient}
+	return httpImpl.Count(ctx, databaseName, collectionName, filter, params...)
+}
+
 func (r *rpcImplementerFlatDocument) Search(ctx context.Context, databaseName, collectionName string,
 	vectors [][]float32, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
-	return r.search(ctx, databaseName, collectionName, nil, vectors, nil, params...)
+	vectors, err := checkSearchVectors(vectors, params)
+	if err != nil {
+		return nil, err
+	}
+	return searchWithPartialFailure(ctx, vectors, params, func(ctx context.Context, vectors [][]float32) (*SearchDocumentResult, error) {
+		return r.search(ctx, databaseName, collectionName, nil, vectors, nil, params...)
+	})
+}
+
+func (r *rpcImplementerFlatDocument) SearchBinary(ctx context.Context, databaseName, collectionName string,
+	vectors [][]byte, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	return r.Search(ctx, databaseName, collectionName, binariesToFloat32(vectors), params...)
 }
 
 func (r *rpcImplementerFlatDocument) SearchById(ctx context.Context, databaseName, collectionName string,
 	documentIds []string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
-	return r.search(ctx, databaseName, collectionName, documentIds, nil, nil, params...)
+	return searchByIdAligned(ctx, r, databaseName, collectionName, documentIds, params,
+		func(ctx context.Context, documentIds []string) (*SearchDocumentResult, error) {
+			return r.search(ctx, databaseName, collectionName, documentIds, nil, nil, params...)
+		})
 }
 
 func (r *rpcImplementerFlatDocument) SearchByText(ctx context.Context, databaseName, collectionName string,
@@ -219,12 +425,19 @@ func (r *rpcImplementerFlatDocument) SearchByText(ctx context.Context, databaseN
 
 func (r *rpcImplementerFlatDocument) HybridSearch(ctx context.Context, databaseName, collectionName string,
 	params HybridSearchDocumentParams) (*SearchDocumentResult, error) {
+	if params.RawResponse {
+		httpImpl := &implementerFlatDocument{SdkClient: r.SdkClient}
+		return httpImpl.HybridSearch(ctx, databaseName, collectionName, params)
+	}
 	req := &olama.SearchRequest{
 		Database:        databaseName,
 		Collection:      collectionName,
 		ReadConsistency: string(r.SdkClient.Options().ReadConsistency),
 		Search:          &olama.SearchCond{},
 	}
+	if params.ReadConsistency != "" {
+		req.ReadConsistency = string(params.ReadConsistency)
+	}
 
 	req.Search.Ann = make([]*olama.AnnData, 0)
 	req.Search.Sparse = make([]*olama.SparseData, 0)
@@ -241,16 +454,16 @@ func (r *rpcImplementerFlatDocument) HybridSearch(ctx context.Context, databaseN
 			req.Search.Ann[i].Limit = uint32(*annParam.Limit)
 		}
 
-		vectorArray := make([]*olama.VectorArray, 0, len(req.Search.Vectors))
-		if vec, ok := annParam.Data.([]float32); ok {
-			vectorArray = append(vectorArray, &olama.VectorArray{Vector: vec})
-		} else {
-			return nil, fmt.Errorf("hybridSearch failed, because of AnnParam.Vectors field type, " +
-				"which must be []float32")
+		switch data := annParam.Data.(type) {
+		case []float32:
+			req.Search.Ann[i].Data = []*olama.VectorArray{{Vector: data}}
+		case string:
+			req.Search.Ann[i].DataExpr = []string{data}
+		default:
+			return nil, fmt.Errorf("hybridSearch failed, because of AnnParam.Data field type, " +
+				"which must be []float32 or string")
 		}
 
-		req.Search.Ann[i].Data = vectorArray
-
 		if annParam.Params != nil {
 			req.Search.Ann[i].Params = new(olama.SearchParams)
 			req.Search.Ann[i].Params.Nprobe = annParam.Params.Nprobe
@@ -269,7 +482,7 @@ func (r *rpcImplementerFlatDocument) HybridSearch(ctx context.Context, databaseN
 			FieldName: fieldName,
 		})
 		if matchParam.Limit != nil {
-			req.Search.Ann[i].Limit = uint32(*matchParam.Limit)
+			req.Search.Sparse[i].Limit = uint32(*matchParam.Limit)
 		}
 
 		sparseVectorArray := make([]*olama.SparseVectorArray, 0)
@@ -307,9 +520,11 @@ func (r *rpcImplementerFlatDocument) HybridSearch(ctx context.Context, databaseN
 	}
 
 	req.Search.Filter = params.Filter.Cond()
-	req.Search.RetrieveVector = params.RetrieveVector
-	req.Search.Outputfields = params.OutputFields
-	req.Search.Limit = uint32(*params.Limit)
+	req.Search.RetrieveVector = retrieveVector(params.RetrieveVector, params.OutputFields)
+	req.Search.Outputfields = uniqueFields(params.OutputFields)
+	if params.Limit != nil {
+		req.Search.Limit = uint32(*params.Limit)
+	}
 
 	res, err := r.rpcClient.HybridSearch(ctx, req)
 	if err != nil {
@@ -342,27 +557,34 @@ func (r *rpcImplementerFlatDocument) HybridSearch(ctx context.Context, databaseN
 		documents = append(documents, vecDoc)
 	}
 	result := &SearchDocumentResult{
-		Warning:   res.Warning,
-		Documents: documents,
+		Warning:            res.Warning,
+		Documents:          documents,
+		EmbeddingExtraInfo: EmbeddingExtraInfo{TokenUsed: res.GetEmbeddingExtraInfo().GetTokenUsed()},
 	}
 	return result, nil
 }
 
 func (r *rpcImplementerFlatDocument) Delete(ctx context.Context, databaseName, collectionName string,
 	param DeleteDocumentParams) (*DeleteDocumentResult, error) {
-	req := &olama.DeleteRequest{
-		Database:   databaseName,
-		Collection: collectionName,
-		Query: &olama.QueryCond{
-			DocumentIds: param.DocumentIds,
-			Filter:      param.Filter.Cond(),
-		},
-	}
-	res, err := r.rpcClient.Dele(ctx, req)
-	if err != nil {
+	if err := checkDeleteParams(param); err != nil {
 		return nil, err
 	}
-	return &DeleteDocumentResult{AffectedCount: int(res.AffectedCount)}, nil
+	return deleteInBatches(ctx, param, func(ctx context.Context, ids []string, limit int64) (int, string, error) {
+		req := &olama.DeleteRequest{
+			Database:   databaseName,
+			Collection: collectionName,
+			Query: &olama.QueryCond{
+				DocumentIds: ids,
+				Filter:      param.Filter.Cond(),
+				Limit:       limit,
+			},
+		}
+		res, err := r.rpcClient.Dele(ctx, req)
+		if err != nil {
+			return 0, "", err
+		}
+		return int(res.AffectedCount), "", nil
+	})
 }
 
 func (r *rpcImplementerFlatDocument) Update(ctx context.Context, databaseName, collectionName string,
@@ -380,8 +602,12 @@ func (r *rpcImplementerFlatDocument) Update(ctx context.Context, databaseName, c
 		},
 	}
 
+	sparseVector, err := checkSparseVector(param.UpdateSparseVec)
+	if err != nil {
+		return nil, fmt.Errorf("update failed. %v", err.Error())
+	}
 	req.Update.SparseVector = make([]*olama.SparseVecItem, 0)
-	for _, sv := range param.UpdateSparseVec {
+	for _, sv := range sparseVector {
 		req.Update.SparseVector = append(req.Update.SparseVector, &olama.SparseVecItem{
 			TermId: sv.TermId,
 			Score:  sv.Score,
@@ -390,7 +616,11 @@ func (r *rpcImplementerFlatDocument) Update(ctx context.Context, databaseName, c
 
 	if updatefields, ok := param.UpdateFields.(map[string]Field); ok {
 		for k, v := range updatefields {
-			req.Update.Fields[k] = ConvertField2Grpc(&v)
+			field, err := convertField2Grpc(&v)
+			if err != nil {
+				return nil, fmt.Errorf("update failed, field %s: %v", k, err)
+			}
+			req.Update.Fields[k] = field
 		}
 	} else if updatefields, ok := param.UpdateFields.(map[string]interface{}); ok {
 		if vector, ok := updatefields["vector"]; ok {
@@ -422,22 +652,85 @@ func (r *rpcImplementerFlatDocument) Update(ctx context.Context, databaseName, c
 		}
 
 		for k, v := range updatefields {
-			req.Update.Fields[k] = ConvertField2Grpc(&Field{Val: v})
+			field, err := convertField2Grpc(&Field{Val: v})
+			if err != nil {
+				return nil, fmt.Errorf("update failed, field %s: %v", k, err)
+			}
+			req.Update.Fields[k] = field
 		}
-	} else {
+	} else if param.UpdateFields != nil {
 		return nil, fmt.Errorf("update failed, because of incorrect UpdateDocumentParams.UpdateFields field type, " +
 			"which must be map[string]Field or map[string]interface{}")
 	}
+	if len(req.Update.Vector) == 0 && len(req.Update.SparseVector) == 0 && len(req.Update.Fields) == 0 {
+		return nil, errEmptyUpdate
+	}
+
+	result := new(UpdateDocumentResult)
+	result.AffectedCount, result.Warning, err = sendIdBatches(ctx, param.QueryIds, updateIdBatchOption(param),
+		func(ctx context.Context, ids []string) (int, string, error) {
+			res, err := r.rpcClient.Update(ctx, &olama.UpdateRequest{
+				Database:   req.Database,
+				Collection: req.Collection,
+				Query:      &olama.QueryCond{DocumentIds: ids, Filter: req.Query.Filter},
+				Update:     req.Update,
+			})
+			if err != nil {
+				return 0, "", err
+			}
+			if err = strictWarning(r.Options(), "/document/update", res.Warning); err != nil {
+				return 0, "", err
+			}
+			return int(res.AffectedCount), res.Warning, nil
+		})
+	if err != nil {
+		if isIdBatchError(err) {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
 
-	res, err := r.rpcClient.Update(ctx, req)
+func (r *rpcImplementerFlatDocument) TruncateCollection(ctx context.Context, databaseName, collectionName string) (*TruncateCollectionResult, error) {
+	req := &olama.TruncateCollectionRequest{
+		Database:   databaseName,
+		Collection: collectionName,
+	}
+	res, err := r.rpcClient.TruncateCollection(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	return &UpdateDocumentResult{AffectedCount: int(res.AffectedCount)}, nil
+	return &TruncateCollectionResult{AffectedCount: int(res.AffectedCount)}, nil
+}
+
+func (r *rpcImplementerFlatDocument) DescribeCollection(ctx context.Context, databaseName, collectionName string) (*DescribeCollectionResult, error) {
+	collImpl := &rpcImplementerCollection{SdkClient: r.SdkClient, rpcClient: r.rpcClient, database: flatDatabase(databaseName)}
+	return collImpl.DescribeCollection(ctx, collectionName)
+}
+
+func (r *rpcImplementerFlatDocument) SetAlias(ctx context.Context, databaseName, collectionName, aliasName string) (*SetAliasResult, error) {
+	aliasImpl := &rpcImplementerAlias{SdkClient: r.SdkClient, rpcClient: r.rpcClient, database: flatDatabase(databaseName)}
+	return aliasImpl.SetAlias(ctx, collectionName, aliasName)
+}
+
+func (r *rpcImplementerFlatDocument) DeleteAlias(ctx context.Context, databaseName, aliasName string) (*DeleteAliasResult, error) {
+	aliasImpl := &rpcImplementerAlias{SdkClient: r.SdkClient, rpcClient: r.rpcClient, database: flatDatabase(databaseName)}
+	return aliasImpl.DeleteAlias(ctx, aliasName)
 }
 
 func (r *rpcImplementerFlatDocument) search(ctx context.Context, databaseName, collectionName string,
 	documentIds []string, vectors [][]float32, text map[string][]string, params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+	if len(params) != 0 && params[0] != nil && params[0].GroupByField != "" {
+		return searchGrouped(params, func(params ...*SearchDocumentParams) (*SearchDocumentResult, error) {
+			return r.search(ctx, databaseName, collectionName, documentIds, vectors, text, params...)
+		})
+	}
+	if len(params) != 0 && params[0] != nil && params[0].RawResponse {
+		// the raw json is returned by http only
+		httpImpl := &implementerFlatDocument{SdkClient: r.SdkClient}
+		return httpImpl.search(ctx, databaseName, collectionName, documentIds, vectors, text, params...)
+	}
 	req := &olama.SearchRequest{
 		Database:        databaseName,
 		Collection:      collectionName,
@@ -456,9 +749,12 @@ func (r *rpcImplementerFlatDocument) search(ctx context.Context, databaseName, c
 	if len(params) != 0 && params[0] != nil {
 		param := params[0]
 		req.Search.Filter = param.Filter.Cond()
-		req.Search.RetrieveVector = param.RetrieveVector
-		req.Search.Outputfields = param.OutputFields
+		req.Search.RetrieveVector = retrieveVector(param.RetrieveVector, param.OutputFields)
+		req.Search.Outputfields = uniqueFields(param.OutputFields)
 		req.Search.Limit = uint32(param.Limit)
+		if param.ReadConsistency != "" {
+			req.ReadConsistency = string(param.ReadConsistency)
+		}
 		if param.Params != nil {
 			req.Search.Params = &olama.SearchParams{
 				Nprobe: param.Params.Nprobe,
@@ -466,6 +762,15 @@ func (r *rpcImplementerFlatDocument) search(ctx context.Context, databaseName, c
 				Radius: param.Params.Radius,
 			}
 		}
+		if param.Radius != 0 && (param.Params == nil || param.Params.Radius == 0) {
+			if req.Search.Params == nil {
+				req.Search.Params = new(olama.SearchParams)
+			}
+			req.Search.Params.Radius = param.Radius
+		}
+	}
+	if err := checkRequestSize(r.Options(), "/document/search", proto.Size(req), 0); err != nil {
+		return nil, err
 	}
 	res, err := r.rpcClient.Search(ctx, req)
 	if err != nil {
@@ -481,6 +786,13 @@ func (r *rpcImplementerFlatDocument) search(ctx context.Context, databaseName, c
 				Score:  doc.Score,
 				Fields: make(map[string]Field),
 			}
+			d.SparseVector = make([]encoder.SparseVecItem, 0)
+			for _, sv := range doc.SparseVector {
+				d.SparseVector = append(d.SparseVector, encoder.SparseVecItem{
+					TermId: sv.TermId,
+					Score:  sv.Score,
+				})
+			}
 			for n, v := range doc.Fields {
 				d.Fields[n] = *ConvertGrpc2Field(v)
 			}
@@ -489,8 +801,9 @@ func (r *rpcImplementerFlatDocument) search(ctx context.Context, databaseName, c
 		documents = append(documents, vecDoc)
 	}
 	result := &SearchDocumentResult{
-		Warning:   res.Warning,
-		Documents: documents,
+		Warning:            res.Warning,
+		Documents:          documents,
+		EmbeddingExtraInfo: EmbeddingExtraInfo{TokenUsed: res.GetEmbeddingExtraInfo().GetTokenUsed()},
 	}
 	return result, nil
 }
@@ -17,9 +17,20 @@ type rpcImplementerIndex struct {
 }
 
 func (r *rpcImplementerIndex) RebuildIndex(ctx context.Context, params ...*RebuildIndexParams) (*RebuildIndexResult, error) {
+	if r.collection == nil {
+		return nil, errIndexCollectionRequired(r.database)
+	}
 	return r.flat.RebuildIndex(ctx, r.database.DatabaseName, r.collection.CollectionName, params...)
 }
 
 func (r *rpcImplementerIndex) AddIndex(ctx context.Context, params ...*AddIndexParams) error {
 	return r.flat.AddIndex(ctx, r.database.DatabaseName, r.collection.CollectionName, params...)
 }
+
+func (r *rpcImplementerIndex) ModifyVectorIndex(ctx context.Context, param ModifyVectorIndexParams) (*ModifyVectorIndexResult, error) {
+	return r.flat.ModifyVectorIndex(ctx, r.database.DatabaseName, r.collection.CollectionName, param)
+}
+
+func (r *rpcImplementerIndex) SyncSchema(ctx context.Context, desired Indexes, option ...*SyncOption) (*SyncSchemaResult, error) {
+	return r.flat.SyncSchema(ctx, r.database.DatabaseName, r.collection.CollectionName, desired, option...)
+}
@@ -20,6 +20,9 @@ func (r *rpcImplementerFlatIndex) RebuildIndex(ctx context.Context, databaseName
 	}
 	if len(params) != 0 && params[0] != nil {
 		param := params[0]
+		if err := param.Throttle.check(); err != nil {
+			return nil, err
+		}
 		req.DropBeforeRebuild = param.DropBeforeRebuild
 		req.Throttle = int32(param.Throttle)
 	}
@@ -41,9 +44,10 @@ func (r *rpcImplementerFlatIndex) AddIndex(ctx context.Context, databaseName, co
 		req.Indexes = make(map[string]*olama.IndexColumn, len(param.FilterIndexs))
 		for _, index := range param.FilterIndexs {
 			req.Indexes[index.FieldName] = &olama.IndexColumn{
-				FieldName: index.FieldName,
-				FieldType: string(index.FieldType),
-				IndexType: string(index.IndexType),
+				FieldName:        index.FieldName,
+				FieldType:        string(index.FieldType),
+				FieldElementType: string(index.ElemType),
+				IndexType:        string(index.IndexType),
 			}
 		}
 		if param.BuildExistedData == nil {
@@ -62,3 +66,12 @@ func (r *rpcImplementerFlatIndex) AddIndex(ctx context.Context, databaseName, co
 
 	return nil
 }
+
+func (r *rpcImplementerFlatIndex) ModifyVectorIndex(ctx context.Context, databaseName, collectionName string, param ModifyVectorIndexParams) (*ModifyVectorIndexResult, error) {
+	httpImpl := &implementerFlatIndex{SdkClient: r.SdkClient}
+	return httpImpl.ModifyVectorIndex(ctx, databaseName, collectionName, param)
+}
+
+func (r *rpcImplementerFlatIndex) SyncSchema(ctx context.Context, databaseName, collectionName string, desired Indexes, option ...*SyncOption) (*SyncSchemaResult, error) {
+	return syncSchema(ctx, r, databaseName, collectionName, desired, option...)
+}
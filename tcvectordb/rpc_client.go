@@ -3,21 +3,29 @@ package tcvectordb
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/olama"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type RpcClient struct {
+	// timeout is the first field to be 64-bit aligned for the atomic access on 32-bit platforms, the same as Client
+	timeout int64
+
 	DatabaseInterface
 	FlatInterface
 	FlatIndexInterface
+	UserInterface
+	BackupInterface
 
 	httpImplementer SdkClient
 	rpcClient       olama.SearchEngineClient
@@ -26,9 +34,12 @@ type RpcClient struct {
 	username        string
 	key             string
 	option          ClientOption
-	debug           bool
+	// debug is accessed atomically, the same as Client
+	debug int32
 }
 
+// NewRpcClient new rpc client with url, username and api key. The document apis are sent by grpc,
+// whose vectors are packed as binary float32 instead of json arrays, prefer it for the large upsert and search.
 func NewRpcClient(url, username, key string, option *ClientOption) (*RpcClient, error) {
 	if option == nil {
 		option = &defaultOption
@@ -50,12 +61,16 @@ func NewRpcClient(url, username, key string, option *ClientOption) (*RpcClient,
 		rpcTarget = url
 	}
 
+	if option.DryRun != nil {
+		return nil, errors.New("dry run is not supported by rpc client, use NewClient instead")
+	}
+
 	cli := new(RpcClient)
 	cli.url = url
 	cli.username = username
 	cli.key = key
-	cli.debug = false
 	cli.option = optionMerge(*option)
+	cli.timeout = int64(cli.option.Timeout)
 
 	cc, err := grpc.Dial(rpcTarget,
 		grpc.WithUnaryInterceptor(newInterceptor(cli)),
@@ -94,6 +109,9 @@ func NewRpcClient(url, username, key string, option *ClientOption) (*RpcClient,
 	cli.DatabaseInterface = databaseImpl
 	cli.FlatInterface = flatImpl
 	cli.FlatIndexInterface = flatIndexImpl
+	// the user api is not supported by rpc
+	cli.UserInterface = httpc.UserInterface
+	cli.BackupInterface = httpc.BackupInterface
 
 	return cli, nil
 }
@@ -102,53 +120,127 @@ func (r *RpcClient) Request(ctx context.Context, req, res interface{}) error {
 	return r.httpImplementer.Request(ctx, req, res)
 }
 
+// Do sends the request of method and path by http, see Client.Do.
+func (r *RpcClient) Do(ctx context.Context, method, path string, reqBody interface{}, respOut interface{}) error {
+	return r.httpImplementer.(*Client).Do(ctx, method, path, reqBody, respOut)
+}
+
+// Ping list the databases by rpc to verify the server is reachable and the credentials are valid.
+func (r *RpcClient) Ping(ctx context.Context) error {
+	_, err := r.ListDatabase(ctx)
+	return err
+}
+
+func (r *RpcClient) schemaCache() (*schemaCache, string) {
+	return r.httpImplementer.(*Client).schemaCache()
+}
+
 func (r *RpcClient) Options() ClientOption {
-	return r.option
+	option := r.option
+	option.Timeout = time.Duration(atomic.LoadInt64(&r.timeout))
+	return option
 }
 
 func (r *RpcClient) WithTimeout(d time.Duration) {
 	r.httpImplementer.WithTimeout(d)
-	r.option.Timeout = d
+	atomic.StoreInt64(&r.timeout, int64(d))
 }
 
 func (r *RpcClient) Debug(v bool) {
 	r.httpImplementer.Debug(v)
-	r.debug = v
+	var debug int32
+	if v {
+		debug = 1
+	}
+	atomic.StoreInt32(&r.debug, debug)
 }
 
+// Close closes the client and its connections immediately, without waiting for the requests in flight,
+// and the requests after closed return ErrClientClosed, see Client.Close.
 func (r *RpcClient) Close() {
 	r.httpImplementer.Close()
 	r.cc.Close()
 }
 
-func (r *RpcClient) attachCtx(ctx context.Context) context.Context {
+// Shutdown closes the client as Close, but waits for the rpc and http requests in flight to finish before
+// closing the connections, see Client.Shutdown.
+func (r *RpcClient) Shutdown(ctx context.Context) error {
+	defer r.cc.Close()
+	return r.httpImplementer.(*Client).Shutdown(ctx)
+}
+
+func (r *RpcClient) attachCtx(ctx context.Context) (context.Context, context.CancelFunc) {
 	auth := fmt.Sprintf("Bearer account=%s&api_key=%s", r.username, r.key)
 	md := metadata.Pairs("authorization", auth)
-	attached, _ := context.WithTimeout(ctx, r.option.Timeout)
+	attached, cancel := ctx, context.CancelFunc(func() {})
+	if timeout := time.Duration(atomic.LoadInt64(&r.timeout)); timeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			attached, cancel = context.WithTimeout(ctx, timeout)
+		}
+	}
 	attached = metadata.NewOutgoingContext(attached, md)
-	return attached
+	return attached, cancel
+}
+
+// rpcReadMethods are the rpc methods limited by ClientOption.ReadRateLimit, the others are writes.
+var rpcReadMethods = map[string]bool{
+	"query":              true,
+	"search":             true,
+	"hybrid_search":      true,
+	"describeCollection": true,
+	"listCollections":    true,
+	"listDatabases":      true,
+	"getAlias":           true,
+	"get_version":        true,
 }
 
 func newInterceptor(client *RpcClient) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		ctx = client.attachCtx(ctx)
-		if client.debug {
-			log.Printf("[DEBUG] REQUEST, Method: %s, Content: %v", method, req)
+		httpc := client.httpImplementer.(*Client)
+		// the rpc requests in flight are tracked with the http requests, so Shutdown waits for both
+		if err := httpc.lifecycle.begin(); err != nil {
+			return err
+		}
+		defer httpc.lifecycle.end()
+		if err := httpc.checkFeature(ctx, rpcFeature(method, req)); err != nil {
+			return err
 		}
+		// the rpc requests share the rate limiters with the http requests of the client
+		limiter := httpc.writeLimiter
+		if rpcReadMethods[method[strings.LastIndex(method, "/")+1:]] {
+			limiter = httpc.readLimiter
+		}
+		if err := limiter.wait(ctx); err != nil {
+			return errors.Wrap(err, "wait for rate limit failed")
+		}
+		ctx, cancel := client.attachCtx(ctx)
+		defer cancel()
+		start := time.Now()
 		err := invoker(ctx, method, req, reply, cc, opts...)
+		if st, ok := status.FromError(err); ok && st.Code() == codes.ResourceExhausted {
+			// the same as the 429 of http, so errors.Is(err, ErrRateLimited) works for rpc
+			err = &APIError{HTTPStatus: http.StatusTooManyRequests, Message: st.Message(), RequestPath: method}
+		}
 		if codeGetter, ok := reply.(interface {
 			GetCode() int32
 			GetMsg() string
 		}); ok {
 			if codeGetter.GetCode() != 0 {
-				err = errors.Errorf("code: %d, message: %s", codeGetter.GetCode(), codeGetter.GetMsg())
+				err = &APIError{Code: codeGetter.GetCode(), Message: codeGetter.GetMsg(), RequestPath: method}
 			}
 		}
-		if client.debug {
+		if logger, verbose := clientLogger(client.option, atomic.LoadInt32(&client.debug) == 1); logger != nil {
+			kvs := []interface{}{"method", method, "duration", time.Since(start)}
+			if apiErr, ok := asAPIError(err); ok {
+				kvs = append(kvs, "code", apiErr.Code, "msg", apiErr.Message)
+			}
+			if verbose {
+				kvs = append(kvs, "request", req, "response", reply)
+			}
 			if err != nil {
-				log.Printf("[DEBUG] RESPONSE ERROR: %s", err.Error())
+				logger.Warn("vectordb rpc failed", append(kvs, "error", err.Error())...)
 			} else {
-				log.Printf("[DEBUG] RESPONSE: %v", reply)
+				logger.Debug("vectordb rpc", kvs...)
 			}
 		}
 		return err
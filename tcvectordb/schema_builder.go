@@ -0,0 +1,207 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"fmt"
+)
+
+// SchemaBuilder builds the indexes and embedding of a collection, and validates them before sending.
+// eg: NewSchema().AddPrimaryKey("id").AddVector("vector", 768, HNSW, COSINE, &HNSWParam{M: 16, EfConstruction: 200}).
+// AddFilter("author", String).Build()
+type SchemaBuilder struct {
+	indexes   Indexes
+	embedding *Embedding
+	// names the field names in order of adding, to reject the duplicate ones
+	names []string
+}
+
+func NewSchema() *SchemaBuilder {
+	return new(SchemaBuilder)
+}
+
+// NewSchemaFrom returns the builder of the schema of coll, such as the collection returned by DescribeCollection,
+// used to compare the schemas by Diff.
+func NewSchemaFrom(coll *Collection) *SchemaBuilder {
+	b := NewSchema()
+	for _, v := range coll.Indexes.FilterIndex {
+		b.add(v.FieldName)
+		b.indexes.FilterIndex = append(b.indexes.FilterIndex, v)
+	}
+	for _, v := range coll.Indexes.VectorIndex {
+		b.add(v.FieldName)
+		b.indexes.VectorIndex = append(b.indexes.VectorIndex, v)
+	}
+	for _, v := range coll.Indexes.SparseVectorIndex {
+		b.add(v.FieldName)
+		b.indexes.SparseVectorIndex = append(b.indexes.SparseVectorIndex, v)
+	}
+	for _, v := range coll.Indexes.BinaryVectorIndex {
+		b.add(v.FieldName)
+		b.indexes.BinaryVectorIndex = append(b.indexes.BinaryVectorIndex, v)
+	}
+	if coll.Embedding.Field != "" {
+		embedding := coll.Embedding
+		b.embedding = &embedding
+	}
+	return b
+}
+
+func (b *SchemaBuilder) add(name string) {
+	b.names = append(b.names, name)
+}
+
+// AddPrimaryKey add the string primary key.
+func (b *SchemaBuilder) AddPrimaryKey(name string) *SchemaBuilder {
+	b.add(name)
+	b.indexes.FilterIndex = append(b.indexes.FilterIndex, FilterIndex{FieldName: name, FieldType: String, IndexType: PRIMARY})
+	return b
+}
+
+// AddVector add the vector index, params could be nil to use the default params of server.
+func (b *SchemaBuilder) AddVector(name string, dimension uint32, indexType IndexType, metric MetricType, params IndexParams) *SchemaBuilder {
+	b.add(name)
+	b.indexes.VectorIndex = append(b.indexes.VectorIndex, VectorIndex{
+		FilterIndex: FilterIndex{FieldName: name, FieldType: Vector, IndexType: indexType},
+		Dimension:   dimension,
+		MetricType:  metric,
+		Params:      params,
+	})
+	return b
+}
+
+// AddSparseVector add the sparse vector index of SPARSE_INVERTED and IP.
+func (b *SchemaBuilder) AddSparseVector(name string) *SchemaBuilder {
+	b.add(name)
+	b.indexes.SparseVectorIndex = append(b.indexes.SparseVectorIndex, SparseVectorIndex{
+		FieldName: name, FieldType: SparseVector, IndexType: SPARSE_INVERTED, MetricType: IP,
+	})
+	return b
+}
+
+// AddBinaryVector add the binary vector index of BIN_FLAT and HAMMING, the dimension is the number of bits.
+func (b *SchemaBuilder) AddBinaryVector(name string, dimension uint32) *SchemaBuilder {
+	b.add(name)
+	b.indexes.BinaryVectorIndex = append(b.indexes.BinaryVectorIndex, BinaryVectorIndex{
+		FieldName: name, FieldType: BinaryVector, IndexType: BIN_FLAT, Dimension: dimension, MetricType: HAMMING,
+	})
+	return b
+}
+
+// AddFilter add the filter index, the array field is the array of string.
+func (b *SchemaBuilder) AddFilter(name string, fieldType FieldType) *SchemaBuilder {
+	index := FilterIndex{FieldName: name, FieldType: fieldType, IndexType: FILTER}
+	if fieldType == Array {
+		index.ElemType = String
+	}
+	b.add(name)
+	b.indexes.FilterIndex = append(b.indexes.FilterIndex, index)
+	return b
+}
+
+// WithEmbedding generate the vector of vectorField by model from the text of field.
+// The dimension of the vector index could be 0 to use the dimension of model.
+func (b *SchemaBuilder) WithEmbedding(field, vectorField string, model EmbeddingModel) *SchemaBuilder {
+	b.embedding = &Embedding{Field: field, VectorField: vectorField, ModelName: string(model)}
+	return b
+}
+
+// Build validates the schema and returns the indexes and embedding, the embedding is nil if it is not set.
+// It returns error if the field names are duplicate, there is not exactly one primary key or no vector index,
+// a vector dimension is 0, or the embedding does not match the vector index.
+func (b *SchemaBuilder) Build() (Indexes, *Embedding, error) {
+	indexes := b.indexes
+	indexes.VectorIndex = append([]VectorIndex(nil), b.indexes.VectorIndex...)
+	seen := make(map[string]bool, len(b.names))
+	for _, name := range b.names {
+		if name == "" {
+			return Indexes{}, nil, fmt.Errorf("the field name of index is empty")
+		}
+		if seen[name] {
+			return Indexes{}, nil, fmt.Errorf("the field %s has more than one index", name)
+		}
+		seen[name] = true
+	}
+	primaryKeys := 0
+	for _, v := range indexes.FilterIndex {
+		if v.IsPrimaryKey() {
+			primaryKeys++
+		}
+	}
+	if primaryKeys != 1 {
+		return Indexes{}, nil, fmt.Errorf("the schema must have exactly one primary key, got %d", primaryKeys)
+	}
+	if len(indexes.VectorIndex) == 0 && len(indexes.BinaryVectorIndex) == 0 {
+		return Indexes{}, nil, fmt.Errorf("the schema has no vector index")
+	}
+
+	var embedding *Embedding
+	if b.embedding != nil {
+		e := *b.embedding
+		embedding = &e
+		if e.Field == "" || e.VectorField == "" {
+			return Indexes{}, nil, fmt.Errorf("the field and vector field of embedding must be set")
+		}
+		for _, v := range indexes.VectorIndex {
+			// the text field could be a filter index, but not a vector
+			if v.FieldName == e.Field {
+				return Indexes{}, nil, fmt.Errorf("the embedding field %s is a vector index", e.Field)
+			}
+		}
+		found := false
+		for n, v := range indexes.VectorIndex {
+			if v.FieldName != e.VectorField {
+				continue
+			}
+			found = true
+			if v.Dimension == 0 {
+				indexes.VectorIndex[n].Dimension = EmbeddingModel(e.ModelName).Dimension()
+			}
+		}
+		if !found {
+			return Indexes{}, nil, fmt.Errorf("the embedding vector field %s is not a vector index", e.VectorField)
+		}
+	}
+	for _, v := range indexes.VectorIndex {
+		if v.Dimension == 0 {
+			return Indexes{}, nil, fmt.Errorf("the dimension of vector index %s is 0", v.FieldName)
+		}
+	}
+	for _, v := range indexes.BinaryVectorIndex {
+		if v.Dimension == 0 || v.Dimension%8 != 0 {
+			return Indexes{}, nil, fmt.Errorf("the dimension %d of binary vector index %s must be a positive multiple of 8", v.Dimension, v.FieldName)
+		}
+	}
+	if err := checkIndexParams(indexes.VectorIndex); err != nil {
+		return Indexes{}, nil, err
+	}
+	if err := checkMetricType(indexes); err != nil {
+		return Indexes{}, nil, err
+	}
+	if err := checkEmbeddingDimension(indexes, &CreateCollectionParams{Embedding: embedding}); err != nil {
+		return Indexes{}, nil, err
+	}
+	return indexes, embedding, nil
+}
+
+// Diff returns the differences of the indexes of other from the ones of b, empty if they are the same.
+// They are described as b is requested and other exists.
+func (b *SchemaBuilder) Diff(other *SchemaBuilder) []string {
+	return diffIndexes(b.indexes, other.indexes)
+}
@@ -0,0 +1,59 @@
+package tcvectordb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSchemaBuilder(t *testing.T) {
+	indexes, embedding, err := NewSchema().AddPrimaryKey("id").
+		AddVector("vector", 0, HNSW, COSINE, &HNSWParam{M: 16, EfConstruction: 200}).
+		AddFilter("author", String).AddFilter("tags", Array).
+		WithEmbedding("text", "vector", BGE_BASE_ZH).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indexes.FilterIndex) != 3 || indexes.FilterIndex[2].ElemType != String || indexes.VectorIndex[0].Dimension != 768 ||
+		embedding == nil || embedding.VectorField != "vector" {
+		t.Errorf("unexpected schema %+v, %+v", indexes, embedding)
+	}
+
+	cases := map[string]*SchemaBuilder{
+		"exactly one primary key": NewSchema().AddVector("vector", 3, HNSW, COSINE, nil),
+		"more than one index":     NewSchema().AddPrimaryKey("id").AddVector("vector", 3, HNSW, COSINE, nil).AddFilter("vector", String),
+		"dimension of vector":     NewSchema().AddPrimaryKey("id").AddVector("vector", 0, HNSW, COSINE, nil),
+		"no vector index":         NewSchema().AddPrimaryKey("id").AddFilter("page", Uint64),
+		"not a vector index":      NewSchema().AddPrimaryKey("id").AddVector("vector", 768, HNSW, COSINE, nil).WithEmbedding("text", "vec", BGE_BASE_ZH),
+		"does not match":          NewSchema().AddPrimaryKey("id").AddVector("vector", 3, HNSW, COSINE, nil).WithEmbedding("text", "vector", BGE_BASE_ZH),
+		"could not be used":       NewSchema().AddPrimaryKey("id").AddVector("vector", 3, IVF_FLAT, COSINE, &HNSWParam{}),
+		"multiple of 8":           NewSchema().AddPrimaryKey("id").AddBinaryVector("bits", 12),
+	}
+	for msg, b := range cases {
+		if _, _, err := b.Build(); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("expect error of %s, got %v", msg, err)
+		}
+	}
+
+	var body string
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"code":0}`))
+	}, ClientOption{})
+	schema := NewSchema().AddPrimaryKey("id").AddVector("vector", 3, HNSW, COSINE, nil).AddFilter("page", Uint64)
+	coll, err := cli.Database("db").CreateCollectionBySpec(context.Background(), CollectionSpec{Name: "coll", ShardNum: 1, Schema: schema})
+	if err != nil || !strings.Contains(body, `"fieldName":"page"`) {
+		t.Fatalf("expect collection created by schema, got %s, %v", body, err)
+	}
+
+	if diffs := schema.Diff(NewSchemaFrom(coll)); len(diffs) != 0 {
+		t.Errorf("expect the same schema, got %v", diffs)
+	}
+	staging := NewSchema().AddPrimaryKey("id").AddVector("vector", 3, HNSW, L2, nil)
+	if diffs := schema.Diff(staging); len(diffs) != 2 {
+		t.Errorf("expect diffs of vector and page, got %v", diffs)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCollectionSchemaTTL = time.Minute
+	// schemaCacheRetryInterval: the failed describe is not retried in the interval
+	schemaCacheRetryInterval = time.Second
+)
+
+// schemaCache caches the described collections by ClientOption.CacheCollectionSchema, it is shared
+// by the handles of the client and the copies of WithOptions.
+type schemaCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*schemaEntry
+}
+
+// schemaEntry is ready when done is closed, coll is nil if the describe failed.
+type schemaEntry struct {
+	done   chan struct{}
+	coll   *Collection
+	expire time.Time
+	// canceled: the describe failed as its ctx is done, the waiters describe it again with their own ctx
+	canceled bool
+}
+
+func newSchemaCache(ttl time.Duration) *schemaCache {
+	if ttl <= 0 {
+		ttl = defaultCollectionSchemaTTL
+	}
+	return &schemaCache{ttl: ttl, entries: make(map[string]*schemaEntry)}
+}
+
+// get returns the cached collection of key, or describes it if it is not cached or expired.
+// The concurrent gets of the same key wait for one describe, or until their ctx is done.
+func (s *schemaCache) get(ctx context.Context, key string, describe func(ctx context.Context) (*Collection, error)) *Collection {
+	for {
+		s.mu.Lock()
+		e, ok := s.entries[key]
+		if !ok {
+			break
+		}
+		ready := false
+		select {
+		case <-e.done:
+			ready = true
+		default:
+		}
+		if ready {
+			if time.Now().Before(e.expire) {
+				s.mu.Unlock()
+				return e.coll
+			}
+			// expired, described again with the lock held
+			break
+		}
+		s.mu.Unlock()
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil
+		}
+		if !e.canceled {
+			return e.coll
+		}
+	}
+	e := &schemaEntry{done: make(chan struct{})}
+	s.entries[key] = e
+	s.mu.Unlock()
+
+	coll, err := describe(ctx)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// the ctx of this get is done, the waiters and the next get describe it again
+		s.mu.Lock()
+		if s.entries[key] == e {
+			delete(s.entries, key)
+		}
+		s.mu.Unlock()
+		e.canceled = true
+	} else if err != nil {
+		ttl := s.ttl
+		if ttl > schemaCacheRetryInterval {
+			ttl = schemaCacheRetryInterval
+		}
+		e.expire = time.Now().Add(ttl)
+	} else {
+		e.coll = coll
+		e.expire = time.Now().Add(s.ttl)
+	}
+	close(e.done)
+	return e.coll
+}
+
+func (s *schemaCache) invalidate(key string) {
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+}
+
+// databaseSchemas is the view of schemaCache for the collections of a database handle.
+type databaseSchemas struct {
+	cache *schemaCache
+	// prefix: the namespace and name of database
+	prefix   string
+	describe func(ctx context.Context, name string) (*Collection, error)
+}
+
+// databaseSchemasOf returns nil if the schema cache of client is not enabled.
+func databaseSchemasOf(cli SdkClient, databaseName string) *databaseSchemas {
+	provider, ok := cli.(interface {
+		schemaCache() (*schemaCache, string)
+	})
+	if !ok {
+		return nil
+	}
+	cache, namespace := provider.schemaCache()
+	if cache == nil {
+		return nil
+	}
+	return &databaseSchemas{
+		cache:  cache,
+		prefix: namespace + NamespaceDelimiter + databaseName + "/",
+		describe: func(ctx context.Context, name string) (*Collection, error) {
+			db := (&implementerDatabase{SdkClient: cli}).Database(databaseName)
+			res, err := db.DescribeCollection(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			return &res.Collection, nil
+		},
+	}
+}
+
+// get returns the cached collection, nil if it could not be described with ctx.
+func (s *databaseSchemas) get(ctx context.Context, name string) *Collection {
+	return s.cache.get(ctx, s.prefix+name, func(ctx context.Context) (*Collection, error) {
+		return s.describe(ctx, name)
+	})
+}
+
+func (s *databaseSchemas) invalidate(name string) {
+	if s == nil {
+		return
+	}
+	s.cache.invalidate(s.prefix + name)
+}
+
+// InvalidateCollectionCache removes the cached schema of the collection, which is described again
+// by the next request. It does nothing if ClientOption.CacheCollectionSchema is not enabled.
+func (d *Database) InvalidateCollectionCache(name string) {
+	d.schemas.invalidate(name)
+}
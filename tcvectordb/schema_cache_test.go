@@ -0,0 +1,153 @@
+package tcvectordb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCollectionSchemaCache(t *testing.T) {
+	var describes, dimension int32 = 0, 2
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collection/describe":
+			atomic.AddInt32(&describes, 1)
+			fmt.Fprintf(w, `{"code":0,"collection":{"collection":"coll","indexes":[`+
+				`{"fieldName":"id","fieldType":"string","indexType":"primaryKey"},`+
+				`{"fieldName":"vector","fieldType":"vector","indexType":"HNSW","dimension":%d,"metricType":"COSINE"}]}}`,
+				atomic.LoadInt32(&dimension))
+		default:
+			w.Write([]byte(`{"code":0,"affectedCount":1}`))
+		}
+	}
+	cli := newTestClient(t, handler, ClientOption{CacheCollectionSchema: true})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for g := 0; g < 50; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 20; n++ {
+				coll := cli.Database("db").Collection("coll")
+				_, err := coll.Upsert(ctx, []Document{{Id: fmt.Sprintf("%d-%d", g, n), Vector: []float32{0.1, 0.2}}})
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&describes); n != 1 {
+		t.Fatalf("expect the collection described once, got %d", n)
+	}
+
+	// the collection is recreated with another dimension
+	atomic.StoreInt32(&dimension, 3)
+	coll := cli.Database("db").Collection("coll")
+	_, err := coll.Upsert(ctx, []Document{{Id: "a", Vector: []float32{0.1, 0.2, 0.3}}})
+	if err == nil || !strings.Contains(err.Error(), "invalidated") {
+		t.Fatalf("expect the cached schema invalidated, got %v", err)
+	}
+	if _, err = coll.Upsert(ctx, []Document{{Id: "a", Vector: []float32{0.1, 0.2, 0.3}}}); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&describes); n != 2 {
+		t.Fatalf("expect the collection described again, got %d", n)
+	}
+
+	db := cli.Database("db")
+	db.InvalidateCollectionCache("coll")
+	db.Collection("coll").Upsert(ctx, []Document{{Id: "a", Vector: []float32{0.1, 0.2, 0.3}}})
+	if n := atomic.LoadInt32(&describes); n != 3 {
+		t.Errorf("expect the collection described after invalidated, got %d", n)
+	}
+	db.Collection("other").Upsert(ctx, []Document{{Id: "a", Vector: []float32{0.1, 0.2, 0.3}}})
+	cli.Database("db2").Collection("coll").Upsert(ctx, []Document{{Id: "a", Vector: []float32{0.1, 0.2, 0.3}}})
+	if n := atomic.LoadInt32(&describes); n != 5 {
+		t.Errorf("expect each collection cached separately, got %d describes", n)
+	}
+
+	atomic.StoreInt32(&describes, 0)
+	cli = newTestClient(t, handler, ClientOption{CacheCollectionSchema: true, CollectionSchemaTTL: 10 * time.Millisecond})
+	cli.Database("db").Collection("coll").Upsert(ctx, []Document{{Id: "a", Vector: []float32{0.1, 0.2, 0.3}}})
+	time.Sleep(20 * time.Millisecond)
+	cli.Database("db").Collection("coll").Upsert(ctx, []Document{{Id: "a", Vector: []float32{0.1, 0.2, 0.3}}})
+	if n := atomic.LoadInt32(&describes); n != 2 {
+		t.Errorf("expect the collection described again after the ttl, got %d", n)
+	}
+
+	atomic.StoreInt32(&describes, 0)
+	cli = newTestClient(t, handler, ClientOption{})
+	cli.Database("db").Collection("coll").Upsert(ctx, []Document{{Id: "a", Vector: []float32{0.1, 0.2}}})
+	if n := atomic.LoadInt32(&describes); n != 0 {
+		t.Errorf("expect no describe without the cache, got %d", n)
+	}
+}
+
+func TestSchemaCacheDescribeWithCtx(t *testing.T) {
+	schemas := &databaseSchemas{
+		cache:  newSchemaCache(time.Minute),
+		prefix: "ns/db/",
+		describe: func(ctx context.Context, name string) (*Collection, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &Collection{CollectionName: name}, nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if coll := schemas.get(ctx, "coll"); coll != nil {
+		t.Errorf("expect no schema described with the canceled ctx, got %+v", coll)
+	}
+	if coll := schemas.get(context.Background(), "coll"); coll == nil || coll.CollectionName != "coll" {
+		t.Errorf("expect the schema described again after the canceled ctx, got %+v", coll)
+	}
+}
+
+func TestSchemaCacheWaiterRetriesCanceledDescribe(t *testing.T) {
+	var calls int32
+	started := make(chan struct{})
+	schemas := &databaseSchemas{
+		cache:  newSchemaCache(time.Minute),
+		prefix: "ns/db/",
+		describe: func(ctx context.Context, name string) (*Collection, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return &Collection{CollectionName: name}, nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan *Collection, 1)
+	go func() { canceled <- schemas.get(ctx, "coll") }()
+	<-started
+
+	waited := make(chan *Collection, 1)
+	go func() { waited <- schemas.get(context.Background(), "coll") }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if coll := <-canceled; coll != nil {
+		t.Errorf("expect no schema of the canceled get, got %+v", coll)
+	}
+	if coll := <-waited; coll == nil || coll.CollectionName != "coll" {
+		t.Errorf("expect the waiter described again with its own ctx, got %+v", coll)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("expect 2 describes, got %d", n)
+	}
+}
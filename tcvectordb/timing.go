@@ -0,0 +1,163 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestTiming the latency breakdown of a request, the phases are of its last attempt.
+type RequestTiming struct {
+	// Total: the client-observed duration, including the retries and their backoff
+	Total time.Duration
+	// Attempts: the number of attempts sent
+	Attempts int
+	// DNS, Connect, TLSHandshake: the phases of establishing the connection, zero if it is reused.
+	// They and FirstByte are only collected with ClientOption.CollectTimings.
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	// FirstByte: from sending the request to receiving the first byte of response
+	FirstByte  time.Duration
+	ConnReused bool
+	// Server: the largest duration reported by the server in the Server-Timing header, 0 if not reported
+	Server time.Duration
+}
+
+// RequestInfo the summary of a finished request, passed to ClientOption.OnRequestDone.
+type RequestInfo struct {
+	// RequestSpan: the operation, database, collection, sizes, status and error of the request
+	RequestSpan
+	Duration time.Duration
+	Timing   RequestTiming
+}
+
+type timingKey struct{}
+
+// withRequestTiming returns the ctx collecting the timing of the request sent with it.
+func withRequestTiming(ctx context.Context) (context.Context, *RequestTiming) {
+	timing := new(RequestTiming)
+	return context.WithValue(ctx, timingKey{}, timing), timing
+}
+
+// collectedTiming returns the timing if the request is sent by the client collecting timing, otherwise nil.
+func collectedTiming(timing *RequestTiming) *RequestTiming {
+	if timing.Attempts == 0 {
+		return nil
+	}
+	return timing
+}
+
+// attemptTiming collects the phases of an attempt by httptrace, the hooks of dialing may be called
+// after the attempt returns, so the phases are guarded by mu.
+type attemptTiming struct {
+	mu                                   sync.Mutex
+	start, dnsStart, connStart, tlsStart time.Time
+	timing                               RequestTiming
+}
+
+func newAttemptTiming() *attemptTiming {
+	return &attemptTiming{start: time.Now()}
+}
+
+func (a *attemptTiming) trace() *httptrace.ClientTrace {
+	since := func(start time.Time) time.Duration {
+		if start.IsZero() {
+			return 0
+		}
+		return time.Since(start)
+	}
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			a.mu.Lock()
+			a.dnsStart = time.Now()
+			a.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			a.mu.Lock()
+			a.timing.DNS = since(a.dnsStart)
+			a.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			a.mu.Lock()
+			a.connStart = time.Now()
+			a.mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			a.mu.Lock()
+			a.timing.Connect = since(a.connStart)
+			a.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			a.mu.Lock()
+			a.tlsStart = time.Now()
+			a.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			a.mu.Lock()
+			a.timing.TLSHandshake = since(a.tlsStart)
+			a.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			a.mu.Lock()
+			a.timing.ConnReused = info.Reused
+			a.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			a.mu.Lock()
+			a.timing.FirstByte = since(a.start)
+			a.mu.Unlock()
+		},
+	}
+}
+
+// finish copies the phases of the attempt into timing.
+func (a *attemptTiming) finish(timing *RequestTiming) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	timing.DNS, timing.Connect, timing.TLSHandshake = a.timing.DNS, a.timing.Connect, a.timing.TLSHandshake
+	timing.FirstByte, timing.ConnReused = a.timing.FirstByte, a.timing.ConnReused
+}
+
+// serverTiming returns the largest duration of the Server-Timing header, such as `db;dur=53, total;dur=100.2`.
+func serverTiming(header http.Header) time.Duration {
+	var largest float64
+	for _, value := range header.Values("Server-Timing") {
+		for _, metric := range strings.Split(value, ",") {
+			for _, param := range strings.Split(metric, ";") {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(param, "dur=") {
+					continue
+				}
+				ms, err := strconv.ParseFloat(strings.Trim(param[len("dur="):], `"`), 64)
+				if err == nil && ms > largest {
+					largest = ms
+				}
+			}
+		}
+	}
+	return time.Duration(largest * float64(time.Millisecond))
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"context"
+	"reflect"
+)
+
+// Tracer traces the requests of client, module github.com/tencent/vectordatabase-sdk-go/tcvdbotel
+// provides the OpenTelemetry one, so the core package does not depend on OpenTelemetry.
+type Tracer interface {
+	// Start is called before the request is sent, the returned ctx is used to send the request,
+	// and end is called after the request returns, with the result filled in the span.
+	Start(ctx context.Context, span *RequestSpan) (_ context.Context, end func(span *RequestSpan))
+}
+
+// RequestSpan the attributes of a traced request, one span covers all the retries of a request.
+type RequestSpan struct {
+	// Operation: the api path, such as /document/search
+	Operation string
+	// Method: the http method
+	Method     string
+	Database   string
+	Collection string
+	// RequestSize: the size of the uncompressed request body
+	RequestSize int
+	// Status: the http status of the last attempt, 0 if no response is received
+	Status int
+	// Code: the code of the response body, or of the APIError
+	Code int32
+	// ResponseSize: the size of the uncompressed response body of the last attempt
+	ResponseSize int
+	// Err: the error returned by the request
+	Err error
+}
+
+// newRequestSpan returns the span of request, taking database and collection from the fields of req.
+func newRequestSpan(method, path string, req interface{}, requestSize int) *RequestSpan {
+	span := &RequestSpan{Operation: path, Method: method, RequestSize: requestSize}
+	v := reflect.Indirect(reflect.ValueOf(req))
+	if v.Kind() != reflect.Struct {
+		return span
+	}
+	if f := v.FieldByName("Database"); f.Kind() == reflect.String {
+		span.Database = f.String()
+	}
+	if f := v.FieldByName("Collection"); f.Kind() == reflect.String {
+		span.Collection = f.String()
+	}
+	return span
+}
+
+// finish fills the result of the request into span.
+func (s *RequestSpan) finish(err error) {
+	s.Err = err
+	if apiErr, ok := asAPIError(err); ok {
+		s.Code = apiErr.Code
+		if apiErr.HTTPStatus != 0 {
+			s.Status = apiErr.HTTPStatus
+		}
+	}
+}
@@ -5,6 +5,8 @@ type VDBCLient struct {
 	DatabaseInterface
 	FlatInterface
 	FlatIndexInterface
+	UserInterface
+	BackupInterface
 
 	cli SdkClient
 }
@@ -27,5 +29,7 @@ func NewVDBClient(cli SdkClient) *VDBCLient {
 		DatabaseInterface:  databaseImpl,
 		FlatInterface:      flatImpl,
 		FlatIndexInterface: flatIndexImpl,
+		UserInterface:      &implementerUser{SdkClient: cli},
+		BackupInterface:    &implementerBackup{SdkClient: cli},
 	}
 }
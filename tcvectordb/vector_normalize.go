@@ -0,0 +1,76 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tcvectordb
+
+import (
+	"fmt"
+
+	"github.com/tencent/vectordatabase-sdk-go/tcvectordb/vectorutil"
+)
+
+// autoNormalize reports whether the vectors of the collection are normalized by ClientOption.AutoNormalize.
+func autoNormalize(option ClientOption, coll *Collection) bool {
+	return option.AutoNormalize && len(coll.Indexes.VectorIndex) != 0 && coll.Indexes.VectorIndex[0].MetricType == COSINE
+}
+
+// normalizeDocuments returns a copy of documents with the vectors normalized, and the ids of the documents
+// whose vectors are zero and left unchanged. The documents passed in are not modified.
+func normalizeDocuments(documents interface{}) (interface{}, []string) {
+	var zeros []string
+	switch docs := documents.(type) {
+	case []Document:
+		normalized := append([]Document(nil), docs...)
+		for n := range normalized {
+			if len(normalized[n].Vector) == 0 {
+				continue
+			}
+			var ok bool
+			if normalized[n].Vector, ok = vectorutil.NormalizedL2(normalized[n].Vector); !ok {
+				zeros = append(zeros, normalized[n].Id)
+			}
+		}
+		return normalized, zeros
+	case []map[string]interface{}:
+		normalized := append([]map[string]interface{}(nil), docs...)
+		for n, doc := range docs {
+			vector, ok := doc["vector"].([]float32)
+			if !ok || len(vector) == 0 {
+				continue
+			}
+			copied := make(map[string]interface{}, len(doc))
+			for k, v := range doc {
+				copied[k] = v
+			}
+			if copied["vector"], ok = vectorutil.NormalizedL2(vector); !ok {
+				zeros = append(zeros, fmt.Sprint(doc["id"]))
+			}
+			normalized[n] = copied
+		}
+		return normalized, zeros
+	}
+	return documents, nil
+}
+
+// zeroVectorError returns the ZeroVectorError of the zero vectors if the request succeeded, otherwise err.
+func zeroVectorError(err error, documentIds []string, vectors []int) error {
+	if err != nil || len(documentIds) == 0 && len(vectors) == 0 {
+		return err
+	}
+	return &ZeroVectorError{DocumentIds: documentIds, Vectors: vectors}
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2023 Tencent Cloud.
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the vectordb-sdk-java), to
+// deal in the Software without restriction, including without limitation the
+// rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is furnished
+// to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED,
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A
+// PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE
+// SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package vectorutil provides the helpers to prepare the vectors before upserting or searching them,
+// such as normalizing them for the COSINE metric.
+package vectorutil
+
+import "math"
+
+// NormalizeL2 scales v in place to the unit L2 norm. It returns false and leaves v unchanged
+// if v is a zero vector, which could not be normalized.
+func NormalizeL2(v []float32) bool {
+	scale, ok := l2Scale(v)
+	if !ok {
+		return false
+	}
+	for i := range v {
+		v[i] *= scale
+	}
+	return true
+}
+
+// NormalizedL2 returns a copy of v scaled to the unit L2 norm, v is not modified. It returns false
+// and an unscaled copy if v is a zero vector.
+func NormalizedL2(v []float32) ([]float32, bool) {
+	normalized := make([]float32, len(v))
+	scale, ok := l2Scale(v)
+	if !ok {
+		copy(normalized, v)
+		return normalized, false
+	}
+	normalized = normalized[:len(v)]
+	for i, x := range v {
+		normalized[i] = x * scale
+	}
+	return normalized, true
+}
+
+// NormalizeL2Batch scales each vector of vs in place to the unit L2 norm, and returns the indexes
+// of the zero vectors left unchanged.
+func NormalizeL2Batch(vs [][]float32) (zeros []int) {
+	for i, v := range vs {
+		if !NormalizeL2(v) {
+			zeros = append(zeros, i)
+		}
+	}
+	return zeros
+}
+
+// NormalizedL2Batch returns the copies of vectors of vs scaled to the unit L2 norm, and the indexes
+// of the zero vectors copied unscaled. vs is not modified.
+func NormalizedL2Batch(vs [][]float32) (normalized [][]float32, zeros []int) {
+	normalized = make([][]float32, len(vs))
+	for i, v := range vs {
+		var ok bool
+		if normalized[i], ok = NormalizedL2(v); !ok {
+			zeros = append(zeros, i)
+		}
+	}
+	return normalized, zeros
+}
+
+// l2Scale returns the reciprocal of the L2 norm of v, or false if the norm is 0.
+// The sum of squares is accumulated in float64 to keep the precision of long vectors.
+// The scale is 1 if the norm is NaN or Inf, so v is left unchanged for the server to reject it.
+func l2Scale(v []float32) (float32, bool) {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return 0, false
+	}
+	if math.IsNaN(sum) || math.IsInf(sum, 0) {
+		return 1, true
+	}
+	return float32(1 / math.Sqrt(sum)), true
+}
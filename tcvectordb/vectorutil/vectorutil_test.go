@@ -0,0 +1,61 @@
+package vectorutil
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeL2(t *testing.T) {
+	v := []float32{3, 4}
+	if !NormalizeL2(v) || v[0] != 0.6 || v[1] != 0.8 {
+		t.Errorf("unexpected normalized vector %v", v)
+	}
+	zero := []float32{0, 0}
+	if NormalizeL2(zero) || zero[0] != 0 || zero[1] != 0 {
+		t.Errorf("expect zero vector unchanged, got %v", zero)
+	}
+	inf := []float32{float32(math.Inf(1)), 1}
+	if !NormalizeL2(inf) || !math.IsInf(float64(inf[0]), 1) || inf[1] != 1 {
+		t.Errorf("expect the vector with Inf unchanged, got %v", inf)
+	}
+
+	v = []float32{1, 2, 2}
+	normalized, ok := NormalizedL2(v)
+	if !ok || v[0] != 1 || math.Abs(float64(normalized[2])-2.0/3) > 1e-6 {
+		t.Errorf("unexpected normalized copy %v of %v", normalized, v)
+	}
+
+	vs := [][]float32{{0, 5}, {0, 0}, {2, 0}}
+	copies, zeros := NormalizedL2Batch(vs)
+	if len(zeros) != 1 || zeros[0] != 1 || copies[0][1] != 1 || copies[2][0] != 1 || vs[0][1] != 5 {
+		t.Errorf("unexpected normalized copies %v, zeros %v", copies, zeros)
+	}
+	if zeros = NormalizeL2Batch(vs); len(zeros) != 1 || vs[0][1] != 1 || vs[2][0] != 1 {
+		t.Errorf("unexpected normalized vectors %v, zeros %v", vs, zeros)
+	}
+}
+
+func benchmarkVector() []float32 {
+	v := make([]float32, 768)
+	for i := range v {
+		v[i] = float32(i%17) - 8
+	}
+	return v
+}
+
+func BenchmarkNormalizeL2(b *testing.B) {
+	v := benchmarkVector()
+	b.SetBytes(int64(len(v) * 4))
+	for i := 0; i < b.N; i++ {
+		NormalizeL2(v)
+	}
+}
+
+func BenchmarkNormalizedL2(b *testing.B) {
+	v := benchmarkVector()
+	b.SetBytes(int64(len(v) * 4))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NormalizedL2(v)
+	}
+}
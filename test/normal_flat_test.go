@@ -169,6 +169,12 @@ func TestFlatQuery(t *testing.T) {
 	}
 }
 
+func TestFlatCount(t *testing.T) {
+	result, err := cli.Count(ctx, database, collectionName, nil)
+	printErr(err)
+	log.Printf("count doc: %d", result.Count)
+}
+
 func TestFlatSearchById(t *testing.T) {
 	filter := tcvectordb.NewFilter(`bookName="三国演义"`)
 	documentId := []string{"0003"}
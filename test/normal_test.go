@@ -295,6 +295,26 @@ func TestQuery(t *testing.T) {
 	}
 }
 
+func TestQueryIterator(t *testing.T) {
+	col := cli.Database(database).Collection(collectionName)
+	it := col.QueryIterator(ctx, nil, 2, &tcvectordb.QueryDocumentParams{OutputFields: []string{"id", "bookName"}})
+	for !it.Done() {
+		docs, err := it.Next()
+		printErr(err)
+		for _, doc := range docs {
+			log.Printf("document: %+v", ToJson(doc))
+		}
+	}
+	log.Printf("total doc: %d", it.Total())
+}
+
+func TestCount(t *testing.T) {
+	col := cli.Database(database).Collection(collectionName)
+	result, err := col.Count(ctx, tcvectordb.NewFilter(`bookName="三国演义"`))
+	printErr(err)
+	log.Printf("count doc: %d", result.Count)
+}
+
 func TestSearch(t *testing.T) {
 	col := cli.Database(database).Collection(collectionName)
 
@@ -408,6 +428,20 @@ func TestTruncateCollection(t *testing.T) {
 	printErr(err)
 }
 
+func TestTruncateCollectionKeepAlias(t *testing.T) {
+	db := cli.Database(database)
+	_, err := db.SetAlias(ctx, collectionName, collectionAlias)
+	printErr(err)
+
+	result, err := cli.TruncateCollection(ctx, database, collectionName)
+	printErr(err)
+	log.Printf("truncate collection result: %+v", result)
+
+	coll, err := db.DescribeCollection(ctx, collectionAlias)
+	printErr(err)
+	log.Printf("alias %s still resolves to collection %s, documentCount: %d", collectionAlias, coll.CollectionName, coll.DocumentCount)
+}
+
 func TestJson(t *testing.T) {
 	strT := "{\"databaseName\":\"go-sdk-test-db\",\"shardNum\":1846430633467240448}"
 	temp := make(map[string]interface{}, 0)
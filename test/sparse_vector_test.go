@@ -336,3 +336,35 @@ func TestHybridSearchWithSparseVec(t *testing.T) {
 		}
 	}
 }
+
+func TestHybridSearchWithText(t *testing.T) {
+	col := cli.Database(database).Collection(embedCollWithSparseVec)
+
+	bm25, err := encoder.NewBM25Encoder(&encoder.BM25EncoderParams{Bm25Language: "zh"})
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	sparseVec, err := bm25.EncodeQuery("刘玄德")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	limit := 3
+	searchRes, err := col.HybridSearch(ctx, tcvectordb.HybridSearchDocumentParams{
+		AnnParams: []*tcvectordb.AnnParam{{Data: "刘玄德"}},
+		Match:     []*tcvectordb.MatchOption{{Data: sparseVec}},
+		Rerank: &tcvectordb.RerankOption{
+			Method: tcvectordb.RerankRrf,
+			RrfK:   60,
+		},
+		Limit:        &limit,
+		OutputFields: []string{"id", "segment"},
+	})
+	printErr(err)
+	for i, docs := range searchRes.Documents {
+		log.Printf("doc %d result: ", i)
+		for _, doc := range docs {
+			log.Printf("document: %+v", doc)
+		}
+	}
+}